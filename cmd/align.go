@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/matcornic/subify/common/utils"
+	"github.com/matcornic/subify/subtitles/format"
+	"github.com/matcornic/subify/subtitles/synchro"
+	"github.com/spf13/cobra"
+)
+
+var alignEdit editFlags
+var alignReference string
+
+// alignCmd represents the align command
+var alignCmd = &cobra.Command{
+	Use:   "align <subtitle-path> --reference <synced-subtitle-path>",
+	Short: "Synchronize a subtitle with another one - 'subify align --help'",
+	Long: `Synchronize a subtitle with a well synchronized one, usually in another language or for another release.
+The offset and the drift (different frame rates) are found by matching the timing of the cues: durations, gaps and order.
+The subtitle is edited in place and the original is kept as a backup, unless --output is given.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 || alignReference == "" {
+			utils.Exit("Subtitle file and reference needed. See usage : 'subify help' or 'subify align --help'")
+		}
+		s, f := readSubtitle(args[0], &alignEdit)
+		reference, _, err := format.ReadFile(alignReference, format.Options{})
+		if err != nil {
+			utils.ExitPrintError(err, "Sadly, we could not read the reference subtitle %v", alignReference)
+		}
+
+		r, err := synchro.Align(synchro.Cues(s), synchro.Cues(reference))
+		if err != nil {
+			utils.ExitPrintError(err, "Sadly, we could not align the subtitle")
+		}
+		fmt.Printf("%v of %v cues matched (%.0f%% overlap): speed x%.5f, offset %v\n", r.Matched, len(s.Cues), r.Score*100, r.Scale, r.Offset.Round(time.Millisecond))
+		if r.Score < 0.3 {
+			utils.Exit("The subtitles are too different to be aligned. Nothing has been changed")
+		}
+
+		s.Transform(r.Scale, r.Offset, nil)
+		path := savePatched(args[0], s, f, &alignEdit)
+		fmt.Println("Subtitle aligned and saved to", path)
+	},
+}
+
+func init() {
+	alignCmd.Flags().StringVarP(&alignReference, "reference", "r", "", "Path of a well synchronized subtitle of the video")
+	addEditFlags(alignCmd, &alignEdit)
+	RootCmd.AddCommand(alignCmd)
+}
@@ -22,10 +22,11 @@ import (
 // apisCmd represents the apis command
 var apisCmd = &cobra.Command{
 	Use:   "apis",
-	Short: "List the available apis used by Subify",
-	Long:  `List the available apis used by Subify`,
+	Short: "List the available apis used by Subify, and their capabilities",
+	Long: `List the available apis used by Subify, and their capabilities
+Provider plugins (executables named subify-provider-*) are found in $HOME/.subify/plugins and in the PATH`,
 	Run: func(cmd *cobra.Command, args []string) {
-		subtitles.DefaultAPIs.Print()
+		subtitles.APIs.All().Print()
 	},
 }
 
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/matcornic/subify/common/utils"
+	"github.com/matcornic/subify/subtitles/synchro"
+	"github.com/spf13/cobra"
+	logger "github.com/spf13/jwalterweatherman"
+)
+
+var autosyncEdit editFlags
+
+// autosyncCmd represents the autosync command
+var autosyncCmd = &cobra.Command{
+	Use:   "autosync <video-path> <subtitle-path>",
+	Short: "Synchronize a subtitle with the audio of the video - 'subify autosync --help'",
+	Long: `Synchronize a subtitle with the speech of the video: the offset and the drift (different frame rates)
+lining up the cues with the speech are applied to the subtitle.
+The audio of the video is read with ffmpeg, which must be in the PATH. A WAV file can be given instead of the video.
+The subtitle is edited in place and the original is kept as a backup, unless --output is given.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 2 {
+			utils.Exit("Video and subtitle files needed. See usage : 'subify help' or 'subify autosync --help'")
+		}
+		s, f := readSubtitle(args[1], &autosyncEdit)
+
+		utils.VerbosePrintln(logger.INFO, "Detecting speech in "+args[0])
+		speech, err := synchro.Speech(args[0])
+		if err != nil {
+			utils.ExitPrintError(err, "Sadly, we could not read the audio of %v", args[0])
+		}
+		utils.VerbosePrintln(logger.INFO, fmt.Sprintf("%v speech segments found", len(speech)))
+
+		r, err := synchro.AlignAudio(synchro.Cues(s), speech)
+		if err != nil {
+			utils.ExitPrintError(err, "Sadly, we could not synchronize the subtitle")
+		}
+		fmt.Printf("%v of %v cues over speech (%.0f%% overlap): speed x%.5f, offset %v\n", r.Matched, len(s.Cues), r.Score*100, r.Scale, r.Offset.Round(time.Millisecond))
+		if r.Score < 0.5 {
+			utils.Exit("The subtitle does not match the speech of the video. Nothing has been changed")
+		}
+
+		s.Transform(r.Scale, r.Offset, nil)
+		path := savePatched(args[1], s, f, &autosyncEdit)
+		fmt.Println("Subtitle synchronized and saved to", path)
+	},
+}
+
+func init() {
+	addEditFlags(autosyncCmd, &autosyncEdit)
+	RootCmd.AddCommand(autosyncCmd)
+}
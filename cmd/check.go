@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/matcornic/subify/common/utils"
+	"github.com/matcornic/subify/subtitles/format"
+	"github.com/matcornic/subify/subtitles/quality"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var checkEdit editFlags
+var checkRules = quality.DefaultRules
+var checkJSON bool
+
+// checkCmd represents the check command
+var checkCmd = &cobra.Command{
+	Use:   "check <subtitle-path>",
+	Short: "Report the defects of a subtitle - 'subify check --help'",
+	Long: `Report the defects of a subtitle: overlapping cues, zero-length cues, cues displayed too short or too fast to be read,
+lines too long, too many lines and broken numbering. Reading speed statistics (characters per second) are given too.
+Use 'subify fix' to apply the safe corrections.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			utils.Exit("Subtitle file needed. See usage : 'subify help' or 'subify check --help'")
+		}
+		s, _ := readSubtitle(args[0], &checkEdit)
+		report := quality.Check(s, checkRules)
+
+		if checkJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(report); err != nil {
+				utils.ExitPrintError(err, "Sadly, we could not print the report")
+			}
+			return
+		}
+		if len(report.Issues) > 0 {
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetHeader([]string{"Cue", "Start", "Issue", "Details"})
+			table.SetAutoWrapText(false)
+			for _, i := range report.Issues {
+				table.Append([]string{fmt.Sprint(i.Cue), format.FormatTimestamp(i.Start, ","), string(i.Kind), i.Message})
+			}
+			table.Render()
+		}
+		st := report.Stats
+		fmt.Printf("%v issues in %v cues. Reading speed: %.1f CPS on average, %.1f median, %.1f max, %v cues faster than %v CPS\n",
+			len(report.Issues), st.Cues, st.MeanCPS, st.MedianCPS, st.MaxCPS, st.TooFast, checkRules.MaxCPS)
+	},
+}
+
+// addRuleFlags adds the flags changing the limits of a readable subtitle
+func addRuleFlags(cmd *cobra.Command, rules *quality.Rules) {
+	cmd.Flags().Float64Var(&rules.MaxCPS, "max-cps", rules.MaxCPS, "Maximum reading speed, in characters per second")
+	cmd.Flags().IntVar(&rules.MaxLineLength, "max-line-length", rules.MaxLineLength, "Maximum number of characters in a line")
+	cmd.Flags().IntVar(&rules.MaxLines, "max-lines", rules.MaxLines, "Maximum number of lines in a cue")
+	cmd.Flags().DurationVar(&rules.MinDuration, "min-duration", rules.MinDuration, "Minimum display duration of a cue")
+}
+
+func init() {
+	checkCmd.Flags().BoolVar(&checkJSON, "json", false, "Print the report as JSON")
+	checkCmd.Flags().Float64Var(&checkEdit.frameRate, "fps", 0, "Frame rate of the video, for MicroDVD subtitles. Read from the subtitle or 23.976 by default")
+	addRuleFlags(checkCmd, &checkRules)
+	RootCmd.AddCommand(checkCmd)
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/matcornic/subify/common/config"
+	"github.com/matcornic/subify/common/utils"
+	"github.com/matcornic/subify/subtitles/clean"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var cleanEdit editFlags
+var cleanPatterns []string
+var cleanHearingImpaired bool
+
+// cleanCmd represents the clean command
+var cleanCmd = &cobra.Command{
+	Use:   "clean <subtitle-path>",
+	Short: "Remove the ads and credits of a subtitle - 'subify clean --help'",
+	Long: `Remove the ads, credits and uploader spam ("Subtitles by...", "Visit www...") of a subtitle.
+Only the first and last cues are looked at, to avoid removing the dialogues. The built-in patterns can be extended
+with --pattern, or with the patterns of the [clean] section of the configuration.
+With --hi, the annotations for the hearing impaired are removed too ([DOOR CLOSES], (laughs), SPEAKER: and music notes),
+turning a SDH subtitle into a regular one.
+The subtitle is edited in place and the original is kept as a backup, unless --output is given.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			utils.Exit("Subtitle file needed. See usage : 'subify help' or 'subify clean --help'")
+		}
+		s, f := readSubtitle(args[0], &cleanEdit)
+		removed, err := clean.Clean(s, clean.Options{
+			Patterns: append(config.CleanPatterns, cleanPatterns...),
+			Cues:     config.CleanCues,
+		})
+		if err != nil {
+			utils.ExitPrintError(err, "Sadly, we could not clean the subtitle")
+		}
+		for _, l := range removed {
+			fmt.Println("Removed:", l)
+		}
+		annotated := 0
+		if cleanHearingImpaired {
+			annotated = clean.HearingImpaired(s)
+			fmt.Println(annotated, "cues cleaned of hearing impaired annotations")
+		}
+		if len(removed) == 0 && annotated == 0 {
+			fmt.Println("Nothing to clean")
+			return
+		}
+		path := savePatched(args[0], s, f, &cleanEdit)
+		fmt.Println("Subtitle cleaned and saved to", path)
+	},
+}
+
+func init() {
+	cleanCmd.Flags().StringArrayVarP(&cleanPatterns, "pattern", "p", nil, "Regular expression of the lines to remove, added to the built-in ones. Can be repeated")
+	cleanCmd.Flags().BoolVar(&cleanHearingImpaired, "hi", false, "Remove the annotations for the hearing impaired too")
+	cleanCmd.Flags().Int("cues", clean.DefaultCues, "Number of cues looked at, at the start and at the end of the subtitle")
+	_ = viper.BindPFlag("clean.cues", cleanCmd.Flags().Lookup("cues"))
+	addEditFlags(cleanCmd, &cleanEdit)
+	RootCmd.AddCommand(cleanCmd)
+}
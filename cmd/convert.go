@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/matcornic/subify/common/utils"
+	"github.com/matcornic/subify/subtitles/format"
+	"github.com/spf13/cobra"
+)
+
+var convertTo string
+var convertOutput string
+var convertFrameRate float64
+
+// convertCmd represents the convert command
+var convertCmd = &cobra.Command{
+	Use:   "convert <subtitle-path>",
+	Short: "Convert a subtitle to another format - 'subify convert --help'",
+	Long: `Convert a subtitle to another format: srt, vtt, ass, ssa, sub (MicroDVD), subviewer, ttml or sbv.
+The format of the given subtitle is guessed from its extension and content.
+Styling (italic, bold, colors, position) is kept when the target format supports it.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			utils.Exit("Subtitle file needed. See usage : 'subify help' or 'subify convert --help'")
+		}
+		to, err := format.ParseFormat(convertTo)
+		if err != nil {
+			utils.ExitPrintError(err, "Available formats are srt, vtt, ass, ssa, sub, subviewer, ttml and sbv")
+		}
+		output := convertOutput
+		if output == "" {
+			output = format.ConvertedPath(args[0], to)
+		}
+		if output == args[0] {
+			if from, err := format.DetectFile(args[0]); err == nil && from != to {
+				utils.Exit("MicroDVD and SubViewer subtitles share the .sub extension, give another output with --output")
+			}
+			utils.Exit("The subtitle is already a %v file, give another output with --output", to)
+		}
+		err = format.Convert(args[0], output, to, format.Options{FrameRate: convertFrameRate})
+		if err != nil {
+			utils.ExitPrintError(err, "Sadly, we could not convert %v", args[0])
+		}
+		fmt.Println("Subtitle converted to", output)
+	},
+}
+
+func init() {
+	convertCmd.Flags().StringVarP(&convertTo, "to", "t", "srt", "Format to convert to (srt, vtt, ass, ssa, sub, subviewer, ttml, sbv)")
+	convertCmd.Flags().StringVarP(&convertOutput, "output", "o", "", "Path of the converted subtitle. Next to the original one by default")
+	convertCmd.Flags().Float64Var(&convertFrameRate, "fps", 0, "Frame rate of the video, for MicroDVD subtitles. Read from the subtitle or 23.976 by default")
+	RootCmd.AddCommand(convertCmd)
+}
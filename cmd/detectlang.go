@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/matcornic/subify/common/utils"
+	"github.com/matcornic/subify/subtitles"
+	"github.com/spf13/cobra"
+)
+
+var detectLangDryRun bool
+
+// detectLangCmd represents the detect-lang command
+var detectLangCmd = &cobra.Command{
+	Use:   "detect-lang <subtitle-path>...",
+	Short: "Identify the language of subtitles - 'subify detect-lang --help'",
+	Long: `Identify the language of subtitles from their text, without network.
+Subtitles without language in their name are renamed with the identified one (Movie.srt to Movie.en.srt),
+so that players and media servers find it, unless it can't be told apart from close languages (Croatian and Serbian). Subtitles named with another language than their text are reported.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			utils.Exit("Subtitle files needed. See usage : 'subify help' or 'subify detect-lang --help'")
+		}
+		failed := false
+		for _, path := range args {
+			language, err := subtitles.DetectLanguage(path)
+			if err != nil {
+				fmt.Println(path+":", err)
+				failed = true
+				continue
+			}
+			if named := subtitles.LanguageFromPath(path); named != nil {
+				if !subtitles.NamedAs(*language, *named) {
+					fmt.Println(path+":", language.Description, "but named as", named.Description)
+				} else {
+					fmt.Println(path+":", named.Description)
+				}
+				continue
+			}
+
+			if close := subtitles.CloseLanguages(*language); len(close) > 0 {
+				fmt.Println(path+":", strings.Join(close.GetDescriptions(), ", "), "can't be told apart, not renamed. Name it with its language")
+				continue
+			}
+			tagged := subtitles.TaggedPath(path, *language)
+			switch {
+			case detectLangDryRun:
+				fmt.Println(path+":", language.Description+", would be renamed to", tagged)
+			case exists(tagged):
+				fmt.Println(path+":", language.Description+", not renamed as", tagged, "already exists")
+			default:
+				if err := os.Rename(path, tagged); err != nil {
+					utils.ExitPrintError(err, "Sadly, we could not rename %v", path)
+				}
+				fmt.Println(path+":", language.Description+", renamed to", tagged)
+			}
+		}
+		if failed {
+			os.Exit(1)
+		}
+	},
+}
+
+// exists tells if a file exists
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func init() {
+	detectLangCmd.Flags().BoolVarP(&detectLangDryRun, "dry-run", "n", false, "Only print the languages, without renaming the subtitles")
+	RootCmd.AddCommand(detectLangCmd)
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"strings"
 
+	"github.com/matcornic/subify/common/config"
 	"github.com/matcornic/subify/common/utils"
 	"github.com/matcornic/subify/subtitles"
 	"github.com/skratchdot/open-golang/open"
@@ -15,6 +16,10 @@ var openVideo bool
 
 var notify bool
 
+var dual string
+
+var force bool
+
 // dlCmd represents the dl command
 var dlCmd = &cobra.Command{
 	Use:     "dl <video-path>",
@@ -29,9 +34,29 @@ Give the path of your video as first parameter and let's go !`,
 		videoPath := args[0]
 		utils.VerbosePrintln(logger.INFO, "Given video file is "+videoPath)
 
+		hi, err := subtitles.ParseHearingImpaired(viper.GetString("download.hearing_impaired"))
+		if err != nil {
+			utils.ExitPrintError(err, "The hearing impaired preference is invalid")
+		}
+		config.HearingImpaired = string(hi)
+
 		apis := strings.Split(viper.GetString("download.apis"), ",")
 		languages := strings.Split(viper.GetString("download.languages"), ",")
-		err := subtitles.Download(videoPath, apis, languages, notify)
+		opts := subtitles.Options{
+			Notify:        notify,
+			Format:        viper.GetString("download.format"),
+			BOM:           viper.GetBool("download.bom"),
+			Clean:         viper.GetBool("download.clean"),
+			Script:        viper.GetString("download.script"),
+			Force:         force,
+			Forced:        viper.GetBool("download.forced"),
+			CheckLanguage: viper.GetBool("download.check_language"),
+		}
+		if dual != "" {
+			err = subtitles.DownloadDual(videoPath, apis, utils.SplitList(dual), opts)
+		} else {
+			err = subtitles.Download(videoPath, apis, languages, opts)
+		}
 		if err != nil {
 			utils.ExitPrintError(err, "Sadly, we could not download any subtitle for you. Try another time or contribute to the apis. See 'subify upload -h'")
 		}
@@ -51,8 +76,24 @@ func init() {
 		"Once the subtitle is downloaded, open the video with your default video player"+
 			` (OSX: "open", Windows: "start", Linux/Other: "xdg-open")`)
 	dlCmd.Flags().BoolVarP(&notify, "notify", "n", true, "Display desktop notification")
+	dlCmd.Flags().StringP("format", "f", "", "Convert the downloaded subtitle to this format (srt, vtt, ass, ssa, sub, subviewer, ttml, sbv). Keeps the original format by default")
+	dlCmd.Flags().Bool("bom", false, "Save the subtitle in UTF-8 with a byte order mark (BOM), for players needing it")
+	dlCmd.Flags().BoolVar(&force, "force", false, "Download the subtitle even when the language is already embedded in the video")
+	dlCmd.Flags().StringVar(&dual, "dual", "", "Download the subtitles in two languages, like en,fr, and merge them to display both at once. Merged as ASS, unless --format is given")
+	dlCmd.Flags().Bool("forced", false, "Download the forced subtitle, only translating the foreign parts of the video. Saved as Movie.en.forced.srt")
+	dlCmd.Flags().String("hearing-impaired", "", "Preference for hearing impaired (SDH) subtitles: prefer, avoid or require. No preference by default")
+	dlCmd.Flags().Bool("clean", false, "Remove the ads and credits at the start and the end of the subtitle. See 'subify clean --help'")
+	dlCmd.Flags().Bool("check-language", true, "Check the language of the text of the downloaded subtitle, and try another one when it is mislabeled")
+	dlCmd.Flags().String("script", "", "Transliterate the subtitles of languages written in both Cyrillic and Latin (Serbian, Macedonian...) to this script: latin or cyrillic. Keeps the original script by default")
 	_ = viper.BindPFlag("download.languages", dlCmd.Flags().Lookup("languages"))
 	_ = viper.BindPFlag("download.apis", dlCmd.Flags().Lookup("apis"))
+	_ = viper.BindPFlag("download.format", dlCmd.Flags().Lookup("format"))
+	_ = viper.BindPFlag("download.bom", dlCmd.Flags().Lookup("bom"))
+	_ = viper.BindPFlag("download.hearing_impaired", dlCmd.Flags().Lookup("hearing-impaired"))
+	_ = viper.BindPFlag("download.forced", dlCmd.Flags().Lookup("forced"))
+	_ = viper.BindPFlag("download.clean", dlCmd.Flags().Lookup("clean"))
+	_ = viper.BindPFlag("download.check_language", dlCmd.Flags().Lookup("check-language"))
+	_ = viper.BindPFlag("download.script", dlCmd.Flags().Lookup("script"))
 
 	RootCmd.AddCommand(dlCmd)
 }
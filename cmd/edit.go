@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/matcornic/subify/common/utils"
+	"github.com/matcornic/subify/subtitles/format"
+	"github.com/spf13/cobra"
+)
+
+// editFlags are the flags of the commands editing a subtitle
+type editFlags struct {
+	output    string
+	noBackup  bool
+	frameRate float64
+}
+
+// addEditFlags adds the flags telling where to save an edited subtitle
+func addEditFlags(cmd *cobra.Command, f *editFlags) {
+	cmd.Flags().StringVarP(&f.output, "output", "o", "", "Save to this file instead of editing the subtitle in place. The format is given by its extension")
+	cmd.Flags().BoolVar(&f.noBackup, "no-backup", false, "Don't keep a copy of the original subtitle (.bak, numbered when one exists) when editing in place")
+	cmd.Flags().Float64Var(&f.frameRate, "fps", 0, "Frame rate of the video, for MicroDVD subtitles. Read from the subtitle or 23.976 by default")
+}
+
+// readSubtitle reads a subtitle to edit, or exits
+func readSubtitle(path string, f *editFlags) (*format.Subtitle, format.Format) {
+	s, fm, err := format.ReadFile(path, format.Options{FrameRate: f.frameRate})
+	if err != nil {
+		utils.ExitPrintError(err, "Sadly, we could not read the subtitle %v", path)
+	}
+	return s, fm
+}
+
+// saveSubtitle saves an edited subtitle in place, keeping a backup of the original, or to the output file.
+// It gives the path of the saved file
+func saveSubtitle(path string, s *format.Subtitle, fm format.Format, f *editFlags) string {
+	return save(path, fm, f, func(output string, to format.Format, opts format.Options) error {
+		return format.WriteFile(output, s, to, opts)
+	})
+}
+
+// savePatched saves a subtitle like saveSubtitle. In the format of the subtitle, only its changes are written
+// to the original file (times, removed cues, edited text), so that nothing the model can't hold is lost
+func savePatched(path string, s *format.Subtitle, fm format.Format, f *editFlags) string {
+	return save(path, fm, f, func(output string, to format.Format, opts format.Options) error {
+		if to != fm {
+			return format.WriteFile(output, s, to, opts)
+		}
+		return format.PatchFile(path, output, s, fm, opts)
+	})
+}
+
+// save saves a subtitle with write, in place or to the output file, and gives the path of the saved file
+func save(path string, fm format.Format, f *editFlags, write func(output string, to format.Format, opts format.Options) error) string {
+	opts := format.Options{FrameRate: f.frameRate}
+	if f.output != "" {
+		// Keep the format unless the extension asks for another one
+		to := fm
+		if !strings.EqualFold(filepath.Ext(f.output), fm.Extension()) {
+			if detected, err := format.Detect(f.output, nil); err == nil {
+				to = detected
+			}
+		}
+		if err := write(f.output, to, opts); err != nil {
+			utils.ExitPrintError(err, "Sadly, we could not save the subtitle to %v", f.output)
+		}
+		return f.output
+	}
+
+	var backup string
+	if !f.noBackup {
+		var err error
+		if backup, err = backUp(path); err != nil {
+			utils.ExitPrintError(err, "Sadly, we could not back up the subtitle %v. Nothing has been changed", path)
+		}
+	}
+	if err := write(path, fm, opts); err != nil {
+		utils.ExitPrintError(err, "Sadly, we could not save the subtitle %v", path)
+	}
+	if backup != "" {
+		fmt.Println("Original subtitle saved to", backup)
+	}
+	return path
+}
+
+// backUp copies the file to path.bak, or to path.1.bak, path.2.bak... when older backups exist, so that
+// the original of successive edits is never lost. It gives the path of the backup
+func backUp(path string) (string, error) {
+	original, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	for i := 0; ; i++ {
+		backup := path + ".bak"
+		if i > 0 {
+			backup = fmt.Sprintf("%v.%d.bak", path, i)
+		}
+		file, err := os.OpenFile(backup, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if os.IsExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		if _, err = file.Write(original); err == nil {
+			err = file.Close()
+		} else {
+			file.Close()
+		}
+		return backup, err
+	}
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/matcornic/subify/common/utils"
+	"github.com/matcornic/subify/subtitles"
+	"github.com/matcornic/subify/subtitles/container"
+	"github.com/spf13/cobra"
+)
+
+var extractTrack int
+var extractLanguage string
+var extractOutput string
+
+// extractCmd represents the extract command
+var extractCmd = &cobra.Command{
+	Use:   "extract <video-path>",
+	Short: "Extract the subtitles embedded in a video - 'subify extract --help'",
+	Long: `Extract the text subtitles embedded in a Matroska (MKV, WebM) or MP4 (MP4, M4V, MOV) video, to edit or synchronize them.
+SRT (S_TEXT/UTF8), ASS, SSA and WebVTT tracks of Matroska videos and tx3g tracks of MP4 videos are saved in their own format,
+next to the video with their language in the name (Movie.en.srt, Movie.en.sdh.srt), and their track number when the name is taken
+(Movie.en.track4.srt). Existing files are never overwritten. Picture based subtitles (VobSub, PGS) can't be extracted.
+All the text subtitle tracks are extracted, unless a track or a language is given. See 'subify list tracks'`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			utils.Exit("Video file needed. See usage : 'subify help' or 'subify extract --help'")
+		}
+		tracks, err := container.ReadTracks(args[0])
+		if err != nil {
+			utils.ExitPrintError(err, "Sadly, we could not read the tracks of %v", args[0])
+		}
+
+		var selected []container.Track
+		switch {
+		case extractTrack > 0:
+			for _, t := range container.SubtitleTracks(tracks) {
+				if t.Number == extractTrack {
+					selected = append(selected, t)
+				}
+			}
+			if len(selected) == 0 {
+				utils.Exit("No subtitle track %v in the video. See 'subify list tracks %v'", extractTrack, args[0])
+			}
+		case extractLanguage != "":
+			language := subtitles.Languages.GetLanguage(extractLanguage)
+			if language == nil {
+				utils.Exit("Language %v does not exist. See 'subify list languages'", extractLanguage)
+			}
+			selected = subtitles.FindTracks(tracks, *language)
+			if len(selected) == 0 {
+				utils.Exit("No %v subtitle track in the video. See 'subify list tracks %v'", language.Description, args[0])
+			}
+			selected = selected[:1]
+		default:
+			for _, t := range container.SubtitleTracks(tracks) {
+				if t.Extractable() {
+					selected = append(selected, t)
+				}
+			}
+			if len(selected) == 0 {
+				utils.Exit("No text subtitle track in the video. See 'subify list tracks %v'", args[0])
+			}
+		}
+		if extractOutput != "" && len(selected) > 1 {
+			utils.Exit("The video has %v subtitle tracks, choose one with --track or --lang to use --output", len(selected))
+		}
+
+		for _, t := range selected {
+			path, err := subtitles.Extract(args[0], t, extractOutput)
+			if err != nil {
+				utils.ExitPrintError(err, "Sadly, we could not extract the track %v", t.Number)
+			}
+			fmt.Println("Track", t.Number, "extracted to", path)
+		}
+	},
+}
+
+func init() {
+	extractCmd.Flags().IntVarP(&extractTrack, "track", "t", 0, "Number of the track to extract. See 'subify list tracks'")
+	extractCmd.Flags().StringVarP(&extractLanguage, "lang", "l", "", "Language of the track to extract, full subtitles first")
+	extractCmd.Flags().StringVarP(&extractOutput, "output", "o", "", "Path of the extracted subtitle. Next to the video by default")
+	RootCmd.AddCommand(extractCmd)
+}
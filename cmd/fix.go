@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/matcornic/subify/common/utils"
+	"github.com/matcornic/subify/subtitles/quality"
+	"github.com/spf13/cobra"
+)
+
+var fixEdit editFlags
+var fixRules = quality.DefaultRules
+
+// fixCmd represents the fix command
+var fixCmd = &cobra.Command{
+	Use:   "fix <subtitle-path>",
+	Short: "Fix the defects of a subtitle - 'subify fix --help'",
+	Long: `Apply the safe corrections to a subtitle: empty cues are removed, cues are sorted and renumbered,
+overlaps are resolved, too short cues are displayed longer when the next cue leaves room, and long lines are rewrapped.
+Cues starting together are merged when they have the same style and alignment, the others are displayed both.
+The subtitle is edited in place and the original is kept as a backup, unless --output is given.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			utils.Exit("Subtitle file needed. See usage : 'subify help' or 'subify fix --help'")
+		}
+		s, f := readSubtitle(args[0], &fixEdit)
+		fixes := quality.Fix(s, fixRules)
+		if fixes.Total() == 0 {
+			fmt.Println("Nothing to fix")
+			return
+		}
+		path := saveSubtitle(args[0], s, f, &fixEdit)
+		fmt.Printf("%v empty cues removed, %v cues merged, %v overlaps resolved, %v cues displayed longer, %v cues rewrapped. Saved to %v\n",
+			fixes.Removed, fixes.Merged, fixes.Overlaps, fixes.Durations, fixes.Rewrapped, path)
+	},
+}
+
+func init() {
+	addRuleFlags(fixCmd, &fixRules)
+	addEditFlags(fixCmd, &fixEdit)
+	RootCmd.AddCommand(fixCmd)
+}
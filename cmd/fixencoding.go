@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/matcornic/subify/common/utils"
+	"github.com/matcornic/subify/subtitles"
+	"github.com/matcornic/subify/subtitles/charset"
+	"github.com/spf13/cobra"
+	logger "github.com/spf13/jwalterweatherman"
+)
+
+var fixEncodingLanguage string
+var fixEncodingBOM bool
+
+// fixEncodingCmd represents the fix-encoding command
+var fixEncodingCmd = &cobra.Command{
+	Use:   "fix-encoding <subtitle-path>...",
+	Short: "Transcode subtitles to UTF-8 - 'subify fix-encoding --help'",
+	Long: `Detect the character encoding of subtitles (Windows-1252, Windows-1251, ISO-8859-2, Big5, GB18030...)
+and transcode them to UTF-8, in place.
+The language of the subtitles guides the detection. By default, it is read from their names (Movie.fr.srt)`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			utils.Exit("Subtitle files needed. See usage : 'subify help' or 'subify fix-encoding --help'")
+		}
+		var language *subtitles.Language
+		if fixEncodingLanguage != "" {
+			if language = subtitles.Languages.GetLanguage(fixEncodingLanguage); language == nil {
+				utils.Exit("Language %v is not available. See 'subify list languages --all'", fixEncodingLanguage)
+			}
+		}
+
+		failed := 0
+		for _, path := range args {
+			lang := language
+			if lang == nil {
+				lang = subtitles.LanguageFromPath(path)
+			}
+			detected, err := subtitles.NormalizeEncoding(path, lang, fixEncodingBOM)
+			if err != nil {
+				logger.ERROR.Println(path, ":", err)
+				failed++
+				continue
+			}
+			if detected.Name == charset.UTF8 {
+				fmt.Println(path, ": already in UTF-8")
+			} else {
+				fmt.Println(path, ": transcoded to UTF-8 from", detected)
+			}
+		}
+		if failed > 0 {
+			utils.Exit("%v subtitles could not be transcoded", failed)
+		}
+	},
+}
+
+func init() {
+	fixEncodingCmd.Flags().StringVarP(&fixEncodingLanguage, "language", "l", "", "Language of the subtitles, when it is not in their names")
+	fixEncodingCmd.Flags().BoolVar(&fixEncodingBOM, "bom", false, "Save the subtitles with a byte order mark (BOM), for players needing it")
+	RootCmd.AddCommand(fixEncodingCmd)
+}
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/matcornic/subify/common/utils"
+	"github.com/matcornic/subify/subtitles"
+	"github.com/spf13/cobra"
+)
+
+// indexCmd represents the index command
+var indexCmd = &cobra.Command{
+	Use:   "index",
+	Short: "Rebuild the index of the local subtitle directories",
+	Long: `Rebuild the index of the local subtitle directories (configured with 'local.dirs')
+Subtitles need the language in their name (ex: Movie.2019.en.srt) to be indexed. Forced subtitles are named like Movie.2019.en.forced.srt`,
+	Run: func(cmd *cobra.Command, args []string) {
+		count, err := subtitles.IndexLocal()
+		if err != nil {
+			utils.ExitPrintError(err, "Sadly, we could not index the local directories")
+		}
+		fmt.Println(count, "subtitles indexed")
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(indexCmd)
+}
@@ -1,24 +1,40 @@
 package cmd
 
 import (
+	"github.com/matcornic/subify/common/utils"
 	"github.com/matcornic/subify/subtitles"
 	"github.com/spf13/cobra"
 )
 
 var all bool
 
+var languagesAPI string
+
 // languagesCmd represents the languages command
 var languagesCmd = &cobra.Command{
 	Use:     "languages",
 	Aliases: []string{"lang"},
 	Short:   "List available languages",
-	Long:    `List available languages`,
+	Long: `List available languages, and their code in each api
+Languages are given to Subify by any of their ISO 639 codes or BCP 47 tags (fr, fre, fra, pt-BR, zh-Hant),
+or by their name (French, français). Subtitles are saved with the BCP 47 tag in their name (Movie.pt-BR.srt)
+Chinese subtitles missing in Simplified (zh-Hans) or Traditional (zh-Hant) characters are downloaded in the other script and converted
+Use --api to only show the languages of one api`,
 	Run: func(cmd *cobra.Command, args []string) {
-		subtitles.Languages.Print(all)
+		apis := subtitles.APIs.All()
+		if languagesAPI != "" {
+			api := subtitles.APIs.Find(languagesAPI)
+			if api == nil {
+				utils.Exit("API %v does not exist. See 'subify list apis'", languagesAPI)
+			}
+			apis = subtitles.Clients{api}
+		}
+		subtitles.Languages.Print(all, apis)
 	},
 }
 
 func init() {
 	listCmd.AddCommand(languagesCmd)
 	languagesCmd.PersistentFlags().BoolVar(&all, "all", false, "Shows all languages")
+	languagesCmd.PersistentFlags().StringVarP(&languagesAPI, "api", "a", "", "Only shows the languages of this api")
 }
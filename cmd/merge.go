@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/matcornic/subify/common/utils"
+	"github.com/matcornic/subify/subtitles"
+	"github.com/matcornic/subify/subtitles/format"
+	"github.com/spf13/cobra"
+)
+
+var mergeTo string
+var mergeOutput string
+var mergeFrameRate float64
+
+// mergeCmd represents the merge command
+var mergeCmd = &cobra.Command{
+	Use:   "merge <primary-subtitle-path> <secondary-subtitle-path>",
+	Short: "Merge two subtitles to display two languages at once - 'subify merge --help'",
+	Long: `Merge the subtitles of a video in two languages, to display both at once.
+The cues of the secondary subtitle are attached to the primary cue they overlap the most, and take its timing.
+An ASS subtitle displays the primary language at the bottom and the secondary one at the top, in yellow.
+Other formats (like SRT) stack the secondary lines in yellow under the primary ones.
+The merged subtitle is saved next to the primary one, with both languages in its name (Movie.en-fr.ass).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 2 {
+			utils.Exit("Two subtitle files needed. See usage : 'subify help' or 'subify merge --help'")
+		}
+		to, err := format.ParseFormat(mergeTo)
+		if err != nil {
+			utils.ExitPrintError(err, "Available formats are srt, vtt, ass, ssa, sub, subviewer, ttml and sbv")
+		}
+		output := mergeOutput
+		if output == "" {
+			output = subtitles.MergedPath(args[0], args[1], to)
+		} else if !cmd.Flags().Changed("to") {
+			if f, err := format.ParseFormat(filepath.Ext(output)); err == nil {
+				to = f
+			}
+		}
+		err = format.MergeFiles(args[0], args[1], output, to, format.Options{FrameRate: mergeFrameRate})
+		if err != nil {
+			utils.ExitPrintError(err, "Sadly, we could not merge the subtitles")
+		}
+		fmt.Println("Subtitles merged to", output)
+	},
+}
+
+func init() {
+	mergeCmd.Flags().StringVarP(&mergeTo, "to", "t", "ass", "Format of the merged subtitle. ASS places the languages at the top and the bottom, other formats stack them")
+	mergeCmd.Flags().StringVarP(&mergeOutput, "output", "o", "", "Path of the merged subtitle. Its extension gives the format, unless --to is given")
+	mergeCmd.Flags().Float64Var(&mergeFrameRate, "fps", 0, "Frame rate of the video, for MicroDVD subtitles. Read from the subtitle or 23.976 by default")
+	RootCmd.AddCommand(mergeCmd)
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/matcornic/subify/common/utils"
+	"github.com/matcornic/subify/subtitles"
+	"github.com/spf13/cobra"
+)
+
+var muxLanguages string
+var muxDefault string
+var muxForced string
+var muxOutput string
+
+// muxCmd represents the mux command
+var muxCmd = &cobra.Command{
+	Use:   "mux <video-path> <subtitle-path>...",
+	Short: "Add subtitles to a Matroska video as new tracks - 'subify mux --help'",
+	Long: `Add subtitles to a Matroska (MKV, WebM) video as new tracks, so that they always follow the video.
+The tracks of the video are copied untouched, and the subtitles are interleaved with them. The new video is written
+next to the original one (Movie.muxed.mkv), unless an output is given. It can be the original video, which is then replaced at once.
+The language of each subtitle is read from its name (Movie.en.srt), like the forced (Movie.en.forced.srt) and
+hearing impaired (Movie.en.sdh.srt) flags. ASS and SSA subtitles keep their styles, other formats are added as SRT.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) < 2 {
+			utils.Exit("Video and subtitle files needed. See usage : 'subify help' or 'subify mux --help'")
+		}
+		languages := utils.SplitList(muxLanguages)
+		if len(languages) > 0 && len(languages) != len(args)-1 {
+			utils.Exit("%v languages given for %v subtitles", len(languages), len(args)-1)
+		}
+		forced := subtitles.Languages.GetLanguages(utils.SplitList(muxForced))
+		var defaultLanguage *subtitles.Language
+		if muxDefault != "" {
+			if defaultLanguage = subtitles.Languages.GetLanguage(muxDefault); defaultLanguage == nil {
+				utils.Exit("Language %v does not exist. See 'subify list languages'", muxDefault)
+			}
+		}
+
+		var subs []subtitles.MuxSubtitle
+		for i, path := range args[1:] {
+			s := subtitles.NewMuxSubtitle(path)
+			if len(languages) > 0 {
+				if s.Language = subtitles.Languages.GetLanguage(languages[i]); s.Language == nil {
+					utils.Exit("Language %v does not exist. See 'subify list languages'", languages[i])
+				}
+			}
+			if s.Language != nil {
+				for _, l := range forced {
+					s.Forced = s.Forced || l.ID == s.Language.ID
+				}
+				// Only the first full subtitle of the language is the default one
+				if defaultLanguage != nil && s.Language.ID == defaultLanguage.ID && !s.Forced {
+					s.Default, defaultLanguage = true, nil
+				}
+			}
+			subs = append(subs, s)
+		}
+
+		output, err := subtitles.Mux(args[0], subs, muxOutput)
+		if err != nil {
+			utils.ExitPrintError(err, "Sadly, we could not add the subtitles to %v", args[0])
+		}
+		fmt.Println("Video with", len(subs), "new subtitle tracks saved to", output)
+	},
+}
+
+func init() {
+	muxCmd.Flags().StringVarP(&muxLanguages, "lang", "l", "", "Languages of the subtitles, in the same order (en,fr). Read from their names by default")
+	muxCmd.Flags().StringVarP(&muxDefault, "default", "d", "", "Language of the subtitle selected by default by players. None by default")
+	muxCmd.Flags().StringVar(&muxForced, "forced", "", "Languages of the forced subtitles (en,fr), in addition to the ones named like Movie.en.forced.srt")
+	muxCmd.Flags().StringVarP(&muxOutput, "output", "o", "", "Path of the new video. Next to the original one by default (Movie.muxed.mkv)")
+	RootCmd.AddCommand(muxCmd)
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/matcornic/subify/common/utils"
+	"github.com/matcornic/subify/subtitles/format"
+	"github.com/spf13/cobra"
+)
+
+var resyncEdit editFlags
+var resyncPoints []string
+var resyncFromFPS float64
+var resyncToFPS float64
+
+// resyncCmd represents the resync command
+var resyncCmd = &cobra.Command{
+	Use:   "resync <subtitle-path>",
+	Short: "Fix a subtitle drifting against the video - 'subify resync --help'",
+	Long: `Fix a subtitle drifting against the video, with a linear transform of all its cues. Two modes are available:
+- two-point sync: give the right time of two cues, ideally one near the start and one near the end.
+  'subify resync movie.srt --sync 3=00:01:02,500 --sync last=01:48:10' moves the 3rd cue to 00:01:02,500
+  and the last cue to 01:48:10, other cues are moved proportionally
+- frame-rate conversion: 'subify resync movie.srt --from 25 --to 23.976' converts a subtitle made for
+  a 25 fps (PAL) release to a 23.976 fps video
+The subtitle is edited in place and the original is kept as a backup, unless --output is given.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			utils.Exit("Subtitle file needed. See usage : 'subify help' or 'subify resync --help'")
+		}
+		s, f := readSubtitle(args[0], &resyncEdit)
+		if len(s.Cues) == 0 {
+			utils.Exit("The subtitle %v has no cue", args[0])
+		}
+
+		var scale float64
+		var offset time.Duration
+		var err error
+		switch {
+		case len(resyncPoints) == 2 && resyncFromFPS == 0 && resyncToFPS == 0:
+			cueA, x := parseSyncPoint(resyncPoints[0], s)
+			cueB, y := parseSyncPoint(resyncPoints[1], s)
+			scale, offset, err = format.TwoPointTransform(cueA.Start, x, cueB.Start, y)
+		case len(resyncPoints) == 0 && resyncFromFPS != 0 && resyncToFPS != 0:
+			scale, err = format.FrameRateScale(resyncFromFPS, resyncToFPS)
+		default:
+			utils.Exit("Give either two --sync points, or --from and --to frame rates. See 'subify resync --help'")
+		}
+		if err != nil {
+			utils.ExitPrintError(err, "Sadly, we could not resync the subtitle")
+		}
+
+		s.Transform(scale, offset, nil)
+		path := savePatched(args[0], s, f, &resyncEdit)
+		fmt.Printf("Subtitle resynced (speed x%.5f, offset %v) and saved to %v\n", scale, offset, path)
+	},
+}
+
+// parseSyncPoint parses a sync point like 12=00:01:02,500 into the cue and its right time, or exits
+func parseSyncPoint(point string, s *format.Subtitle) (*format.Cue, time.Duration) {
+	parts := strings.SplitN(point, "=", 2)
+	if len(parts) != 2 {
+		utils.Exit("Invalid sync point %v. Expected <cue>=<time>, like 12=00:01:02,500", point)
+	}
+	index := len(s.Cues)
+	if cue := strings.TrimSpace(parts[0]); !strings.EqualFold(cue, "last") {
+		var err error
+		if index, err = strconv.Atoi(cue); err != nil || index < 1 || index > len(s.Cues) {
+			utils.Exit("Invalid cue %v in sync point %v. Cues go from 1 to %v", cue, point, len(s.Cues))
+		}
+	}
+	t, err := format.ParseOffset(parts[1])
+	if err != nil || t < 0 {
+		utils.Exit("Invalid time in sync point %v. Examples of valid times: 00:01:02,500, 90s", point)
+	}
+	return s.Cues[index-1], t
+}
+
+func init() {
+	resyncCmd.Flags().StringArrayVar(&resyncPoints, "sync", nil, "Right time of a cue, as <cue>=<time> (12=00:01:02,500, last=01:48:10). Give it twice")
+	resyncCmd.Flags().Float64Var(&resyncFromFPS, "from", 0, "Frame rate of the video the subtitle was made for (25 for PAL releases)")
+	resyncCmd.Flags().Float64Var(&resyncToFPS, "to", 0, "Frame rate of your video (23.976 for most releases)")
+	addEditFlags(resyncCmd, &resyncEdit)
+	RootCmd.AddCommand(resyncCmd)
+}
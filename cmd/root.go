@@ -19,6 +19,14 @@ http://github.com/matcornic/subify`,
 		// Overwrite conf from config files
 		config.Dev = viper.GetBool("root.dev")
 		config.Verbose = viper.GetBool("root.verbose")
+		config.SubDLAPIKey = viper.GetString("subdl.apikey")
+		config.JimakuAPIKey = viper.GetString("jimaku.apikey")
+		config.LocalDirs = utils.SplitList(viper.GetString("local.dirs"))
+		config.CleanPatterns = viper.GetStringSlice("clean.patterns")
+		config.CleanCues = viper.GetInt("clean.cues")
+		if err := viper.UnmarshalKey("download.audio_rules", &config.AudioRules); err != nil {
+			utils.ExitPrintError(err, "The audio rules of the configuration are invalid")
+		}
 		utils.InitLoggingConf()
 	},
 }
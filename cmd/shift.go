@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/matcornic/subify/common/utils"
+	"github.com/matcornic/subify/subtitles/format"
+	"github.com/spf13/cobra"
+)
+
+var shiftEdit editFlags
+var shiftFrom string
+var shiftTo string
+var shiftFromCue int
+
+// shiftCmd represents the shift command
+var shiftCmd = &cobra.Command{
+	Use:   "shift <subtitle-path> <offset>",
+	Short: "Shift the timing of a subtitle - 'subify shift --help'",
+	Long: `Shift the timing of a subtitle by an offset, like +1.5s, 200ms or 00:00:02,500.
+Use -- before negative offsets, so that they are not taken as flags: 'subify shift movie.srt -- -1.5s'
+All cues are shifted, unless a time range (--from, --to) or a first cue (--from-cue) is given.
+The subtitle is edited in place and the original is kept as a backup, unless --output is given.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 2 {
+			utils.Exit("Subtitle file and offset needed. See usage : 'subify help' or 'subify shift --help'")
+		}
+		offset, err := format.ParseOffset(args[1])
+		if err != nil {
+			utils.ExitPrintError(err, "Sadly, we could not understand the offset")
+		}
+		from, to := parseTimeFlag(shiftFrom, "from"), parseTimeFlag(shiftTo, "to")
+
+		s, f := readSubtitle(args[0], &shiftEdit)
+		count := 0
+		s.Shift(offset, func(i int, c *format.Cue) bool {
+			selected := (shiftFromCue == 0 || i+1 >= shiftFromCue) &&
+				(shiftFrom == "" || c.Start >= from) &&
+				(shiftTo == "" || c.Start <= to)
+			if selected {
+				count++
+			}
+			return selected
+		})
+		path := savePatched(args[0], s, f, &shiftEdit)
+		fmt.Println(count, "cues shifted by", offset, "and saved to", path)
+	},
+}
+
+// parseTimeFlag parses the time given to a flag, or exits
+func parseTimeFlag(value, flag string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	t, err := format.ParseOffset(value)
+	if err != nil || t < 0 {
+		utils.Exit("Invalid time for --%v: %v. Examples of valid times: 00:10:00, 90s, 1h2m", flag, value)
+	}
+	return t
+}
+
+func init() {
+	shiftCmd.Flags().StringVar(&shiftFrom, "from", "", "Only shift the cues starting from this time (00:10:00, 90s...)")
+	shiftCmd.Flags().StringVar(&shiftTo, "to", "", "Only shift the cues starting until this time (00:20:00, 1h2m...)")
+	shiftCmd.Flags().IntVar(&shiftFromCue, "from-cue", 0, "Only shift the cues from this one onward (1 is the first cue)")
+	addEditFlags(shiftCmd, &shiftEdit)
+	RootCmd.AddCommand(shiftCmd)
+}
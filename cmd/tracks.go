@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"github.com/matcornic/subify/common/utils"
+	"github.com/matcornic/subify/subtitles"
+	"github.com/matcornic/subify/subtitles/container"
+	"github.com/spf13/cobra"
+)
+
+var allTracks bool
+
+// tracksCmd represents the tracks command
+var tracksCmd = &cobra.Command{
+	Use:   "tracks <video-path>",
+	Short: "List the subtitle tracks embedded in a video",
+	Long: `List the subtitle tracks embedded in a Matroska (MKV, WebM) or MP4 (MP4, M4V, MOV) video,
+with their language, and their default and forced flags. Use --all to list the video and audio tracks too`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			utils.Exit("Video file needed. See usage : 'subify help' or 'subify list tracks --help'")
+		}
+		tracks, err := container.ReadTracks(args[0])
+		if err != nil {
+			utils.ExitPrintError(err, "Sadly, we could not read the tracks of %v", args[0])
+		}
+		if !allTracks {
+			tracks = container.SubtitleTracks(tracks)
+		}
+		subtitles.PrintTracks(tracks)
+	},
+}
+
+func init() {
+	listCmd.AddCommand(tracksCmd)
+	tracksCmd.Flags().BoolVar(&allTracks, "all", false, "List all the tracks, not only the subtitles")
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/matcornic/subify/common/utils"
+	"github.com/matcornic/subify/subtitles"
+	"github.com/matcornic/subify/subtitles/translit"
+	"github.com/spf13/cobra"
+)
+
+var transliterateEdit editFlags
+var transliterateTo string
+var transliterateLanguage string
+
+// transliterateCmd represents the transliterate command
+var transliterateCmd = &cobra.Command{
+	Use:     "transliterate <subtitle-path>",
+	Aliases: []string{"translit"},
+	Short:   "Convert a subtitle between the Cyrillic and Latin scripts - 'subify transliterate --help'",
+	Long: `Convert a subtitle between the Cyrillic and Latin scripts, for the languages written in both:
+Serbian, Bosnian, Montenegrin and Macedonian. The language is read from the name of the subtitle (Movie.sr.srt),
+unless --language is given. Tags, links and the letters without equivalent (q, w, x, y) are kept as is.
+The subtitle is edited in place and the original is kept as a backup, unless --output is given.
+Downloaded subtitles are transliterated with 'subify dl --script', or the 'script' key of the [download] section of the configuration.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			utils.Exit("Subtitle file needed. See usage : 'subify help' or 'subify transliterate --help'")
+		}
+		to, err := translit.ParseScript(transliterateTo)
+		if err != nil {
+			utils.ExitPrintError(err, "Sadly, we could not transliterate the subtitle")
+		}
+		language := subtitles.LanguageFromPath(args[0])
+		if transliterateLanguage != "" {
+			language = subtitles.Languages.GetLanguage(transliterateLanguage)
+		}
+		if language == nil {
+			utils.Exit("Language of the subtitle unknown. Give it with --language, like 'subify transliterate -l sr --to latin Movie.srt'")
+		}
+
+		s, f := readSubtitle(args[0], &transliterateEdit)
+		changed, err := translit.Transliterate(s, language.Tag, to)
+		if err != nil {
+			utils.ExitPrintError(err, "Sadly, we could not transliterate the %v subtitle", language.Description)
+		}
+		if changed == 0 {
+			fmt.Println("Subtitle already written in the", to, "script")
+			return
+		}
+		fmt.Println(changed, "cues transliterated to the", to, "script")
+		path := savePatched(args[0], s, f, &transliterateEdit)
+		fmt.Println("Subtitle saved to", path)
+	},
+}
+
+func init() {
+	transliterateCmd.Flags().StringVarP(&transliterateTo, "to", "t", "latin", "Script to convert the subtitle to: latin or cyrillic")
+	transliterateCmd.Flags().StringVarP(&transliterateLanguage, "language", "l", "", "Language of the subtitle, when it is not in its name. Available languages at 'subify list languages'")
+	addEditFlags(transliterateCmd, &transliterateEdit)
+	RootCmd.AddCommand(transliterateCmd)
+}
@@ -9,4 +9,33 @@ var (
 
 	// Dev mode to use sandbox parameters
 	Dev bool
+
+	// SubDLAPIKey is the key needed to search subtitles with the SubDL API
+	SubDLAPIKey string
+
+	// JimakuAPIKey is the key needed to search anime subtitles with the Jimaku API
+	JimakuAPIKey string
+
+	// LocalDirs are the directories of subtitles served by the Local API
+	LocalDirs []string
+
+	// HearingImpaired is the preference for hearing impaired subtitles: prefer, avoid, require or empty.
+	// It is checked and lower cased by subtitles.ParseHearingImpaired when the command starts
+	HearingImpaired string
+
+	// CleanPatterns are the regular expressions of the ads and credits to remove, added to the built-in ones
+	CleanPatterns []string
+
+	// CleanCues is the number of cues cleaned at the start and at the end of subtitles
+	CleanCues int
+
+	// AudioRules choose the subtitles to download from the languages of the audio of the video
+	AudioRules []AudioRule
 )
+
+// AudioRule changes the subtitles to download when the audio of the video is in one of its languages
+type AudioRule struct {
+	Audio     []string // Languages of the audio matching the rule
+	Languages []string // Languages of the subtitles to download. The preferred languages when empty
+	Forced    bool     // Download forced subtitles only (foreign parts), as the audio is understood
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
 
 	"github.com/matcornic/subify/common/config"
 	logger "github.com/spf13/jwalterweatherman"
@@ -34,6 +35,16 @@ func ExitVerbose(verboseLog string, format string, args ...interface{}) {
 	if !config.Verbose {
 		logger.ERROR.Println("Run subify with --verbose option to get more information about the error")
 	}
-	logger.FATAL.Printf(format)
+	logger.FATAL.Printf(format, args...)
 	os.Exit(-1)
 }
+
+// SplitList splits a comma separated list, ignoring empty values
+func SplitList(list string) (values []string) {
+	for _, v := range strings.Split(list, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return
+}
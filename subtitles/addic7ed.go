@@ -33,10 +33,9 @@ var addic7edLangs = map[string]string{
 	"baq": "Euskera",
 	"fin": "Finnish",
 	"fre": "French",
-	"frc": "French (Canadian)",
 	"glg": "Galego",
 	"ger": "German",
-	"ell": "Greek",
+	"gre": "Greek",
 	"heb": "Hebrew",
 	"hin": "Hindi",
 	"hun": "Hungarian",
@@ -49,26 +48,31 @@ var addic7edLangs = map[string]string{
 	"lav": "Latvian",
 	"lit": "Lithuanian",
 	"mac": "Macedonian",
-	"mal": "Malay",
+	"may": "Malay",
 	"nor": "Norwegian",
 	"per": "Persian",
 	"pol": "Polish",
 	"por": "Portuguese",
-	"pob": "Portuguese (Brazilian)",
 	"rum": "Romanian",
 	"rus": "Russian",
-	"scc": "Serbian (Cyrillic)",
+	"srp": "Serbian (Cyrillic)",
 	"sin": "Sinhala",
 	"slo": "Slovak",
 	"slv": "Slovenian",
-	"es":  "Spanish",
+	"spa": "Spanish",
 	"swe": "Swedish",
 	"tam": "Tamil",
 	"tha": "Thai",
 	"tur": "Turkish",
 	"ukr": "Ukrainian",
 	"vie": "Vietnamese",
-	"zht": "Chinese (Traditional)",
+
+	// Regional and script variants
+	"es-419":  "Spanish (Latin America)",
+	"fr-CA":   "French (Canadian)",
+	"pt-BR":   "Portuguese (Brazilian)",
+	"zh-Hans": "Chinese (Simplified)",
+	"zh-Hant": "Chinese (Traditional)",
 }
 
 // Addic7ed creates a new API for Addic7ed
@@ -94,7 +98,7 @@ func (s Addic7edAPI) Download(videoPath string, language Language) (subtitlePath
 	}
 
 	// Saving to disk
-	subtitlePath = videoPath[0:len(videoPath)-len(path.Ext(videoPath))] + "." + lang + ".srt"
+	subtitlePath = subtitlePathFor(videoPath, language.Tag, ".srt")
 	if err := subtitle.DownloadTo(subtitlePath); err != nil {
 		return "", err
 	}
@@ -118,3 +122,11 @@ func (s Addic7edAPI) GetName() string {
 func (s Addic7edAPI) GetAliases() []string {
 	return s.Aliases
 }
+
+// GetCapabilities returns what this API is able to do
+func (s Addic7edAPI) GetCapabilities() Capabilities {
+	return Capabilities{
+		Languages:   addic7edLangs,
+		SearchModes: []SearchMode{SearchByName},
+	}
+}
@@ -0,0 +1,97 @@
+package subtitles
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"strings"
+)
+
+const (
+	// maxSubtitleSize protects against archives containing huge files
+	maxSubtitleSize = 20 * 1024 * 1024
+	// maxArchiveSize protects against huge downloads, archives of full seasons included
+	maxArchiveSize = 100 * 1024 * 1024
+)
+
+// subtitleExtensions are the file extensions recognized as subtitles inside archives
+var subtitleExtensions = []string{".srt", ".ass", ".ssa", ".vtt", ".sub"}
+
+// isSubtitleFile tells if a file name looks like a subtitle
+func isSubtitleFile(name string) bool {
+	ext := strings.ToLower(path.Ext(name))
+	for _, e := range subtitleExtensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// extractFromZip extracts the subtitle matching the best the video release from a ZIP archive.
+// When the archive contains several subtitles (a full season for example), the episode number
+// is used first, then the similarity of the names.
+func extractFromZip(data []byte, video Release) (name string, content []byte, err error) {
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", nil, fmt.Errorf("Downloaded archive is not a valid ZIP file: %v", err)
+	}
+
+	var candidates []string
+	files := make(map[string]*zip.File)
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() || !isSubtitleFile(f.Name) || strings.HasPrefix(path.Base(f.Name), ".") {
+			continue
+		}
+		candidates = append(candidates, f.Name)
+		files[f.Name] = f
+	}
+
+	name = chooseSubtitleFile(candidates, video)
+	if name == "" {
+		return "", nil, errors.New("No subtitle matching the video in the downloaded archive")
+	}
+
+	f := files[name]
+	if f.UncompressedSize64 > maxSubtitleSize {
+		return "", nil, fmt.Errorf("Subtitle %v is too big to be extracted", name)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return "", nil, fmt.Errorf("Can't extract %v from archive: %v", name, err)
+	}
+	defer rc.Close()
+	// The size written in the archive may be wrong
+	content, err = ioutil.ReadAll(io.LimitReader(rc, maxSubtitleSize+1))
+	if err != nil {
+		return "", nil, fmt.Errorf("Can't extract %v from archive: %v", name, err)
+	}
+	if len(content) > maxSubtitleSize {
+		return "", nil, fmt.Errorf("Subtitle %v is too big to be extracted", name)
+	}
+	return name, content, nil
+}
+
+// chooseSubtitleFile picks the best file name for the video among candidates.
+// Returns an empty string when no candidate matches the episode of the video.
+func chooseSubtitleFile(candidates []string, video Release) string {
+	best, bestScore := "", -1.0
+	for _, c := range candidates {
+		release := ParseRelease(c)
+		if len(candidates) > 1 && !video.MatchesEpisode(release) {
+			continue
+		}
+		score := video.Similarity(release)
+		if video.IsEpisode() && release.Episode == video.Episode {
+			score++
+		}
+		if score > bestScore {
+			best, bestScore = c, score
+		}
+	}
+	return best
+}
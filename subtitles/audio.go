@@ -0,0 +1,67 @@
+package subtitles
+
+import (
+	"strings"
+
+	"github.com/matcornic/subify/common/config"
+	"github.com/matcornic/subify/subtitles/container"
+	logger "github.com/spf13/jwalterweatherman"
+)
+
+// AudioLanguages gives the known languages of the audio tracks of a video
+func AudioLanguages(tracks []container.Track) Langs {
+	var languages Langs
+	for _, t := range tracks {
+		if t.Type != container.Audio {
+			continue
+		}
+		if l := TrackLanguage(t.Language); l != nil && languages.GetLanguage(l.ID) == nil {
+			languages = append(languages, *l)
+		}
+	}
+	return languages
+}
+
+// matchAudioRule gives the first rule with one of the languages of the audio, nil if none matches
+func matchAudioRule(rules []config.AudioRule, audio Langs) *config.AudioRule {
+	for i, r := range rules {
+		for _, l := range Languages.GetLanguages(r.Audio) {
+			for _, a := range audio {
+				if l.Covers(a) {
+					return &rules[i]
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// applyAudioRules follows the first audio rule of the configuration matching the audio of the video:
+// it gives the languages to download, and asks for forced subtitles if needed
+func applyAudioRules(videoPath string, languages []string, opts Options) ([]string, Options) {
+	if len(config.AudioRules) == 0 {
+		return languages, opts
+	}
+	tracks, err := container.ReadTracks(videoPath)
+	if err != nil {
+		if err != container.ErrUnknownContainer {
+			logger.INFO.Println("Audio languages not read:", err)
+		}
+		return languages, opts
+	}
+	audio := AudioLanguages(tracks)
+	rule := matchAudioRule(config.AudioRules, audio)
+	if rule == nil {
+		return languages, opts
+	}
+	if len(rule.Languages) > 0 {
+		languages = rule.Languages
+	}
+	opts.Forced = opts.Forced || rule.Forced
+	kind := "full"
+	if opts.Forced {
+		kind = "forced"
+	}
+	logger.INFO.Println("Audio in", strings.Join(audio.GetDescriptions(), ", ")+": searching", kind, "subtitles in", strings.Join(languages, ", "))
+	return languages, opts
+}
@@ -0,0 +1,33 @@
+package subtitles
+
+import (
+	"testing"
+
+	"github.com/matcornic/subify/common/config"
+	"github.com/matcornic/subify/subtitles/container"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAudioLanguagesShouldReadAudioTracks(t *testing.T) {
+	tracks := []container.Track{
+		{Number: 1, Type: container.Video, Language: "und"},
+		{Number: 2, Type: container.Audio, Language: "eng"},
+		{Number: 3, Type: container.Audio, Language: "en-US"},
+		{Number: 4, Type: container.Audio, Language: "und"},
+		{Number: 5, Type: container.Subtitle, Language: "fra"},
+	}
+	assert.Equal(t, []string{"English"}, AudioLanguages(tracks).GetDescriptions())
+}
+
+func TestMatchAudioRuleShouldPickFirstMatchingRule(t *testing.T) {
+	rules := []config.AudioRule{
+		{Audio: []string{"en", "fr"}, Languages: []string{"en"}, Forced: true},
+		{Audio: []string{"ja"}, Languages: []string{"en"}},
+	}
+	audio := Languages.GetLanguages([]string{"fre"})
+	assert.Equal(t, &rules[0], matchAudioRule(rules, audio))
+	audio = Languages.GetLanguages([]string{"de", "jpn"})
+	assert.Equal(t, &rules[1], matchAudioRule(rules, audio))
+	assert.Nil(t, matchAudioRule(rules, Languages.GetLanguages([]string{"es"})))
+	assert.Nil(t, matchAudioRule(rules, nil))
+}
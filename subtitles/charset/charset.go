@@ -0,0 +1,239 @@
+// Package charset detects the character encoding of subtitles and transcodes them to UTF-8.
+// The detection decodes the text with the encodings commonly used for its language,
+// and keeps the one giving the most plausible text.
+package charset
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/korean"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+	xunicode "golang.org/x/text/encoding/unicode"
+)
+
+// UTF8 is the name of the UTF-8 encoding
+const UTF8 = "UTF-8"
+
+// BOM is the UTF-8 byte order mark
+var BOM = []byte{0xEF, 0xBB, 0xBF}
+
+var (
+	utf16LEBOM = []byte{0xFF, 0xFE}
+	utf16BEBOM = []byte{0xFE, 0xFF}
+)
+
+// Result is the encoding found for a text
+type Result struct {
+	Name       string  // Name of the encoding (UTF-8, windows-1251, Big5...)
+	Confidence float64 // From 0 to 1
+}
+
+func (r Result) String() string {
+	return fmt.Sprintf("%v (%.0f%%)", r.Name, r.Confidence*100)
+}
+
+// encodings are the supported legacy encodings, by name
+var encodings = map[string]encoding.Encoding{
+	"windows-874":  charmap.Windows874,
+	"windows-1250": charmap.Windows1250,
+	"windows-1251": charmap.Windows1251,
+	"windows-1252": charmap.Windows1252,
+	"windows-1253": charmap.Windows1253,
+	"windows-1254": charmap.Windows1254,
+	"windows-1255": charmap.Windows1255,
+	"windows-1256": charmap.Windows1256,
+	"windows-1257": charmap.Windows1257,
+	"windows-1258": charmap.Windows1258,
+	"ISO-8859-1":   charmap.ISO8859_1,
+	"ISO-8859-2":   charmap.ISO8859_2,
+	"ISO-8859-4":   charmap.ISO8859_4,
+	"ISO-8859-5":   charmap.ISO8859_5,
+	"ISO-8859-6":   charmap.ISO8859_6,
+	"ISO-8859-7":   charmap.ISO8859_7,
+	"ISO-8859-8":   charmap.ISO8859_8,
+	"ISO-8859-9":   charmap.ISO8859_9,
+	"ISO-8859-13":  charmap.ISO8859_13,
+	"ISO-8859-15":  charmap.ISO8859_15,
+	"ISO-8859-16":  charmap.ISO8859_16,
+	"KOI8-R":       charmap.KOI8R,
+	"KOI8-U":       charmap.KOI8U,
+	"IBM866":       charmap.CodePage866,
+	"GB18030":      simplifiedchinese.GB18030,
+	"Big5":         traditionalchinese.Big5,
+	"Shift_JIS":    japanese.ShiftJIS,
+	"EUC-JP":       japanese.EUCJP,
+	"EUC-KR":       korean.EUCKR,
+	"UTF-16LE":     xunicode.UTF16(xunicode.LittleEndian, xunicode.IgnoreBOM),
+	"UTF-16BE":     xunicode.UTF16(xunicode.BigEndian, xunicode.IgnoreBOM),
+}
+
+// Detect guesses the encoding of a text. The language (ISO 639-1 code like "fr" or BCP 47 tag like "zh-Hant", or empty if unknown)
+// restricts the candidates to the encodings used for it and tells which letters to expect
+func Detect(data []byte, lang string) Result {
+	switch {
+	case bytes.HasPrefix(data, BOM):
+		return Result{UTF8, 1}
+	case bytes.HasPrefix(data, utf16LEBOM):
+		return Result{"UTF-16LE", 1}
+	case bytes.HasPrefix(data, utf16BEBOM):
+		return Result{"UTF-16BE", 1}
+	}
+	if name := guessUTF16(data); name != "" {
+		return Result{name, 0.9}
+	}
+	if utf8.Valid(data) {
+		return Result{UTF8, 1}
+	}
+
+	// Scores of the encodings, for the most plausible language. Encodings are tried by usage
+	var names []string
+	scores := map[string]float64{}
+	decoded := map[string]string{}
+	for _, p := range profilesOf(lang) {
+		for _, name := range p.charsets {
+			if _, ok := decoded[name]; !ok {
+				text, err := encodings[name].NewDecoder().Bytes(data)
+				if err != nil {
+					continue
+				}
+				decoded[name] = string(text)
+				names = append(names, name)
+			}
+			if s, ok := scores[name]; !ok || p.score(decoded[name]) > s {
+				scores[name] = p.score(decoded[name])
+			}
+		}
+	}
+	best, second := Result{Name: UTF8}, -1e9
+	bestScore := -1e9
+	for _, name := range names {
+		// Ties go to the most used encoding, the first one
+		if s := scores[name]; s > bestScore {
+			best.Name, second, bestScore = name, bestScore, s
+		} else if s > second {
+			second = s
+		}
+	}
+	// Confident when the text looks right and no other encoding comes close
+	best.Confidence = clamp((bestScore+1)/4, 0, 1) * clamp(0.5+(bestScore-second)/2, 0, 1)
+	return best
+}
+
+// ToUTF8 transcodes a text to UTF-8, without BOM, and gives the encoding it was in
+func ToUTF8(data []byte, lang string) ([]byte, Result, error) {
+	r := Detect(data, lang)
+	if r.Name == UTF8 {
+		return bytes.TrimPrefix(data, BOM), r, nil
+	}
+	decoded, err := encodings[r.Name].NewDecoder().Bytes(data)
+	if err != nil {
+		return nil, r, fmt.Errorf("Can't decode the text from %v because of : %v", r.Name, err)
+	}
+	return bytes.TrimPrefix(decoded, BOM), r, nil
+}
+
+// WithBOM adds the UTF-8 BOM to a text, if it does not have it yet
+func WithBOM(data []byte) []byte {
+	if bytes.HasPrefix(data, BOM) {
+		return data
+	}
+	return append(append([]byte{}, BOM...), data...)
+}
+
+// guessUTF16 recognizes UTF-16 texts without BOM by their zero bytes: text in latin script
+// has a zero byte every two bytes, always at the same position
+func guessUTF16(data []byte) string {
+	n := len(data) - len(data)%2
+	if n < 8 {
+		return ""
+	}
+	var even, odd int
+	for i := 0; i < n; i += 2 {
+		if data[i] == 0 {
+			even++
+		}
+		if data[i+1] == 0 {
+			odd++
+		}
+	}
+	switch {
+	case odd > n/4 && even < n/100+1:
+		return "UTF-16LE"
+	case even > n/4 && odd < n/100+1:
+		return "UTF-16BE"
+	}
+	return ""
+}
+
+// score tells how plausible a decoded text is for the profile, per non ASCII character.
+// Letters of the language win, symbols rarely found in subtitles and invalid characters lose
+func (p profile) score(text string) float64 {
+	var total, count float64
+	var prev rune
+	for _, r := range text {
+		if r < utf8.RuneSelf {
+			if r < 0x20 && r != '\n' && r != '\r' && r != '\t' {
+				total -= 10
+			}
+			prev = r
+			continue
+		}
+		count++
+		switch {
+		case r == utf8.RuneError || (r >= 0x80 && r < 0xA0):
+			total -= 10
+		case p.frequent[r]:
+			total += 3
+		case p.alphabet[r]:
+			total += 2
+		case unicode.IsLetter(r):
+			switch {
+			case p.script == nil:
+				total += 0.5
+			case !unicode.Is(p.script, r):
+				total -= 3
+			case p.alphabet == nil:
+				total++
+			default:
+				// Letters of the script which are not in the alphabet of the language
+				total -= 1
+			}
+			// Text is mostly in lower case, mojibake often mixes cases inside words
+			if unicode.IsLower(r) {
+				total += 0.5
+			} else if unicode.IsLetter(prev) && unicode.IsLower(prev) {
+				total -= 1
+			}
+		case strings.ContainsRune(commonPunctuation, r):
+			total += 0.5
+		default:
+			total -= 3
+		}
+		prev = r
+	}
+	if count == 0 {
+		return 0
+	}
+	return total / count
+}
+
+// commonPunctuation are the non ASCII symbols often found in subtitles
+const commonPunctuation = "‘’‚“”„«»‹›–—…•€£ ¡¿♪♫·°"
+
+func clamp(v, min, max float64) float64 {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
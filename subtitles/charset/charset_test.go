@@ -0,0 +1,68 @@
+package charset
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var samples = []struct {
+	lang, charset, text string
+}{
+	{"fr", "windows-1252", "1\n00:00:01,000 --> 00:00:02,000\nJe suis désolé, c'était à côté de la forêt.\nÇa va très bien, merci.\n"},
+	{"de", "windows-1252", "Wir müssen schnell über die Straße gehen, Herr Müller.\n"},
+	{"pl", "windows-1250", "Zażółć gęślą jaźń. Nie wiem, co się stało z łódką.\n"},
+	{"cs", "windows-1250", "Příliš žluťoučký kůň úpěl ďábelské ódy. Děkuji, že jste přišli.\n"},
+	{"ru", "windows-1251", "Привет! Как дела? Я не знаю, что случилось вчера вечером.\n"},
+	{"ru", "KOI8-R", "Привет! Как дела? Я не знаю, что случилось вчера вечером.\n"},
+	{"el", "windows-1253", "Καλημέρα, τι κάνεις; Δεν ξέρω τι έγινε χθες το βράδυ.\n"},
+	{"tr", "windows-1254", "Günaydın, nasılsın? Dün gece ne olduğunu bilmiyorum, şimdi çıkıyoruz.\n"},
+	{"zh", "GB18030", "你好，我是中国人。我们到这里来说话。\n"},
+	{"pt-BR", "windows-1252", "Não sei, você está falando sério? Ação!\n"},
+	{"zh-Hant", "Big5", "你好，我是中國人。我們到這裡來說話。\n"},
+	{"", "windows-1251", "Привет! Как дела? Я не знаю, что случилось вчера вечером.\n"},
+	{"", "windows-1252", "Je suis désolé, c'était à côté de la forêt. Ça va très bien.\n"},
+}
+
+func TestDetectShouldFindLegacyEncodings(t *testing.T) {
+	for _, s := range samples {
+		data, err := encodings[s.charset].NewEncoder().Bytes([]byte(s.text))
+		assert.Nil(t, err)
+		r := Detect(data, s.lang)
+		assert.Equal(t, s.charset, r.Name, s.lang+" "+s.text)
+
+		utf8, _, err := ToUTF8(data, s.lang)
+		assert.Nil(t, err)
+		assert.Equal(t, s.text, string(utf8))
+	}
+}
+
+func TestDetectShouldRecognizeUnicode(t *testing.T) {
+	assert.Equal(t, Result{UTF8, 1}, Detect([]byte("Déjà vu"), "fr"))
+	assert.Equal(t, Result{UTF8, 1}, Detect([]byte("Hello"), ""))
+
+	utf8, r, err := ToUTF8([]byte("\xEF\xBB\xBFDéjà vu"), "fr")
+	assert.Nil(t, err)
+	assert.Equal(t, UTF8, r.Name)
+	assert.Equal(t, "Déjà vu", string(utf8))
+
+	utf8, r, err = ToUTF8([]byte("\xFF\xFEH\x00\xe9\x00l\x00l\x00o\x00"), "")
+	assert.Nil(t, err)
+	assert.Equal(t, "UTF-16LE", r.Name)
+	assert.Equal(t, "Héllo", string(utf8))
+
+	r = Detect([]byte("H\x00e\x00l\x00l\x00o\x00 \x00w\x00o\x00r\x00l\x00d\x00"), "")
+	assert.Equal(t, "UTF-16LE", r.Name)
+}
+
+func TestWithBOMShouldAddBOMOnce(t *testing.T) {
+	assert.Equal(t, "\xEF\xBB\xBFHello", string(WithBOM([]byte("Hello"))))
+	assert.Equal(t, "\xEF\xBB\xBFHello", string(WithBOM(WithBOM([]byte("Hello")))))
+}
+
+func TestAllProfilesShouldBeTriedForUnknownLanguages(t *testing.T) {
+	assert.Equal(t, len(profiles), len(languagesByUsage))
+	for _, l := range languagesByUsage {
+		assert.Contains(t, profiles, l)
+	}
+}
@@ -0,0 +1,138 @@
+package charset
+
+import (
+	"strings"
+	"unicode"
+)
+
+// profile is what is expected from the texts of a language
+type profile struct {
+	charsets []string            // Legacy encodings used for the language, most common first
+	script   *unicode.RangeTable // Script of the letters, nil if unknown
+	alphabet map[rune]bool       // Non ASCII letters of the language, nil to accept all the letters of the script
+	frequent map[rune]bool       // Most frequent non ASCII letters or characters of the language
+}
+
+var (
+	westernCharsets  = []string{"windows-1252", "ISO-8859-15", "ISO-8859-1"}
+	centralCharsets  = []string{"windows-1250", "ISO-8859-2", "ISO-8859-16"}
+	cyrillicCharsets = []string{"windows-1251", "KOI8-R", "KOI8-U", "ISO-8859-5", "IBM866"}
+	balticCharsets   = []string{"windows-1257", "ISO-8859-13", "ISO-8859-4"}
+)
+
+// Frequent characters of Chinese, in simplified and traditional script
+const frequentHanzi = "的一是不了人我在有他这這中大来來上个個们們到说說国國和地也子时時道出而要于於就下得可你年生会會那后後能对對着著事"
+
+// profiles of the languages, by ISO 639-1 code or BCP 47 tag in lower case. Accented letters are given in lower case
+var profiles = map[string]profile{
+	"en": latin(westernCharsets, "éèçñ", ""),
+	"fr": latin(westernCharsets, "éèêëàâçùûüôîïœæÿ", "éèàç"),
+	"de": latin(westernCharsets, "äöüß", "äöüß"),
+	"es": latin(westernCharsets, "ñáéíóúü¡¿", "ñáéíóú"),
+	"pt": latin(westernCharsets, "ãõáéíóúâêôçà", "ãçéá"),
+	"it": latin(westernCharsets, "àèéìíòóù", "àèéìòù"),
+	"ca": latin(westernCharsets, "àèéíïòóúüç·", "àèéç"),
+	"nl": latin(westernCharsets, "éëïöüèç", "ëé"),
+	"da": latin(westernCharsets, "æøåé", "æøå"),
+	"no": latin(westernCharsets, "æøåé", "æøå"),
+	"nb": latin(westernCharsets, "æøåé", "æøå"),
+	"sv": latin(westernCharsets, "åäöé", "åäö"),
+	"fi": latin(westernCharsets, "äöåšž", "äö"),
+	"is": latin(westernCharsets, "áðéíóúýþæö", "áðþæ"),
+	"ga": latin(westernCharsets, "áéíóú", "áéíóú"),
+	"eu": latin(westernCharsets, "ñ", "ñ"),
+	"gl": latin(westernCharsets, "ñáéíóú", "ñáéíóú"),
+	"id": latin(westernCharsets, "é", ""),
+	"ms": latin(westernCharsets, "é", ""),
+	"pl": latin(centralCharsets, "ąćęłńóśźż", "ąęłóśż"),
+	"cs": latin(centralCharsets, "áčďéěíňóřšťúůýž", "áčéěířšýž"),
+	"sk": latin(centralCharsets, "áäčďéíĺľňóôŕšťúýž", "áčéíšýž"),
+	"hu": latin(centralCharsets, "áéíóöőúüű", "áéóöő"),
+	"ro": latin(centralCharsets, "ăâîșțşţ", "ăâîșțşţ"),
+	"hr": latin(centralCharsets, "čćđšž", "čćšž"),
+	"bs": latin(centralCharsets, "čćđšž", "čćšž"),
+	"sl": latin(centralCharsets, "čšž", "čšž"),
+	"sq": latin(centralCharsets, "çë", "çë"),
+	"tr": latin([]string{"windows-1254", "ISO-8859-9"}, "çğıöşüâîû", "çğıöşü"),
+	"az": latin([]string{"windows-1254", "ISO-8859-9"}, "çğıöşüə", "çğıöşü"),
+	"lt": latin(balticCharsets, "ąčęėįšųūž", "ąčėįšųūž"),
+	"lv": latin(balticCharsets, "āčēģīķļņšūž", "āēīšū"),
+	"et": latin(balticCharsets, "äöõüšž", "äõü"),
+	"vi": {charsets: []string{"windows-1258"}, script: unicode.Latin},
+	"ru": cyrillic([]string{"windows-1251", "KOI8-R", "ISO-8859-5", "IBM866"}, "оеаинтсрвл"),
+	"uk": cyrillic([]string{"windows-1251", "KOI8-U", "ISO-8859-5", "IBM866"}, "оанивірте"),
+	"be": cyrillic(cyrillicCharsets, "аоеніыру"),
+	"bg": cyrillic(cyrillicCharsets, "аоиентсрвлкдп"),
+	"mk": cyrillic(cyrillicCharsets, "аоеинтсрвлкдп"),
+	// Serbian is written in both scripts
+	"sr": {charsets: []string{"windows-1250", "windows-1251", "ISO-8859-2", "ISO-8859-5"}, alphabet: runeSet("čćđšžабвгдђежзијклљмнњопрстћуфхцчџш"), frequent: runes("čšžаоеиснт")},
+	"el": {charsets: []string{"windows-1253", "ISO-8859-7"}, script: unicode.Greek, frequent: runes("αοετινσυρκπμλ")},
+	"he": {charsets: []string{"windows-1255", "ISO-8859-8"}, script: unicode.Hebrew, frequent: runes("יהולאמתבשנר")},
+	"ar": {charsets: []string{"windows-1256", "ISO-8859-6"}, script: unicode.Arabic, frequent: runes("الينمهوترب")},
+	"fa": {charsets: []string{"windows-1256"}, script: unicode.Arabic, frequent: runes("ایندمهورتب")},
+	"ur": {charsets: []string{"windows-1256"}, script: unicode.Arabic},
+	"th": {charsets: []string{"windows-874"}, script: unicode.Thai},
+	"zh": {charsets: []string{"GB18030", "Big5"}, script: unicode.Han, frequent: runes(frequentHanzi + "，。？！")},
+	"ja": {charsets: []string{"Shift_JIS", "EUC-JP"}, frequent: runes("のはにをたがでてとしれいかなっすまあるこ。、")},
+	"ko": {charsets: []string{"EUC-KR"}, script: unicode.Hangul},
+
+	// Traditional Chinese is mostly encoded in Big5, in Taiwan and Hong Kong
+	"zh-hant": {charsets: []string{"Big5", "GB18030"}, script: unicode.Han, frequent: runes(frequentHanzi + "，。？！")},
+}
+
+// languagesByUsage orders the profiles for unknown languages, so that the most used encodings are tried first
+var languagesByUsage = []string{
+	"en", "fr", "de", "es", "pt", "it", "ca", "nl", "da", "no", "nb", "sv", "fi", "is", "ga", "eu", "gl", "id", "ms",
+	"pl", "cs", "sk", "hu", "ro", "hr", "bs", "sl", "sq", "ru", "uk", "be", "bg", "mk", "sr", "el", "tr", "az",
+	"lt", "lv", "et", "he", "ar", "fa", "ur", "th", "vi", "zh", "zh-hant", "ja", "ko",
+}
+
+// profilesOf gives the profile of a language. Tags without profile (pt-BR) get the one of their language (pt).
+// All the profiles are given for an unknown language
+func profilesOf(lang string) []profile {
+	lang = strings.ToLower(lang)
+	for lang != "" {
+		if p, ok := profiles[lang]; ok {
+			return []profile{p}
+		}
+		i := strings.LastIndex(lang, "-")
+		if i < 0 {
+			break
+		}
+		lang = lang[:i]
+	}
+	all := make([]profile, 0, len(profiles))
+	for _, l := range languagesByUsage {
+		all = append(all, profiles[l])
+	}
+	return all
+}
+
+// latin builds the profile of a language written with the latin script
+func latin(charsets []string, alphabet, frequent string) profile {
+	return profile{charsets: charsets, script: unicode.Latin, alphabet: runeSet(alphabet), frequent: runes(frequent)}
+}
+
+// cyrillic builds the profile of a language written with the cyrillic script
+func cyrillic(charsets []string, frequent string) profile {
+	return profile{charsets: charsets, script: unicode.Cyrillic, frequent: runes(frequent)}
+}
+
+// runes gives the set of runes of a string
+func runes(s string) map[rune]bool {
+	set := map[rune]bool{}
+	for _, r := range s {
+		set[r] = true
+	}
+	return set
+}
+
+// runeSet gives the set of runes of a string, in lower and upper case
+func runeSet(s string) map[rune]bool {
+	set := map[rune]bool{}
+	for _, r := range s {
+		set[r] = true
+		set[unicode.ToUpper(r)] = true
+	}
+	return set
+}
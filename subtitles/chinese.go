@@ -0,0 +1,63 @@
+package subtitles
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/matcornic/subify/subtitles/format"
+	"github.com/matcornic/subify/subtitles/zhconv"
+	logger "github.com/spf13/jwalterweatherman"
+)
+
+// chineseCounterpart gives the Chinese language written in the other script (Traditional for Simplified),
+// whose subtitles can be converted to the language. It is nil for the other languages
+func chineseCounterpart(language Language) *Language {
+	script, ok := zhconv.ScriptOf(language.Tag)
+	if !ok {
+		return nil
+	}
+	if script == zhconv.Simplified {
+		return Languages.GetLanguageByCode("zh-" + string(zhconv.Traditional))
+	}
+	return Languages.GetLanguageByCode("zh-" + string(zhconv.Simplified))
+}
+
+// chineseLanguage gives the Chinese language of a text written in Simplified or Traditional characters
+func chineseLanguage(text string) *Language {
+	script, ok := zhconv.Detect(text)
+	if !ok {
+		return nil
+	}
+	return Languages.GetLanguageByCode("zh-" + string(script))
+}
+
+// relabelConverted renames a subtitle downloaded in the counterpart of a language with this language,
+// as it is converted to it. The subtitle is kept under its name when it can't be renamed
+func relabelConverted(videoPath, subtitlePath string, language Language, forced bool) string {
+	converted := subtitlePathFor(videoPath, subtitleTag(language.Tag, forced), filepath.Ext(subtitlePath))
+	if err := os.Rename(subtitlePath, converted); err != nil {
+		logger.WARN.Println("Can't rename the subtitle", subtitlePath, "because of :", err)
+		return subtitlePath
+	}
+	return converted
+}
+
+// convertChineseFile converts a Chinese subtitle to the script of its language (zh-Hans or zh-Hant)
+func convertChineseFile(subtitlePath string, language Language) error {
+	to, ok := zhconv.ScriptOf(language.Tag)
+	if !ok {
+		return nil
+	}
+	s, f, err := format.ReadFile(subtitlePath, format.Options{})
+	if err != nil {
+		return err
+	}
+	if zhconv.Convert(s, to) == 0 {
+		return nil
+	}
+	if err := format.PatchFile(subtitlePath, subtitlePath, s, f, format.Options{}); err != nil {
+		return err
+	}
+	logger.INFO.Println("Subtitle converted to", language.Description)
+	return nil
+}
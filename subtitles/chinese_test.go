@@ -0,0 +1,50 @@
+package subtitles
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const simplifiedSubtitle = `1
+00:00:01,000 --> 00:00:03,000
+<font face="KaiTi">你在这里干什么？我们已经等了你很久。</font>
+
+2
+00:00:04,000 --> 00:00:06,000
+她的头发很长，下周一我们再见面吧。
+`
+
+func TestChineseCounterpartShouldGiveTheOtherScript(t *testing.T) {
+	assert.Equal(t, "zh-Hant", chineseCounterpart(*Languages.GetLanguage("zh-Hans")).Tag)
+	assert.Equal(t, "zh-Hans", chineseCounterpart(*Languages.GetLanguage("zht")).Tag)
+	assert.Nil(t, chineseCounterpart(*Languages.GetLanguage("chi")))
+	assert.Nil(t, chineseCounterpart(*Languages.GetLanguage("en")))
+}
+
+func TestConvertChineseFileShouldConvertToTheScriptOfTheLanguage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "subify")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	subtitlePath := filepath.Join(dir, "Movie.zh-Hant.srt")
+	assert.Nil(t, ioutil.WriteFile(subtitlePath, []byte(simplifiedSubtitle), 0644))
+
+	l, err := DetectLanguage(subtitlePath)
+	assert.Nil(t, err)
+	assert.Equal(t, "zh-Hans", l.Tag)
+
+	assert.Nil(t, convertChineseFile(subtitlePath, *Languages.GetLanguage("zh-Hant")))
+	data, err := ioutil.ReadFile(subtitlePath)
+	assert.Nil(t, err)
+	assert.Equal(t, strings.NewReplacer(
+		"你在这里干什么？我们已经等了你很久。", "你在這裡幹什麼？我們已經等了你很久。",
+		"她的头发很长，下周一我们再见面吧。", "她的頭髮很長，下週一我們再見面吧。",
+	).Replace(simplifiedSubtitle), string(data))
+	l, err = DetectLanguage(subtitlePath)
+	assert.Nil(t, err)
+	assert.Equal(t, "zh-Hant", l.Tag)
+}
@@ -0,0 +1,104 @@
+// Package clean removes the ads, credits and uploader spam added at the start and the end of subtitles
+package clean
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/matcornic/subify/subtitles/format"
+)
+
+// DefaultCues is the number of cues looked at, at the start and at the end of a subtitle
+const DefaultCues = 5
+
+// Patterns are the built-in regular expressions of the lines to remove. They are matched without case and tags
+var Patterns = []string{
+	// Credits of the people making the subtitle
+	`\b(subtitles?|subs|subtitled|captions?|captioned|captioning|transcript|transcribed|translations?|translated|synced|sync|synchroni[sz]ed|resynced|corrected|corrections|encoded|ripped|timing|timed)\b.*\bby\b`,
+	`^\W*(sync|synchro)\b.*\b(and|&)\b.*\bcorrections?\b`,
+	// Links and subtitles sites
+	`\b(https?://|www\.)`,
+	`\b[a-z0-9-]+\.(com|org|net|tv|io|cc|me|info|to|xyz)\b`,
+	`\b(opensubtitles|addic7ed|subscene|podnapisi|yify|yts|titlovi|subdl|subdb|napisy|titulky|legendas\.tv|jimaku)\b`,
+	// Ads and calls to support the uploader
+	`\b(advertise (your|here)|ads? by|your (ad|product|brand) here|support us|become (a )?vip|donate|please rate|rate this subtitle)\b`,
+	`^\W*(downloaded from|visit|brought to you by|presented by)\b`,
+}
+
+// Options changes how subtitles are cleaned
+type Options struct {
+	Patterns []string // Regular expressions added to the built-in ones
+	Cues     int      // Number of cues looked at, at the start and at the end. DefaultCues when 0
+}
+
+// Clean removes the matching lines of the first and last cues of a subtitle, and the cues left empty.
+// Only these cues are looked at, to avoid removing the dialogues. It gives the removed lines
+func Clean(s *format.Subtitle, opts Options) ([]string, error) {
+	patterns, err := compile(append(append([]string{}, Patterns...), opts.Patterns...))
+	if err != nil {
+		return nil, err
+	}
+	n := opts.Cues
+	if n <= 0 {
+		n = DefaultCues
+	}
+
+	var removed []string
+	var kept []*format.Cue
+	for i, c := range s.Cues {
+		if i >= n && i < len(s.Cues)-n {
+			kept = append(kept, c)
+			continue
+		}
+		var lines []string
+		for _, l := range c.Lines {
+			if matches(patterns, l) {
+				removed = append(removed, format.StripTags(l))
+				continue
+			}
+			lines = append(lines, l)
+		}
+		if len(lines) == len(c.Lines) {
+			kept = append(kept, c)
+			continue
+		}
+		if strings.TrimSpace(format.StripTags(strings.Join(lines, ""))) == "" {
+			continue
+		}
+		c.Lines = lines
+		kept = append(kept, c)
+	}
+	if len(removed) > 0 {
+		s.Cues = kept
+		s.Renumber()
+	}
+	return removed, nil
+}
+
+// compile compiles the patterns, without case
+func compile(patterns []string) ([]*regexp.Regexp, error) {
+	var compiled []*regexp.Regexp
+	for _, p := range patterns {
+		if strings.TrimSpace(p) == "" {
+			continue
+		}
+		r, err := regexp.Compile("(?i)" + p)
+		if err != nil {
+			return nil, fmt.Errorf("Can't use the cleaning pattern %v because of : %v", p, err)
+		}
+		compiled = append(compiled, r)
+	}
+	return compiled, nil
+}
+
+// matches tells if a line matches one of the patterns
+func matches(patterns []*regexp.Regexp, line string) bool {
+	plain := strings.TrimSpace(format.StripTags(line))
+	for _, p := range patterns {
+		if p.MatchString(plain) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package clean
+
+import (
+	"testing"
+	"time"
+
+	"github.com/matcornic/subify/subtitles/format"
+	"github.com/stretchr/testify/assert"
+)
+
+func subtitle(lines ...[]string) *format.Subtitle {
+	s := &format.Subtitle{}
+	for i, l := range lines {
+		s.Cues = append(s.Cues, &format.Cue{Index: i + 1, Start: time.Duration(i) * time.Second, End: time.Duration(i+1) * time.Second, Lines: l})
+	}
+	return s
+}
+
+func TestCleanShouldRemoveAdsAtStartAndEnd(t *testing.T) {
+	s := subtitle(
+		[]string{"<font color=\"#ffff00\">Subtitles by XYZ</font>"},
+		[]string{"Where were you?", "www.example-subs.com"},
+		[]string{"At the office."},
+		[]string{"Did you see the site Bob made?"},
+		[]string{"Come on, let's go."},
+		[]string{"Sync & corrections by someone"},
+		[]string{"Downloaded from OpenSubtitles"},
+	)
+	removed, err := Clean(s, Options{Cues: 2})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Subtitles by XYZ", "www.example-subs.com", "Sync & corrections by someone", "Downloaded from OpenSubtitles"}, removed)
+	if assert.Equal(t, 4, len(s.Cues)) {
+		assert.Equal(t, []string{"Where were you?"}, s.Cues[0].Lines)
+		assert.Equal(t, 1, s.Cues[0].Index)
+		assert.Equal(t, "Come on, let's go.", s.Cues[3].Text())
+	}
+}
+
+func TestCleanShouldOnlyLookAtFirstAndLastCues(t *testing.T) {
+	s := subtitle(
+		[]string{"Hello"},
+		[]string{"Translated by the best team"},
+		[]string{"Goodbye"},
+	)
+	removed, err := Clean(s, Options{Cues: 1})
+	assert.Nil(t, err)
+	assert.Empty(t, removed)
+	assert.Equal(t, 3, len(s.Cues))
+}
+
+func TestCleanShouldUseExtraPatterns(t *testing.T) {
+	s := subtitle([]string{"Team Awesome presents"}, []string{"Hello"})
+	removed, err := Clean(s, Options{Patterns: []string{`^team awesome\b`}})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Team Awesome presents"}, removed)
+
+	_, err = Clean(s, Options{Patterns: []string{`(`}})
+	assert.NotNil(t, err)
+}
+
+func TestHearingImpairedShouldRemoveAnnotations(t *testing.T) {
+	s := subtitle(
+		[]string{"[DOOR CLOSES]"},
+		[]string{"- JOHN: Where were you?", "- (laughs) At the office."},
+		[]string{"<i>♪ Happy birthday to you ♪</i>"},
+		[]string{"- [sighs]", "- Note: it's 10:30."},
+		[]string{"(GUNSHOT)", "MAN #2: Get down!"},
+	)
+	assert.Equal(t, 5, HearingImpaired(s))
+	if assert.Equal(t, 4, len(s.Cues)) {
+		assert.Equal(t, []string{"- Where were you?", "- At the office."}, s.Cues[0].Lines)
+		assert.Equal(t, []string{"<i>Happy birthday to you</i>"}, s.Cues[1].Lines)
+		assert.Equal(t, []string{"Note: it's 10:30."}, s.Cues[2].Lines)
+		assert.Equal(t, []string{"Get down!"}, s.Cues[3].Lines)
+		assert.Equal(t, 4, s.Cues[3].Index)
+	}
+}
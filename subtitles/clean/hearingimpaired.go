@@ -0,0 +1,68 @@
+package clean
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/matcornic/subify/subtitles/format"
+)
+
+var (
+	// Sound descriptions, like [DOOR CLOSES] or (laughs). They can be on several lines
+	soundRegexp = regexp.MustCompile(`\[[^\]]*\]|\([^)]*\)`)
+	// Names of the speakers, like "JOHN:" or "- MAN #2:". Only upper case names, to keep dialogues like "Note: ..."
+	speakerRegexp  = regexp.MustCompile(`(?m)^(\s*(?:<[^>]+>)*\s*-?\s*)[A-Z][A-Z0-9 .'#&-]*[A-Z0-9]\s*:\s*`)
+	musicRegexp    = regexp.MustCompile(`[♪♫♬♩]+`)
+	emptyTagRegexp = regexp.MustCompile(`<(i|b|u|s|font[^>]*)>\s*</(i|b|u|s|font)>`)
+	spacesRegexp   = regexp.MustCompile(`[ \t]{2,}`)
+	openingRegexp  = regexp.MustCompile(`(<[^/>][^>]*>)[ \t]+`)
+	closingRegexp  = regexp.MustCompile(`[ \t]+(</[^>]+>)`)
+)
+
+// HearingImpaired removes the annotations for the hearing impaired (SDH): sound descriptions between brackets
+// or parentheses, names of the speakers and music notes. Cues left empty are removed.
+// It gives the number of cues changed or removed
+func HearingImpaired(s *format.Subtitle) int {
+	changed := 0
+	var kept []*format.Cue
+	for _, c := range s.Cues {
+		text := strings.Join(c.Lines, "\n")
+		stripped := soundRegexp.ReplaceAllString(text, "")
+		stripped = speakerRegexp.ReplaceAllString(stripped, "$1")
+		stripped = musicRegexp.ReplaceAllString(stripped, "")
+		stripped = emptyTagRegexp.ReplaceAllString(stripped, "")
+		stripped = openingRegexp.ReplaceAllString(closingRegexp.ReplaceAllString(stripped, "$1"), "$1")
+		if stripped == text {
+			kept = append(kept, c)
+			continue
+		}
+		changed++
+
+		var lines []string
+		for _, l := range strings.Split(stripped, "\n") {
+			l = strings.TrimSpace(spacesRegexp.ReplaceAllString(l, " "))
+			plain := strings.TrimSpace(format.StripTags(l))
+			if plain == "" || plain == "-" {
+				continue
+			}
+			lines = append(lines, l)
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		// A dialogue reduced to one speaker is no more a dialogue
+		if len(lines) == 1 {
+			lines[0] = strings.Replace(lines[0], "- ", "", 1)
+			if strings.HasPrefix(format.StripTags(lines[0]), "-") {
+				lines[0] = strings.Replace(lines[0], "-", "", 1)
+			}
+		}
+		c.Lines = lines
+		kept = append(kept, c)
+	}
+	if changed > 0 {
+		s.Cues = kept
+		s.Renumber()
+	}
+	return changed
+}
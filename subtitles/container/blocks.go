@@ -0,0 +1,267 @@
+package container
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+)
+
+// Packet is a frame of a track: the text of a subtitle cue for text subtitles
+type Packet struct {
+	Start    time.Duration
+	Duration time.Duration // 0 when unknown
+	Data     []byte
+}
+
+// Matroska element IDs of the clusters
+const (
+	idInfo           = 0x1549A966
+	idTimestampScale = 0x2AD7B1
+	idTimestamp      = 0xE7
+	idSimpleBlock    = 0xA3
+	idBlockGroup     = 0xA0
+	idBlock          = 0xA1
+	idBlockDuration  = 0x9B
+	// Elements found at the top level of segments, ending clusters of unknown size
+	idCues        = 0x1C53BB6B
+	idChapters    = 0x1043A770
+	idTags        = 0x1254C367
+	idAttachments = 0x1941A469
+
+	defaultTimestampScale = 1000000
+)
+
+// Compression algorithms of Matroska blocks
+const (
+	compressionZlib            = 0
+	compressionHeaderStripping = 3
+)
+
+// compression is the compression of the blocks of a Matroska track
+type compression struct {
+	algo     uint64
+	settings []byte
+	private  bool // The codec private data is compressed too
+}
+
+// decompress decompresses a block or the codec private data
+func (c *compression) decompress(data []byte) ([]byte, error) {
+	switch c.algo {
+	case compressionZlib:
+		z, err := zlib.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("Can't decompress a Matroska block because of : %v", err)
+		}
+		defer z.Close()
+		return ioutil.ReadAll(z)
+	case compressionHeaderStripping:
+		return append(append([]byte{}, c.settings...), data...), nil
+	}
+	return nil, fmt.Errorf("Matroska compression %v is not supported", c.algo)
+}
+
+// matroskaCompression reads the ContentEncodings element of a track, nil when blocks are not compressed
+func matroskaCompression(r io.ReaderAt, encodings element) (*compression, error) {
+	var c *compression
+	err := readChildren(r, encodings.Offset, encodings.Offset+encodings.Size, func(encoding element) (bool, error) {
+		if encoding.ID != idContentEncoding {
+			return true, nil
+		}
+		scope := uint64(1)
+		var found *compression
+		err := readChildren(r, encoding.Offset, encoding.Offset+encoding.Size, func(e element) (bool, error) {
+			var err error
+			switch e.ID {
+			case idContentScope:
+				scope, err = readUint(r, e)
+			case idCompression:
+				found = &compression{algo: compressionZlib}
+				err = readChildren(r, e.Offset, e.Offset+e.Size, func(setting element) (bool, error) {
+					var err error
+					switch setting.ID {
+					case idCompAlgo:
+						found.algo, err = readUint(r, setting)
+					case idCompSettings:
+						found.settings, err = readData(r, setting)
+					}
+					return true, err
+				})
+			case idEncryption:
+				return false, errors.New("Encrypted Matroska tracks are not supported")
+			}
+			return true, err
+		})
+		if found != nil {
+			found.private = scope&2 != 0
+			c = found
+		}
+		return false, err
+	})
+	return c, err
+}
+
+// isTopLevel tells if an element can only be a child of the segment
+func isTopLevel(id uint32) bool {
+	switch id {
+	case idCluster, idCues, idChapters, idTags, idAttachments, idTracks, idInfo, idSeekHead:
+		return true
+	}
+	return false
+}
+
+// ReadPackets reads the packets of a track of a Matroska or MP4 video
+func ReadPackets(path string, number int) (Track, []Packet, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Track{}, nil, fmt.Errorf("Can't open the file %v because of : %v", path, err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return Track{}, nil, fmt.Errorf("Can't open the file %v because of : %v", path, err)
+	}
+	return Packets(f, info.Size(), number)
+}
+
+// Packets reads the packets of a track of a Matroska or MP4 video of the given size
+func Packets(r io.ReaderAt, size int64, number int) (Track, []Packet, error) {
+	tracks, err := Tracks(r, size)
+	if err != nil {
+		return Track{}, nil, err
+	}
+	for _, t := range tracks {
+		if t.Number != number {
+			continue
+		}
+		var packets []Packet
+		if t.timescale != 0 {
+			packets, err = mp4Packets(r, size, t)
+		} else {
+			packets, err = matroskaPackets(r, size, t)
+		}
+		return t, packets, err
+	}
+	return Track{}, nil, fmt.Errorf("No track %v in the video", number)
+}
+
+// matroskaPackets reads the blocks of a track in the clusters of a Matroska file
+func matroskaPackets(r io.ReaderAt, size int64, track Track) ([]Packet, error) {
+	segment, err := matroskaSegment(r, size)
+	if err != nil {
+		return nil, err
+	}
+	scale := timestampScale(r, segment, size)
+
+	var packets []Packet
+	end := segment.End(size)
+	for offset := segment.Offset; offset < end; {
+		cluster, err := readElement(r, offset)
+		if err != nil {
+			break
+		}
+		if cluster.ID != idCluster {
+			if cluster.Size == unknownElementSize {
+				break
+			}
+			offset = cluster.Offset + cluster.Size
+			continue
+		}
+
+		var clusterTime uint64
+		next := cluster.Offset
+		for next < cluster.End(end) {
+			e, err := readElement(r, next)
+			if err != nil || (cluster.Size == unknownElementSize && isTopLevel(e.ID)) {
+				break
+			}
+			if e.Size == unknownElementSize {
+				return nil, errors.New("Invalid Matroska cluster")
+			}
+			switch e.ID {
+			case idTimestamp:
+				clusterTime, err = readUint(r, e)
+			case idSimpleBlock:
+				err = readBlock(r, e, 0, track, clusterTime, scale, &packets)
+			case idBlockGroup:
+				var block *element
+				var duration uint64
+				err = readChildren(r, e.Offset, e.Offset+e.Size, func(child element) (bool, error) {
+					var err error
+					switch child.ID {
+					case idBlock:
+						block = &child
+					case idBlockDuration:
+						duration, err = readUint(r, child)
+					}
+					return true, err
+				})
+				if err == nil && block != nil {
+					err = readBlock(r, *block, duration, track, clusterTime, scale, &packets)
+				}
+			}
+			if err != nil {
+				return nil, err
+			}
+			next = e.Offset + e.Size
+		}
+		offset = next
+	}
+	return packets, nil
+}
+
+// timestampScale gives the nanoseconds of a timestamp unit of the segment
+func timestampScale(r io.ReaderAt, segment element, size int64) uint64 {
+	scale := uint64(defaultTimestampScale)
+	if info, err := findTopLevel(r, segment, size, idInfo); err == nil {
+		_ = readChildren(r, info.Offset, info.End(size), func(e element) (bool, error) {
+			if e.ID == idTimestampScale {
+				scale, err = readUint(r, e)
+			}
+			return true, err
+		})
+	}
+	if scale == 0 {
+		scale = defaultTimestampScale
+	}
+	return scale
+}
+
+// readBlock reads a block of the track, and ignores the blocks of other tracks
+func readBlock(r io.ReaderAt, block element, duration uint64, track Track, clusterTime, scale uint64, packets *[]Packet) error {
+	number, length, err := readVint(r, block.Offset, false)
+	if err != nil {
+		return err
+	}
+	if int(number) != track.Number {
+		return nil
+	}
+	header := make([]byte, 3)
+	if _, err := r.ReadAt(header, block.Offset+int64(length)); err != nil {
+		return err
+	}
+	if header[2]&0x06 != 0 {
+		return errors.New("Laced Matroska blocks are not supported for subtitles")
+	}
+	data, err := readData(r, element{ID: block.ID, Offset: block.Offset + int64(length) + 3, Size: block.Size - int64(length) - 3})
+	if err != nil {
+		return err
+	}
+	if track.compression != nil {
+		if data, err = track.compression.decompress(data); err != nil {
+			return err
+		}
+	}
+	timestamp := int64(clusterTime) + int64(int16(binary.BigEndian.Uint16(header[:2])))
+	*packets = append(*packets, Packet{
+		Start:    time.Duration(timestamp * int64(scale)),
+		Duration: time.Duration(duration * scale),
+		Data:     data,
+	})
+	return nil
+}
@@ -0,0 +1,80 @@
+// Package container reads the tracks of video containers: Matroska (MKV, WebM) and MP4 (MP4, M4V, MOV)
+package container
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// TrackType is the kind of content of a track
+type TrackType string
+
+// Types of tracks
+const (
+	Video    TrackType = "video"
+	Audio    TrackType = "audio"
+	Subtitle TrackType = "subtitle"
+	Other    TrackType = "other"
+)
+
+// Track describes a track of a video container
+type Track struct {
+	Number          int // Number of the track in the container (Matroska TrackNumber, MP4 track_ID)
+	Type            TrackType
+	Codec           string // Codec of the track (S_TEXT/UTF8, S_TEXT/ASS, tx3g, wvtt...)
+	Language        string // ISO 639-2 code or BCP 47 tag (en-US), "und" when unknown
+	Name            string // Title of the track, if any
+	Default         bool   // Selected by players when nothing else is asked
+	Forced          bool   // Only for the foreign parts of the video
+	HearingImpaired bool   // Subtitles for the hearing impaired
+	CodecPrivate    []byte // Initialization data of the codec, like the header of ASS subtitles
+
+	compression *compression // Compression of the Matroska blocks
+	timescale   uint32       // Units of the MP4 times in a second
+}
+
+// ErrUnknownContainer is returned for files which are neither Matroska nor MP4
+var ErrUnknownContainer = errors.New("Not a Matroska or MP4 video")
+
+// ReadTracks lists the tracks of a Matroska or MP4 video
+func ReadTracks(path string) ([]Track, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("Can't open the file %v because of : %v", path, err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("Can't open the file %v because of : %v", path, err)
+	}
+	return Tracks(f, info.Size())
+}
+
+// Tracks lists the tracks of a Matroska or MP4 video of the given size
+func Tracks(r io.ReaderAt, size int64) ([]Track, error) {
+	header := make([]byte, 8)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return nil, ErrUnknownContainer
+	}
+	switch {
+	case bytes.Equal(header[:4], []byte{0x1A, 0x45, 0xDF, 0xA3}):
+		return matroskaTracks(r, size)
+	case isMP4Box(string(header[4:8])):
+		return mp4Tracks(r, size)
+	}
+	return nil, ErrUnknownContainer
+}
+
+// SubtitleTracks keeps the subtitle tracks
+func SubtitleTracks(tracks []Track) []Track {
+	var subtitles []Track
+	for _, t := range tracks {
+		if t.Type == Subtitle {
+			subtitles = append(subtitles, t)
+		}
+	}
+	return subtitles
+}
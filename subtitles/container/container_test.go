@@ -0,0 +1,348 @@
+package container
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/matcornic/subify/subtitles/format"
+	"github.com/stretchr/testify/assert"
+)
+
+// el builds an EBML element, with a 8 bytes size
+func el(id uint32, children ...[]byte) []byte {
+	buf := new(bytes.Buffer)
+	idBytes := make([]byte, 4)
+	binary.BigEndian.PutUint32(idBytes, id)
+	for len(idBytes) > 1 && idBytes[0] == 0 {
+		idBytes = idBytes[1:]
+	}
+	buf.Write(idBytes)
+	data := bytes.Join(children, nil)
+	size := make([]byte, 8)
+	binary.BigEndian.PutUint64(size, uint64(len(data)))
+	size[0] = 0x01
+	buf.Write(size)
+	buf.Write(data)
+	return buf.Bytes()
+}
+
+func uintEl(id uint32, value byte) []byte {
+	return el(id, []byte{value})
+}
+
+func stringEl(id uint32, value string) []byte {
+	return el(id, []byte(value))
+}
+
+func sampleMatroska() []byte {
+	return bytes.Join([][]byte{
+		el(idEBML, stringEl(0x4282, "matroska")),
+		el(idSegment,
+			el(0x1549A966, uintEl(0x2AD7B1, 1)),
+			el(idTracks,
+				el(idTrackEntry, uintEl(idTrackNumber, 1), uintEl(idTrackType, matroskaVideo), stringEl(idCodecID, "V_MPEG4/ISO/AVC")),
+				el(idTrackEntry, uintEl(idTrackNumber, 2), uintEl(idTrackType, matroskaAudio), stringEl(idLanguage, "fre")),
+				el(idTrackEntry, uintEl(idTrackNumber, 3), uintEl(idTrackType, matroskaSubtitle), stringEl(idCodecID, "S_TEXT/UTF8"),
+					uintEl(idFlagDefault, 0), stringEl(idName, "English SDH"), uintEl(idFlagHI, 1)),
+				el(idTrackEntry, uintEl(idTrackNumber, 4), uintEl(idTrackType, matroskaSubtitle), stringEl(idCodecID, "S_TEXT/ASS"),
+					stringEl(idLanguage, "por"), stringEl(idLanguageBCP47, "pt-BR"), uintEl(idFlagForced, 1)),
+			),
+			el(idCluster, uintEl(0xE7, 0)),
+		),
+	}, nil)
+}
+
+func TestTracksShouldReadMatroska(t *testing.T) {
+	data := sampleMatroska()
+	tracks, err := Tracks(bytes.NewReader(data), int64(len(data)))
+	assert.Nil(t, err)
+	if assert.Equal(t, 4, len(tracks)) {
+		assert.Equal(t, Track{Number: 1, Type: Video, Codec: "V_MPEG4/ISO/AVC", Language: "eng", Default: true}, tracks[0])
+		assert.Equal(t, "fre", tracks[1].Language)
+		assert.Equal(t, Track{Number: 3, Type: Subtitle, Codec: "S_TEXT/UTF8", Language: "eng", Name: "English SDH", HearingImpaired: true}, tracks[2])
+		assert.Equal(t, Track{Number: 4, Type: Subtitle, Codec: "S_TEXT/ASS", Language: "pt-BR", Default: true, Forced: true}, tracks[3])
+	}
+	assert.Equal(t, 2, len(SubtitleTracks(tracks)))
+}
+
+// bx builds a MP4 box
+func bx(boxType string, children ...[]byte) []byte {
+	data := bytes.Join(children, nil)
+	header := make([]byte, 8)
+	binary.BigEndian.PutUint32(header, uint32(len(data)+8))
+	copy(header[4:], boxType)
+	return append(header, data...)
+}
+
+// trak builds a MP4 track box
+func trak(id uint32, enabled bool, handler, language string, entry []byte) []byte {
+	tkhd := make([]byte, 84)
+	if enabled {
+		tkhd[3] = 1
+	}
+	binary.BigEndian.PutUint32(tkhd[12:], id)
+	mdhd := make([]byte, 24)
+	packed := uint16(language[0]-0x60)<<10 | uint16(language[1]-0x60)<<5 | uint16(language[2]-0x60)
+	binary.BigEndian.PutUint16(mdhd[20:], packed)
+	hdlr := make([]byte, 25)
+	copy(hdlr[8:], handler)
+	stsd := append(make([]byte, 8), entry...)
+	return bx("trak", bx("tkhd", tkhd), bx("mdia", bx("mdhd", mdhd), bx("hdlr", hdlr), bx("minf", bx("stbl", bx("stsd", stsd)))))
+}
+
+func TestTracksShouldReadMP4(t *testing.T) {
+	forced := make([]byte, 12)
+	binary.BigEndian.PutUint32(forced[8:], tx3gAllSamplesForced)
+	data := bytes.Join([][]byte{
+		bx("ftyp", []byte("isom")),
+		bx("moov",
+			trak(1, true, "vide", "und", bx("avc1", make([]byte, 8))),
+			trak(2, true, "sbtl", "fra", bx("tx3g", forced)),
+			trak(3, false, "text", "eng", bx("wvtt", make([]byte, 8))),
+		),
+		bx("mdat", []byte("data")),
+	}, nil)
+	tracks, err := Tracks(bytes.NewReader(data), int64(len(data)))
+	assert.Nil(t, err)
+	if assert.Equal(t, 3, len(tracks)) {
+		assert.Equal(t, Track{Number: 1, Type: Video, Codec: "avc1", Language: "und", Default: true}, tracks[0])
+		assert.Equal(t, Track{Number: 2, Type: Subtitle, Codec: "tx3g", Language: "fra", Default: true, Forced: true}, tracks[1])
+		assert.Equal(t, Track{Number: 3, Type: Subtitle, Codec: "wvtt", Language: "eng"}, tracks[2])
+	}
+}
+
+func TestTracksShouldRejectOtherFiles(t *testing.T) {
+	data := []byte("RIFF....AVI LIST")
+	_, err := Tracks(bytes.NewReader(data), int64(len(data)))
+	assert.Equal(t, ErrUnknownContainer, err)
+}
+
+// block builds a Matroska block of a track, at a time relative to its cluster
+func block(track byte, time int16, data string) []byte {
+	header := []byte{0x80 | track, 0, 0, 0}
+	binary.BigEndian.PutUint16(header[1:], uint16(time))
+	return append(header, data...)
+}
+
+func zlibData(t *testing.T, data string) string {
+	buf := new(bytes.Buffer)
+	z := zlib.NewWriter(buf)
+	_, err := z.Write([]byte(data))
+	assert.Nil(t, err)
+	assert.Nil(t, z.Close())
+	return buf.String()
+}
+
+func TestExtractShouldDemuxMatroskaTextTracks(t *testing.T) {
+	assHeader := "[Script Info]\nScriptType: v4.00+\n\n[V4+ Styles]\nStyle: Default,Arial,20\n\n[Events]\nFormat: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text\n"
+	data := bytes.Join([][]byte{
+		el(idEBML, stringEl(0x4282, "matroska")),
+		el(idSegment,
+			el(idInfo, el(idTimestampScale, []byte{0x0F, 0x42, 0x40})),
+			el(idTracks,
+				el(idTrackEntry, uintEl(idTrackNumber, 1), uintEl(idTrackType, matroskaVideo)),
+				el(idTrackEntry, uintEl(idTrackNumber, 2), uintEl(idTrackType, matroskaSubtitle), stringEl(idCodecID, CodecText),
+					el(idContentEncodings, el(idContentEncoding, el(idCompression, uintEl(idCompAlgo, compressionZlib))))),
+				el(idTrackEntry, uintEl(idTrackNumber, 3), uintEl(idTrackType, matroskaSubtitle), stringEl(idCodecID, CodecASS),
+					stringEl(idCodecPrivate, assHeader)),
+			),
+			el(idCluster, el(idTimestamp, []byte{0x03, 0xE8}),
+				el(idSimpleBlock, block(1, 0, "video frame")),
+				el(idBlockGroup, el(idBlock, block(2, 0, zlibData(t, "<i>Hello</i>"))), el(idBlockDuration, []byte{0x07, 0xD0})),
+				el(idBlockGroup, el(idBlock, block(3, 500, "1,0,Default,,0,0,0,,Second")), el(idBlockDuration, []byte{0x03, 0xE8})),
+				el(idBlockGroup, el(idBlock, block(3, 0, "0,0,Default,,0,0,0,,First")), el(idBlockDuration, []byte{0x01, 0xF4})),
+			),
+			el(idCluster, el(idTimestamp, []byte{0x13, 0x88}),
+				el(idSimpleBlock, block(2, -100, zlibData(t, "World"))),
+			),
+		),
+	}, nil)
+	r := bytes.NewReader(data)
+
+	track, packets, err := Packets(r, int64(len(data)), 2)
+	assert.Nil(t, err)
+	assert.True(t, track.Extractable())
+	srt, f, err := Extract(track, packets)
+	assert.Nil(t, err)
+	assert.Equal(t, format.SRT, f)
+	assert.Equal(t, "1\n00:00:01,000 --> 00:00:03,000\n<i>Hello</i>\n\n2\n00:00:04,900 --> 00:00:06,900\nWorld\n\n", string(srt))
+
+	track, packets, err = Packets(r, int64(len(data)), 3)
+	assert.Nil(t, err)
+	ass, f, err := Extract(track, packets)
+	assert.Nil(t, err)
+	assert.Equal(t, format.ASS, f)
+	assert.Equal(t, assHeader+"Dialogue: 0,0:00:01.00,0:00:01.50,Default,,0,0,0,,First\nDialogue: 0,0:00:01.50,0:00:02.50,Default,,0,0,0,,Second\n", string(ass))
+
+	track, packets, err = Packets(r, int64(len(data)), 1)
+	assert.Nil(t, err)
+	assert.False(t, track.Extractable())
+	_, _, err = Extract(track, packets)
+	assert.NotNil(t, err)
+}
+
+func TestExtractShouldDemuxMP4Tx3gSamples(t *testing.T) {
+	sample := func(text string) []byte {
+		data := make([]byte, 2)
+		binary.BigEndian.PutUint16(data, uint16(len(text)))
+		return append(data, text...)
+	}
+	samples := [][]byte{sample("Hello"), sample(""), sample("World")}
+	mdat := bytes.Join(samples, nil)
+	mdatOffset := uint32(12 + 8) // After ftyp and the header of mdat
+
+	full := func(entries ...uint32) []byte {
+		data := make([]byte, 4+4*len(entries))
+		for i, e := range entries {
+			binary.BigEndian.PutUint32(data[4+4*i:], e)
+		}
+		return data
+	}
+	stbl := bx("stbl",
+		bx("stsd", append(make([]byte, 8), bx("tx3g", make([]byte, 12))...)),
+		bx("stts", full(3, 1, 2000, 1, 500, 1, 1500)),
+		bx("stsc", full(1, 1, 3, 1)),
+		bx("stsz", full(0, 3, uint32(len(samples[0])), uint32(len(samples[1])), uint32(len(samples[2])))),
+		bx("stco", full(1, mdatOffset)),
+	)
+	mdhd := make([]byte, 24)
+	binary.BigEndian.PutUint32(mdhd[12:], 1000)
+	binary.BigEndian.PutUint16(mdhd[20:], uint16('e'-0x60)<<10|uint16('n'-0x60)<<5|uint16('g'-0x60))
+	hdlr := make([]byte, 25)
+	copy(hdlr[8:], "sbtl")
+	tkhd := make([]byte, 84)
+	binary.BigEndian.PutUint32(tkhd[12:], 1)
+	data := bytes.Join([][]byte{
+		bx("ftyp", []byte("isom")),
+		bx("mdat", mdat),
+		bx("moov", bx("trak", bx("tkhd", tkhd), bx("mdia", bx("mdhd", mdhd), bx("hdlr", hdlr), bx("minf", stbl)))),
+	}, nil)
+
+	track, packets, err := Packets(bytes.NewReader(data), int64(len(data)), 1)
+	assert.Nil(t, err)
+	assert.Equal(t, "eng", track.Language)
+	srt, f, err := Extract(track, packets)
+	assert.Nil(t, err)
+	assert.Equal(t, format.SRT, f)
+	assert.Equal(t, "1\n00:00:00,000 --> 00:00:02,000\nHello\n\n2\n00:00:02,500 --> 00:00:04,000\nWorld\n\n", string(srt))
+}
+
+func TestMuxShouldInterleaveNewTracks(t *testing.T) {
+	info := el(idInfo, el(idTimestampScale, []byte{0x0F, 0x42, 0x40}))
+	tracks := el(idTracks, el(idTrackEntry, uintEl(idTrackNumber, 1), uintEl(idTrackType, matroskaVideo)))
+	first := el(idCluster, uintEl(idTimestamp, 0),
+		el(idSimpleBlock, block(1, 0, "frame 1")),
+		el(idSimpleBlock, block(1, 500, "frame 2")),
+		el(idSimpleBlock, block(1, 1000, "frame 3")),
+	)
+	second := el(idCluster, el(idTimestamp, []byte{0x9C, 0x40}), el(idSimpleBlock, block(1, 0, "frame 4")))
+	cuePoint := func(time []byte, position int) []byte {
+		return el(idCuePoint, el(idCueTime, time), el(idCueTrackPositions, uintEl(idCueTrack, 1), el(idCueClusterPosition, []byte{byte(position >> 8), byte(position)})))
+	}
+	position := len(el(idSeekHead)) + len(info) + len(tracks)
+	data := bytes.Join([][]byte{
+		el(idEBML, stringEl(0x4282, "webm")),
+		el(idSegment, el(idSeekHead), info, tracks, first, second,
+			el(idCues, cuePoint([]byte{0}, position), cuePoint([]byte{0x9C, 0x40}, position+len(first)))),
+	}, nil)
+
+	s, err := format.ParseSRT([]byte("1\n00:00:00,200 --> 00:00:01,000\nHello\n\n2\n00:00:00,900 --> 00:00:02,000\n<i>World</i>\n\n3\n00:00:35,000 --> 00:00:36,000\nFar\n\n4\n00:00:41,000 --> 00:00:42,000\nEnd\n"))
+	assert.Nil(t, err)
+	track, err := SubtitleTrack(s, format.SRT)
+	assert.Nil(t, err)
+	track.Language, track.LanguageBCP47, track.Forced = "fre", "fr", true
+
+	f, err := ioutil.TempFile("", "subify-mux")
+	assert.Nil(t, err)
+	defer os.Remove(f.Name())
+	defer f.Close()
+	assert.Nil(t, Mux(bytes.NewReader(data), int64(len(data)), f, []NewTrack{track}))
+	stat, err := f.Stat()
+	assert.Nil(t, err)
+	size := stat.Size()
+
+	muxed, err := Tracks(f, size)
+	assert.Nil(t, err)
+	if assert.Equal(t, 2, len(muxed)) {
+		assert.Equal(t, Track{Number: 2, Type: Subtitle, Codec: CodecText, Language: "fr", Forced: true}, muxed[1])
+	}
+	_, packets, err := Packets(f, size, 1)
+	assert.Nil(t, err)
+	if assert.Equal(t, 4, len(packets)) {
+		assert.Equal(t, "frame 3", string(packets[2].Data))
+		assert.Equal(t, 40*time.Second, packets[3].Start)
+	}
+	muxedTrack, packets, err := Packets(f, size, 2)
+	assert.Nil(t, err)
+	srt, _, err := Extract(muxedTrack, packets)
+	assert.Nil(t, err)
+	assert.Equal(t, "1\n00:00:00,200 --> 00:00:01,000\nHello\n\n2\n00:00:00,900 --> 00:00:02,000\n<i>World</i>\n\n3\n00:00:35,000 --> 00:00:36,000\nFar\n\n4\n00:00:41,000 --> 00:00:42,000\nEnd\n\n", string(srt))
+
+	// Blocks are ordered by time in the clusters, and the cues point to the moved clusters
+	segment, err := matroskaSegment(f, size)
+	assert.Nil(t, err)
+	children, err := segmentChildren(f, segment, size)
+	assert.Nil(t, err)
+	var ids []uint32
+	for _, c := range children {
+		ids = append(ids, c.ID)
+	}
+	assert.Equal(t, []uint32{idSeekHead, idVoid, idInfo, idTracks, idCluster, idCluster, idCluster, idCues}, ids)
+	blocks, err := clusterChildren(f, children[4], size)
+	assert.Nil(t, err)
+	var times []int64
+	for _, b := range blocks[1:] {
+		relative, err := blockTime(f, b.element)
+		assert.Nil(t, err)
+		times = append(times, relative)
+	}
+	assert.Equal(t, []int64{0, 200, 500, 900, 1000}, times)
+	for _, id := range []uint32{idTracks, idCues} {
+		e, err := findTopLevel(f, segment, size, id)
+		assert.Nil(t, err)
+		assert.Equal(t, id, e.ID)
+	}
+	cues, err := findTopLevel(f, segment, size, idCues)
+	assert.Nil(t, err)
+	var clusters []int64
+	_ = readChildren(f, cues.Offset, cues.Offset+cues.Size, func(point element) (bool, error) {
+		_ = readChildren(f, point.Offset, point.Offset+point.Size, func(e element) (bool, error) {
+			if e.ID == idCueTrackPositions {
+				_ = readChildren(f, e.Offset, e.Offset+e.Size, func(c element) (bool, error) {
+					if c.ID == idCueClusterPosition {
+						position, _ := readUint(f, c)
+						clusters = append(clusters, int64(position))
+					}
+					return true, nil
+				})
+			}
+			return true, nil
+		})
+		return true, nil
+	})
+	assert.Equal(t, []int64{children[4].start - segment.Offset, children[6].start - segment.Offset}, clusters)
+}
+
+func TestSubtitleTrackShouldKeepTheEventsOfASSSubtitles(t *testing.T) {
+	ass := "[Script Info]\nScriptType: v4.00+\n\n[V4+ Styles]\nFormat: Name, Fontname, Fontsize, Alignment\nStyle: Sign,Arial,48,8\n\n" +
+		"[Events]\nFormat: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text\n" +
+		"Comment: 0,0:00:00.00,0:00:05.00,Sign,,0,0,0,,Typeset by someone\n" +
+		"Dialogue: 5,0:00:01.00,0:00:03.00,Sign,Hero,0,0,0,,{\\an8\\pos(960,120)\\blur2}WELCOME{\\k20}, TOKYO\n" +
+		"Dialogue: 4,0:00:01.00,0:00:03.00,Sign,,0,0,0,,{\\p1}m 0 0 l 100 0 100 100{\\p0}\n\n[Fonts]\nfontname: sign.ttf\n"
+	s, err := format.ParseASS([]byte(ass))
+	assert.Nil(t, err)
+	track, err := SubtitleTrack(s, format.ASS)
+	assert.Nil(t, err)
+	assert.Equal(t, "[Script Info]\nScriptType: v4.00+\n\n[V4+ Styles]\nFormat: Name, Fontname, Fontsize, Alignment\nStyle: Sign,Arial,48,8\n\n"+
+		"[Events]\nFormat: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text\n\n[Fonts]\nfontname: sign.ttf\n", string(track.CodecPrivate))
+	if assert.Equal(t, 2, len(track.Packets)) {
+		assert.Equal(t, "0,5,Sign,Hero,0,0,0,,{\\an8\\pos(960,120)\\blur2}WELCOME{\\k20}, TOKYO", string(track.Packets[0].Data))
+		assert.Equal(t, "1,4,Sign,,0,0,0,,{\\p1}m 0 0 l 100 0 100 100{\\p0}", string(track.Packets[1].Data))
+		assert.Equal(t, time.Second, track.Packets[1].Start)
+	}
+}
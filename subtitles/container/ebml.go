@@ -0,0 +1,64 @@
+package container
+
+import (
+	"encoding/binary"
+)
+
+// encodeID encodes an element ID, which keeps its length marker
+func encodeID(id uint32) []byte {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, id)
+	for len(b) > 1 && b[0] == 0 {
+		b = b[1:]
+	}
+	return b
+}
+
+// encodeVint encodes a size or a track number in as few bytes as possible.
+// Values with all bits set are avoided, as they mean an unknown size
+func encodeVint(value uint64) []byte {
+	length := 1
+	for length < 8 && value >= 1<<(7*uint(length))-1 {
+		length++
+	}
+	return encodeVintLength(value, length)
+}
+
+// encodeVintLength encodes a size on the given number of bytes
+func encodeVintLength(value uint64, length int) []byte {
+	b := make([]byte, length)
+	for i := length - 1; i >= 0; i-- {
+		b[i] = byte(value)
+		value >>= 8
+	}
+	b[0] |= 0x80 >> uint(length-1)
+	return b
+}
+
+// encodeElement encodes an element with its data
+func encodeElement(id uint32, data ...[]byte) []byte {
+	size := 0
+	for _, d := range data {
+		size += len(d)
+	}
+	b := append(encodeID(id), encodeVint(uint64(size))...)
+	for _, d := range data {
+		b = append(b, d...)
+	}
+	return b
+}
+
+// encodeUint encodes an element holding an unsigned integer
+func encodeUint(id uint32, value uint64) []byte {
+	data := make([]byte, 8)
+	binary.BigEndian.PutUint64(data, value)
+	for len(data) > 1 && data[0] == 0 {
+		data = data[1:]
+	}
+	return encodeElement(id, data)
+}
+
+// encodeString encodes an element holding a string
+func encodeString(id uint32, value string) []byte {
+	return encodeElement(id, []byte(value))
+}
@@ -0,0 +1,149 @@
+package container
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"github.com/matcornic/subify/subtitles/format"
+)
+
+// Codecs of the text subtitles which can be extracted
+const (
+	CodecText   = "S_TEXT/UTF8"
+	CodecASS    = "S_TEXT/ASS"
+	CodecSSA    = "S_TEXT/SSA"
+	CodecWebVTT = "S_TEXT/WEBVTT"
+	CodecTx3g   = "tx3g"
+)
+
+// defaultPacketDuration is the display duration of the last cue, when the container does not give it
+const defaultPacketDuration = 2 * time.Second
+
+// Extractable tells if the subtitles of the track can be extracted as text
+func (t Track) Extractable() bool {
+	switch t.Codec {
+	case CodecText, CodecASS, CodecSSA, CodecWebVTT, CodecTx3g:
+		return true
+	}
+	return false
+}
+
+// Extract turns the packets of a text subtitle track into a subtitle file, in the format matching the codec
+func Extract(track Track, packets []Packet) ([]byte, format.Format, error) {
+	sort.SliceStable(packets, func(i, j int) bool { return packets[i].Start < packets[j].Start })
+	// Unknown durations end at the next cue
+	for i := range packets {
+		if packets[i].Duration > 0 {
+			continue
+		}
+		packets[i].Duration = defaultPacketDuration
+		if i+1 < len(packets) && packets[i+1].Start > packets[i].Start {
+			packets[i].Duration = packets[i+1].Start - packets[i].Start
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	switch track.Codec {
+	case CodecText:
+		writeSRT(buf, packets, func(data []byte) string { return string(data) })
+		return buf.Bytes(), format.SRT, nil
+	case CodecTx3g:
+		writeSRT(buf, packets, tx3gText)
+		return buf.Bytes(), format.SRT, nil
+	case CodecWebVTT:
+		header := strings.TrimSpace(string(track.CodecPrivate))
+		if header == "" {
+			header = "WEBVTT"
+		}
+		buf.WriteString(header + "\n")
+		for _, p := range packets {
+			fmt.Fprintf(buf, "\n%s --> %s\n%s\n", format.FormatTimestamp(p.Start, "."), format.FormatTimestamp(p.Start+p.Duration, "."), strings.TrimSpace(string(p.Data)))
+		}
+		return buf.Bytes(), format.VTT, nil
+	case CodecASS:
+		return extractASS(track, packets), format.ASS, nil
+	case CodecSSA:
+		return extractASS(track, packets), format.SSA, nil
+	}
+	return nil, "", fmt.Errorf("The track %v is not a text subtitle (codec %v), it can't be extracted", track.Number, track.Codec)
+}
+
+// writeSRT writes the packets as SRT cues, skipping the empty ones
+func writeSRT(buf *bytes.Buffer, packets []Packet, text func(data []byte) string) {
+	index := 0
+	for _, p := range packets {
+		t := strings.TrimSpace(strings.Replace(text(p.Data), "\r\n", "\n", -1))
+		if t == "" {
+			continue
+		}
+		index++
+		fmt.Fprintf(buf, "%d\n%s --> %s\n%s\n\n", index, format.FormatTimestamp(p.Start, ","), format.FormatTimestamp(p.Start+p.Duration, ","), t)
+	}
+}
+
+// tx3gText reads the text of a tx3g sample: its length, then the text in UTF-8 or UTF-16, then style boxes
+func tx3gText(data []byte) string {
+	if len(data) < 2 {
+		return ""
+	}
+	length := int(binary.BigEndian.Uint16(data))
+	if length > len(data)-2 {
+		length = len(data) - 2
+	}
+	text := data[2 : 2+length]
+	if len(text) >= 2 && text[0] == 0xFE && text[1] == 0xFF {
+		units := make([]uint16, (len(text)-2)/2)
+		for i := range units {
+			units[i] = binary.BigEndian.Uint16(text[2+2*i:])
+		}
+		return string(utf16.Decode(units))
+	}
+	return string(text)
+}
+
+// extractASS rebuilds an ASS or SSA file from the header of the track and its blocks.
+// Matroska blocks are "ReadOrder, Layer, Style, Name, MarginL, MarginR, MarginV, Effect, Text", without times
+func extractASS(track Track, packets []Packet) []byte {
+	type event struct {
+		order int
+		line  string
+	}
+	var events []event
+	for _, p := range packets {
+		fields := strings.SplitN(strings.TrimRight(string(p.Data), "\r\n"), ",", 9)
+		if len(fields) < 9 {
+			continue
+		}
+		order, _ := strconv.Atoi(strings.TrimSpace(fields[0]))
+		line := fmt.Sprintf("Dialogue: %s,%s,%s,%s", fields[1], assTimestamp(p.Start), assTimestamp(p.Start+p.Duration), strings.Join(fields[2:], ","))
+		events = append(events, event{order, line})
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].order < events[j].order })
+
+	buf := new(bytes.Buffer)
+	header := strings.TrimRight(string(track.CodecPrivate), "\r\n\x00")
+	buf.WriteString(header + "\n")
+	if !strings.Contains(header, "[Events]") {
+		first := "Layer"
+		if track.Codec == CodecSSA {
+			first = "Marked"
+		}
+		fmt.Fprintf(buf, "\n[Events]\nFormat: %s, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text\n", first)
+	}
+	for _, e := range events {
+		buf.WriteString(e.line + "\n")
+	}
+	return buf.Bytes()
+}
+
+// assTimestamp formats a duration as H:MM:SS.cc
+func assTimestamp(d time.Duration) string {
+	cs := d.Round(10*time.Millisecond) / (10 * time.Millisecond)
+	return fmt.Sprintf("%d:%02d:%02d.%02d", cs/360000, cs/6000%60, cs/100%60, cs%100)
+}
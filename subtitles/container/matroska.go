@@ -0,0 +1,316 @@
+package container
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Matroska element IDs, with their length marker
+const (
+	idEBML             = 0x1A45DFA3
+	idSegment          = 0x18538067
+	idSeekHead         = 0x114D9B74
+	idSeek             = 0x4DBB
+	idSeekID           = 0x53AB
+	idSeekPosition     = 0x53AC
+	idTracks           = 0x1654AE6B
+	idTrackEntry       = 0xAE
+	idTrackNumber      = 0xD7
+	idTrackType        = 0x83
+	idFlagDefault      = 0x88
+	idFlagForced       = 0x55AA
+	idFlagHI           = 0x55AB
+	idLanguage         = 0x22B59C
+	idLanguageBCP47    = 0x22B59D
+	idCodecID          = 0x86
+	idName             = 0x536E
+	idCodecPrivate     = 0x63A2
+	idContentEncodings = 0x6D80
+	idContentEncoding  = 0x6240
+	idContentScope     = 0x5032
+	idCompression      = 0x5034
+	idEncryption       = 0x5035
+	idCompAlgo         = 0x4254
+	idCompSettings     = 0x4255
+	idCluster          = 0x1F43B675
+	unknownElementSize = -1
+)
+
+// Matroska track types
+const (
+	matroskaVideo    = 1
+	matroskaAudio    = 2
+	matroskaSubtitle = 0x11
+)
+
+// element is the header of an EBML element
+type element struct {
+	ID     uint32
+	Offset int64 // Position of the data
+	Size   int64 // Size of the data, unknownElementSize when not written
+}
+
+// End gives the position after the data of the element, or the given limit when its size is unknown
+func (e element) End(limit int64) int64 {
+	if e.Size == unknownElementSize || e.Offset+e.Size > limit {
+		return limit
+	}
+	return e.Offset + e.Size
+}
+
+// readVint reads an EBML variable size integer. IDs keep their length marker, sizes don't
+func readVint(r io.ReaderAt, offset int64, keepMarker bool) (value uint64, length int, err error) {
+	buf := make([]byte, 8)
+	if _, err := r.ReadAt(buf[:1], offset); err != nil {
+		return 0, 0, err
+	}
+	length = 1
+	for mask := byte(0x80); length <= 8 && buf[0]&mask == 0; mask >>= 1 {
+		length++
+	}
+	if length > 8 {
+		return 0, 0, errors.New("Invalid EBML integer")
+	}
+	if length > 1 {
+		if _, err := r.ReadAt(buf[1:length], offset+1); err != nil {
+			return 0, 0, err
+		}
+	}
+	value = uint64(buf[0])
+	if !keepMarker {
+		value &= uint64(0xFF >> uint(length))
+	}
+	allOnes := value == uint64(0xFF>>uint(length))
+	for _, b := range buf[1:length] {
+		value = value<<8 | uint64(b)
+		allOnes = allOnes && b == 0xFF
+	}
+	if !keepMarker && allOnes {
+		return 0, length, errUnknownSize
+	}
+	return value, length, nil
+}
+
+// errUnknownSize is returned for sizes with all bits set, used for elements written while streaming
+var errUnknownSize = errors.New("Unknown EBML size")
+
+// readElement reads the header of the element at the given position
+func readElement(r io.ReaderAt, offset int64) (element, error) {
+	id, idLength, err := readVint(r, offset, true)
+	if err != nil {
+		return element{}, err
+	}
+	size, sizeLength, err := readVint(r, offset+int64(idLength), false)
+	e := element{ID: uint32(id), Offset: offset + int64(idLength+sizeLength), Size: int64(size)}
+	if err == errUnknownSize {
+		e.Size = unknownElementSize
+	} else if err != nil {
+		return element{}, err
+	}
+	return e, nil
+}
+
+// readChildren reads the elements between two positions, until fn returns false
+func readChildren(r io.ReaderAt, from, to int64, fn func(e element) (bool, error)) error {
+	for offset := from; offset < to; {
+		e, err := readElement(r, offset)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		next, err := fn(e)
+		if err != nil || !next {
+			return err
+		}
+		if e.Size == unknownElementSize {
+			return nil
+		}
+		offset = e.Offset + e.Size
+	}
+	return nil
+}
+
+// readData reads the data of an element
+func readData(r io.ReaderAt, e element) ([]byte, error) {
+	if e.Size == unknownElementSize || e.Size > 1<<24 {
+		return nil, fmt.Errorf("EBML element %x is too large", e.ID)
+	}
+	data := make([]byte, e.Size)
+	if _, err := r.ReadAt(data, e.Offset); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// readUint reads an element holding an unsigned integer
+func readUint(r io.ReaderAt, e element) (uint64, error) {
+	data, err := readData(r, e)
+	if err != nil || len(data) > 8 {
+		return 0, fmt.Errorf("Invalid EBML integer in element %x", e.ID)
+	}
+	var value uint64
+	for _, b := range data {
+		value = value<<8 | uint64(b)
+	}
+	return value, nil
+}
+
+// readString reads an element holding a string, without its trailing zeros
+func readString(r io.ReaderAt, e element) (string, error) {
+	data, err := readData(r, e)
+	for len(data) > 0 && data[len(data)-1] == 0 {
+		data = data[:len(data)-1]
+	}
+	return string(data), err
+}
+
+// matroskaSegment finds the segment of a Matroska file
+func matroskaSegment(r io.ReaderAt, size int64) (element, error) {
+	header, err := readElement(r, 0)
+	if err != nil || header.ID != idEBML {
+		return element{}, ErrUnknownContainer
+	}
+	segment, err := readElement(r, header.End(size))
+	if err != nil || segment.ID != idSegment {
+		return element{}, errors.New("No segment found in the Matroska file")
+	}
+	return segment, nil
+}
+
+// findTopLevel finds the first top level element of a segment with the given ID.
+// The seek head is used when clusters come first
+func findTopLevel(r io.ReaderAt, segment element, size int64, id uint32) (element, error) {
+	var found *element
+	var seeks []int64
+	err := readChildren(r, segment.Offset, segment.End(size), func(e element) (bool, error) {
+		switch e.ID {
+		case id:
+			found = &e
+			return false, nil
+		case idSeekHead:
+			seeks = append(seeks, seekPositions(r, e, id)...)
+		case idCluster:
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		return element{}, err
+	}
+	if found != nil {
+		return *found, nil
+	}
+	for _, position := range seeks {
+		if e, err := readElement(r, segment.Offset+position); err == nil && e.ID == id {
+			return e, nil
+		}
+	}
+	return element{}, fmt.Errorf("No element %x found in the Matroska file", id)
+}
+
+// seekPositions gives the positions, relative to the segment, of the elements with the ID in a seek head
+func seekPositions(r io.ReaderAt, seekHead element, id uint32) (positions []int64) {
+	_ = readChildren(r, seekHead.Offset, seekHead.Offset+seekHead.Size, func(seek element) (bool, error) {
+		if seek.ID != idSeek {
+			return true, nil
+		}
+		var seekID []byte
+		var position uint64
+		_ = readChildren(r, seek.Offset, seek.Offset+seek.Size, func(e element) (bool, error) {
+			switch e.ID {
+			case idSeekID:
+				seekID, _ = readData(r, e)
+			case idSeekPosition:
+				position, _ = readUint(r, e)
+			}
+			return true, nil
+		})
+		if len(seekID) == 4 && binary.BigEndian.Uint32(seekID) == id {
+			positions = append(positions, int64(position))
+		}
+		return true, nil
+	})
+	return positions
+}
+
+// matroskaTracks reads the Tracks element of a Matroska file
+func matroskaTracks(r io.ReaderAt, size int64) ([]Track, error) {
+	segment, err := matroskaSegment(r, size)
+	if err != nil {
+		return nil, err
+	}
+	tracks, err := findTopLevel(r, segment, size, idTracks)
+	if err != nil {
+		return nil, err
+	}
+	var result []Track
+	err = readChildren(r, tracks.Offset, tracks.End(size), func(e element) (bool, error) {
+		if e.ID != idTrackEntry {
+			return true, nil
+		}
+		t, err := matroskaTrack(r, e)
+		result = append(result, t)
+		return true, err
+	})
+	return result, err
+}
+
+// matroskaTrack reads a TrackEntry element
+func matroskaTrack(r io.ReaderAt, entry element) (Track, error) {
+	// Default values of the Matroska specification
+	t := Track{Type: Other, Language: "eng", Default: true}
+	var bcp47 string
+	err := readChildren(r, entry.Offset, entry.Offset+entry.Size, func(e element) (bool, error) {
+		var err error
+		var value uint64
+		switch e.ID {
+		case idTrackNumber:
+			value, err = readUint(r, e)
+			t.Number = int(value)
+		case idTrackType:
+			value, err = readUint(r, e)
+			switch value {
+			case matroskaVideo:
+				t.Type = Video
+			case matroskaAudio:
+				t.Type = Audio
+			case matroskaSubtitle:
+				t.Type = Subtitle
+			}
+		case idFlagDefault:
+			value, err = readUint(r, e)
+			t.Default = value == 1
+		case idFlagForced:
+			value, err = readUint(r, e)
+			t.Forced = value == 1
+		case idFlagHI:
+			value, err = readUint(r, e)
+			t.HearingImpaired = value == 1
+		case idLanguage:
+			t.Language, err = readString(r, e)
+		case idLanguageBCP47:
+			bcp47, err = readString(r, e)
+		case idCodecID:
+			t.Codec, err = readString(r, e)
+		case idName:
+			t.Name, err = readString(r, e)
+		case idCodecPrivate:
+			t.CodecPrivate, err = readData(r, e)
+		case idContentEncodings:
+			t.compression, err = matroskaCompression(r, e)
+		}
+		return true, err
+	})
+	// The BCP 47 language overrides the old one when both are given
+	if bcp47 != "" {
+		t.Language = bcp47
+	}
+	if err == nil && t.compression != nil && t.compression.private {
+		t.CodecPrivate, err = t.compression.decompress(t.CodecPrivate)
+	}
+	return t, err
+}
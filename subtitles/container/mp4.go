@@ -0,0 +1,185 @@
+package container
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+)
+
+// box is the header of a MP4 box
+type box struct {
+	Type   string
+	Offset int64 // Position of the data
+	Size   int64 // Size of the data
+}
+
+// mp4TopLevel are the box types found at the start of MP4 files
+var mp4TopLevel = map[string]bool{"ftyp": true, "moov": true, "mdat": true, "free": true, "skip": true, "wide": true, "pnot": true}
+
+// Flags of tx3g sample entries
+const (
+	tx3gAllSamplesForced = 0x40000000
+)
+
+// isMP4Box tells if the type of the first box is the one of a MP4 file
+func isMP4Box(boxType string) bool {
+	return mp4TopLevel[boxType]
+}
+
+// readBoxes reads the boxes between two positions, until fn returns false
+func readBoxes(r io.ReaderAt, from, to int64, fn func(b box) (bool, error)) error {
+	header := make([]byte, 16)
+	for offset := from; offset+8 <= to; {
+		if _, err := r.ReadAt(header[:8], offset); err != nil {
+			return err
+		}
+		b := box{Type: string(header[4:8]), Offset: offset + 8, Size: int64(binary.BigEndian.Uint32(header[:4])) - 8}
+		switch b.Size + 8 {
+		case 1:
+			// 64 bits size
+			if _, err := r.ReadAt(header[8:16], offset+8); err != nil {
+				return err
+			}
+			b.Offset, b.Size = offset+16, int64(binary.BigEndian.Uint64(header[8:16]))-16
+		case 0:
+			// Up to the end
+			b.Size = to - b.Offset
+		}
+		if b.Size < 0 || b.Offset+b.Size > to {
+			return errors.New("Invalid MP4 box " + b.Type)
+		}
+		next, err := fn(b)
+		if err != nil || !next {
+			return err
+		}
+		offset = b.Offset + b.Size
+	}
+	return nil
+}
+
+// readBox reads the data of a box
+func readBox(r io.ReaderAt, b box) ([]byte, error) {
+	if b.Size > 1<<24 {
+		return nil, errors.New("MP4 box " + b.Type + " is too large")
+	}
+	data := make([]byte, b.Size)
+	_, err := r.ReadAt(data, b.Offset)
+	return data, err
+}
+
+// findBox finds the first child box of the given type, following the path of types
+func findBox(r io.ReaderAt, parent box, path ...string) (box, bool) {
+	var found box
+	ok := false
+	_ = readBoxes(r, parent.Offset, parent.Offset+parent.Size, func(b box) (bool, error) {
+		if b.Type == path[0] {
+			found, ok = b, true
+			return false, nil
+		}
+		return true, nil
+	})
+	if ok && len(path) > 1 {
+		return findBox(r, found, path[1:]...)
+	}
+	return found, ok
+}
+
+// mp4Tracks reads the tracks of the movie box of a MP4 file
+func mp4Tracks(r io.ReaderAt, size int64) ([]Track, error) {
+	moov, ok := findBox(r, box{Offset: 0, Size: size}, "moov")
+	if !ok {
+		return nil, errors.New("No movie box found in the MP4 file")
+	}
+	var tracks []Track
+	err := readBoxes(r, moov.Offset, moov.Offset+moov.Size, func(b box) (bool, error) {
+		if b.Type != "trak" {
+			return true, nil
+		}
+		t, err := mp4Track(r, b)
+		tracks = append(tracks, t)
+		return true, err
+	})
+	return tracks, err
+}
+
+// mp4Track reads a track box
+func mp4Track(r io.ReaderAt, trak box) (Track, error) {
+	t := Track{Type: Other, Language: "und"}
+
+	if tkhd, ok := findBox(r, trak, "tkhd"); ok {
+		data, err := readBox(r, tkhd)
+		if err != nil || len(data) < 24 {
+			return t, errors.New("Invalid MP4 track header")
+		}
+		// Version 1 has 64 bits dates
+		idOffset := 12
+		if data[0] == 1 {
+			idOffset = 20
+		}
+		t.Number = int(binary.BigEndian.Uint32(data[idOffset:]))
+		t.Default = data[3]&0x1 != 0
+	}
+
+	if mdhd, ok := findBox(r, trak, "mdia", "mdhd"); ok {
+		data, err := readBox(r, mdhd)
+		timescaleOffset, languageOffset := 12, 20
+		if err == nil && len(data) > 0 && data[0] == 1 {
+			timescaleOffset, languageOffset = 20, 32
+		}
+		if err == nil && len(data) >= languageOffset+2 {
+			t.timescale = binary.BigEndian.Uint32(data[timescaleOffset:])
+			t.Language = mp4Language(binary.BigEndian.Uint16(data[languageOffset:]))
+		}
+	}
+	// Extended language, as BCP 47
+	if elng, ok := findBox(r, trak, "mdia", "elng"); ok {
+		if data, err := readBox(r, elng); err == nil && len(data) > 4 {
+			if language := strings.TrimRight(string(data[4:]), "\x00"); language != "" {
+				t.Language = language
+			}
+		}
+	}
+
+	if hdlr, ok := findBox(r, trak, "mdia", "hdlr"); ok {
+		if data, err := readBox(r, hdlr); err == nil && len(data) >= 12 {
+			switch string(data[8:12]) {
+			case "vide":
+				t.Type = Video
+			case "soun":
+				t.Type = Audio
+			case "sbtl", "text", "subt", "clcp":
+				t.Type = Subtitle
+			}
+		}
+	}
+
+	if stsd, ok := findBox(r, trak, "mdia", "minf", "stbl", "stsd"); ok && stsd.Size > 8 {
+		// Full box header and entry count come before the sample entries
+		entries := box{Offset: stsd.Offset + 8, Size: stsd.Size - 8}
+		_ = readBoxes(r, entries.Offset, entries.Offset+entries.Size, func(entry box) (bool, error) {
+			t.Codec = entry.Type
+			if entry.Type == "tx3g" {
+				if data, err := readBox(r, entry); err == nil && len(data) >= 12 {
+					t.Forced = binary.BigEndian.Uint32(data[8:12])&tx3gAllSamplesForced != 0
+				}
+			}
+			return false, nil
+		})
+	}
+
+	if name, ok := findBox(r, trak, "udta", "name"); ok {
+		if data, err := readBox(r, name); err == nil {
+			t.Name = strings.TrimRight(string(data), "\x00")
+		}
+	}
+	return t, nil
+}
+
+// mp4Language decodes the packed ISO 639-2/T code of a media header
+func mp4Language(packed uint16) string {
+	if packed == 0 || packed == 0x7FFF {
+		return "und"
+	}
+	return string([]byte{byte(packed>>10&0x1F) + 0x60, byte(packed>>5&0x1F) + 0x60, byte(packed&0x1F) + 0x60})
+}
@@ -0,0 +1,570 @@
+package container
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/matcornic/subify/subtitles/format"
+)
+
+// Matroska element IDs written when muxing
+const (
+	idEBMLVersion        = 0x4286
+	idEBMLReadVersion    = 0x42F7
+	idEBMLMaxIDLength    = 0x42F2
+	idEBMLMaxSizeLength  = 0x42F3
+	idDocType            = 0x4282
+	idDocTypeVersion     = 0x4287
+	idDocTypeReadVersion = 0x4285
+	idVoid               = 0xEC
+	idCRC32              = 0xBF
+	idTrackUID           = 0x73C5
+	idFlagLacing         = 0x9C
+	idPosition           = 0xA7
+	idPrevSize           = 0xAB
+	idCuePoint           = 0xBB
+	idCueTime            = 0xB3
+	idCueTrackPositions  = 0xB7
+	idCueTrack           = 0xF7
+	idCueClusterPosition = 0xF1
+	idCueDuration        = 0xB2
+
+	// seekHeadSpace is reserved at the start of the segment for the seek head, written last
+	seekHeadSpace = 256
+	// maxBlockOffset is the largest distance, in timestamp units, between a block and its cluster
+	maxBlockOffset = math.MaxInt16
+)
+
+// NewTrack is a text subtitle track to add to a Matroska video
+type NewTrack struct {
+	Codec           string // CodecText, CodecASS or CodecSSA
+	CodecPrivate    []byte // Header of ASS and SSA subtitles
+	Language        string // ISO 639-2/B code, "und" when empty
+	LanguageBCP47   string // BCP 47 tag (pt-BR, zh-Hant), if any
+	Name            string
+	Default         bool
+	Forced          bool
+	HearingImpaired bool
+	Packets         []Packet
+}
+
+// SubtitleTrack turns a subtitle into a track to mux, without language nor flags.
+// ASS and SSA subtitles keep their header and the lines of their events, other formats become S_TEXT/UTF8 tracks with inline tags
+func SubtitleTrack(s *format.Subtitle, f format.Format) (NewTrack, error) {
+	if f != format.ASS && f != format.SSA {
+		t := NewTrack{Codec: CodecText}
+		for _, c := range s.Cues {
+			text := strings.Join(s.StyledLines(c), "\n")
+			if align := s.Alignment(c); align != format.AlignBottomCenter {
+				text = fmt.Sprintf("{\\an%d}", align) + text
+			}
+			if strings.TrimSpace(text) != "" {
+				t.Packets = append(t.Packets, subtitlePacket(c, text))
+			}
+		}
+		return t, nil
+	}
+
+	t := NewTrack{Codec: CodecASS}
+	if f == format.SSA {
+		t.Codec = CodecSSA
+	}
+	// The events keep the lines they were read from, and become "ReadOrder, Layer, Style, Name, MarginL, MarginR, MarginV, Effect, Text"
+	header, events := format.ASSEvents(s, f == format.SSA)
+	for i, e := range events {
+		layer, ok := e["layer"]
+		if !ok {
+			layer = e["marked"]
+		}
+		fields := []string{strconv.Itoa(i), layer, e["style"], e["name"], e["marginl"], e["marginr"], e["marginv"], e["effect"], e["text"]}
+		t.Packets = append(t.Packets, subtitlePacket(s.Cues[i], strings.Join(fields, ",")))
+	}
+	t.CodecPrivate = []byte(strings.TrimSpace(header) + "\n")
+	return t, nil
+}
+
+// subtitlePacket makes the packet of a cue
+func subtitlePacket(c *format.Cue, text string) Packet {
+	p := Packet{Start: c.Start, Duration: c.Duration(), Data: []byte(text)}
+	if p.Start < 0 {
+		p.Duration, p.Start = p.Duration+p.Start, 0
+	}
+	if p.Duration < 0 {
+		p.Duration = 0
+	}
+	return p
+}
+
+// span is a top level element of a segment or a child of a cluster, from the start of its header to its end
+type span struct {
+	element
+	start, end int64
+}
+
+// newBlock is a block of an added track, with times in timestamp units of the segment
+type newBlock struct {
+	track    int
+	time     uint64
+	duration uint64
+	data     []byte
+}
+
+// encode encodes the block in a block group, for a cluster with the given timestamp
+func (b newBlock) encode(clusterTime uint64) []byte {
+	relative := int16(int64(b.time) - int64(clusterTime))
+	block := append(encodeVint(uint64(b.track)), byte(uint16(relative)>>8), byte(relative), 0)
+	group := [][]byte{encodeElement(idBlock, block, b.data)}
+	if b.duration > 0 {
+		group = append(group, encodeUint(idBlockDuration, b.duration))
+	}
+	return encodeElement(idBlockGroup, group...)
+}
+
+// muxWriter writes a Matroska file, keeping the position and the first error
+type muxWriter struct {
+	w   io.WriteSeeker
+	pos int64
+	err error
+}
+
+func (m *muxWriter) write(data []byte) {
+	if m.err != nil {
+		return
+	}
+	n, err := m.w.Write(data)
+	m.pos += int64(n)
+	m.err = err
+}
+
+// copy copies the bytes of the original file between two positions
+func (m *muxWriter) copy(r io.ReaderAt, from, to int64) {
+	if m.err != nil {
+		return
+	}
+	n, err := io.Copy(m.w, io.NewSectionReader(r, from, to-from))
+	m.pos += n
+	m.err = err
+}
+
+func (m *muxWriter) seek(pos int64) {
+	if m.err != nil {
+		return
+	}
+	m.pos, m.err = m.w.Seek(pos, io.SeekStart)
+}
+
+// Mux copies the Matroska video of the given size to w, with new subtitle tracks.
+// The elements and blocks of the video are copied untouched, the blocks of the new tracks are interleaved
+// in the clusters by time. The seek head and the cues are rebuilt for the new positions
+func Mux(r io.ReaderAt, size int64, w io.WriteSeeker, tracks []NewTrack) error {
+	segment, err := matroskaSegment(r, size)
+	if err != nil {
+		return err
+	}
+	existing, err := matroskaTracks(r, size)
+	if err != nil {
+		return err
+	}
+	children, err := segmentChildren(r, segment, size)
+	if err != nil {
+		return err
+	}
+	scale := timestampScale(r, segment, size)
+
+	// New tracks are numbered after the existing ones
+	number := 0
+	for _, t := range existing {
+		if t.Number > number {
+			number = t.Number
+		}
+	}
+	var entries [][]byte
+	var blocks []newBlock
+	for _, t := range tracks {
+		number++
+		entry, err := trackEntry(number, t)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, entry)
+		for _, p := range t.Packets {
+			blocks = append(blocks, newBlock{number, uint64(p.Start) / scale, uint64(p.Duration) / scale, p.Data})
+		}
+	}
+	sort.SliceStable(blocks, func(i, j int) bool { return blocks[i].time < blocks[j].time })
+
+	var clusterTimes []uint64
+	for _, c := range children {
+		if c.ID == idCluster {
+			t, err := clusterTimestamp(r, c)
+			if err != nil {
+				return err
+			}
+			clusterTimes = append(clusterTimes, t)
+		}
+	}
+
+	m := &muxWriter{w: w}
+	m.write(ebmlHeader())
+	m.write(encodeID(idSegment))
+	sizePosition := m.pos
+	m.write(encodeVintLength(0, 8))
+	start := m.pos
+	m.write(void(seekHeadSpace))
+
+	positions := map[uint32]int64{} // New positions of the top level elements, for the seek head
+	clusters := map[int64]int64{}   // Old to new positions of the clusters, for the cues
+	var cues []span
+	next, index := 0, 0
+	for _, c := range children {
+		switch c.ID {
+		case idSeekHead, idVoid, idCRC32:
+			continue
+		case idCues:
+			cues = append(cues, c)
+			continue
+		case idCluster:
+			clusterTime, limit := clusterTimes[index], uint64(math.MaxUint64)
+			if index+1 < len(clusterTimes) {
+				limit = clusterTimes[index+1]
+			}
+			index++
+			// Blocks before the cluster, or too far from the previous one, get their own clusters
+			next = writeBlockClusters(m, blocks, next, clusterTime)
+			clusters[c.start-segment.Offset] = m.pos - start
+			first := next
+			for next < len(blocks) && blocks[next].time < limit && blocks[next].time-clusterTime <= maxBlockOffset {
+				next++
+			}
+			if err := writeCluster(m, r, c, clusterTime, blocks[first:next]); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, ok := positions[c.ID]; !ok {
+			positions[c.ID] = m.pos - start
+		}
+		if c.ID == idTracks {
+			if err := writeTracks(m, r, c, entries); err != nil {
+				return err
+			}
+			continue
+		}
+		m.copy(r, c.start, c.end)
+	}
+	writeBlockClusters(m, blocks, next, math.MaxUint64)
+
+	for _, c := range cues {
+		data, err := rebuildCues(r, c, clusters)
+		if err != nil {
+			return err
+		}
+		if _, ok := positions[idCues]; !ok && len(data) > 0 {
+			positions[idCues] = m.pos - start
+		}
+		m.write(data)
+	}
+
+	// Size of the segment and seek head, once everything is written
+	end := m.pos
+	var seeks [][]byte
+	for _, id := range []uint32{idInfo, idTracks, idChapters, idAttachments, idTags, idCues} {
+		if position, ok := positions[id]; ok {
+			seeks = append(seeks, encodeElement(idSeek, encodeElement(idSeekID, encodeID(id)), encodeUint(idSeekPosition, uint64(position))))
+		}
+	}
+	seekHead := encodeElement(idSeekHead, seeks...)
+	m.seek(sizePosition)
+	m.write(encodeVintLength(uint64(end-start), 8))
+	m.seek(start)
+	m.write(seekHead)
+	m.write(void(seekHeadSpace - len(seekHead)))
+	m.seek(end)
+	return m.err
+}
+
+// ebmlHeader is the header of the written files, which need Matroska v4 for BCP 47 languages
+func ebmlHeader() []byte {
+	return encodeElement(idEBML,
+		encodeUint(idEBMLVersion, 1),
+		encodeUint(idEBMLReadVersion, 1),
+		encodeUint(idEBMLMaxIDLength, 4),
+		encodeUint(idEBMLMaxSizeLength, 8),
+		encodeString(idDocType, "matroska"),
+		encodeUint(idDocTypeVersion, 4),
+		encodeUint(idDocTypeReadVersion, 2))
+}
+
+// void makes a Void element taking the given number of bytes, at least 9
+func void(length int) []byte {
+	return append(append([]byte{idVoid}, encodeVintLength(uint64(length-9), 8)...), make([]byte, length-9)...)
+}
+
+// trackEntry makes the TrackEntry element of a new track
+func trackEntry(number int, t NewTrack) ([]byte, error) {
+	uid := make([]byte, 8)
+	if _, err := rand.Read(uid); err != nil {
+		return nil, err
+	}
+	language := t.Language
+	if language == "" {
+		language = "und"
+	}
+	flag := func(b bool) uint64 {
+		if b {
+			return 1
+		}
+		return 0
+	}
+	data := [][]byte{
+		encodeUint(idTrackNumber, uint64(number)),
+		encodeUint(idTrackUID, binary.BigEndian.Uint64(uid)|1),
+		encodeUint(idTrackType, matroskaSubtitle),
+		encodeUint(idFlagDefault, flag(t.Default)),
+		encodeUint(idFlagForced, flag(t.Forced)),
+		encodeUint(idFlagLacing, 0),
+		encodeString(idLanguage, language),
+		encodeString(idCodecID, t.Codec),
+	}
+	if t.HearingImpaired {
+		data = append(data, encodeUint(idFlagHI, 1))
+	}
+	if t.LanguageBCP47 != "" {
+		data = append(data, encodeString(idLanguageBCP47, t.LanguageBCP47))
+	}
+	if t.Name != "" {
+		data = append(data, encodeString(idName, t.Name))
+	}
+	if len(t.CodecPrivate) > 0 {
+		data = append(data, encodeElement(idCodecPrivate, t.CodecPrivate))
+	}
+	return encodeElement(idTrackEntry, data...), nil
+}
+
+// segmentChildren lists the top level elements of a segment. The end of clusters of unknown size is found
+// from their children
+func segmentChildren(r io.ReaderAt, segment element, size int64) ([]span, error) {
+	var children []span
+	end := segment.End(size)
+	for offset := segment.Offset; offset < end; {
+		e, err := readElement(r, offset)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		c := span{e, offset, e.End(end)}
+		if e.Size == unknownElementSize {
+			if e.ID != idCluster {
+				return nil, fmt.Errorf("Matroska element %x of unknown size is not supported", e.ID)
+			}
+			blocks, err := clusterChildren(r, c, end)
+			if err != nil {
+				return nil, err
+			}
+			c.end = c.Offset
+			if len(blocks) > 0 {
+				c.end = blocks[len(blocks)-1].end
+			}
+		}
+		children = append(children, c)
+		offset = c.end
+	}
+	return children, nil
+}
+
+// clusterChildren lists the children of a cluster, until a top level element when its size is unknown
+func clusterChildren(r io.ReaderAt, cluster span, limit int64) ([]span, error) {
+	var children []span
+	for offset := cluster.Offset; offset < cluster.End(limit); {
+		e, err := readElement(r, offset)
+		if err == io.EOF || (err == nil && cluster.Size == unknownElementSize && isTopLevel(e.ID)) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if e.Size == unknownElementSize {
+			return nil, errors.New("Invalid Matroska cluster")
+		}
+		children = append(children, span{e, offset, e.Offset + e.Size})
+		offset = e.Offset + e.Size
+	}
+	return children, nil
+}
+
+// clusterTimestamp reads the timestamp of a cluster
+func clusterTimestamp(r io.ReaderAt, cluster span) (uint64, error) {
+	var timestamp uint64
+	err := readChildren(r, cluster.Offset, cluster.end, func(e element) (bool, error) {
+		if e.ID != idTimestamp {
+			return true, nil
+		}
+		var err error
+		timestamp, err = readUint(r, e)
+		return false, err
+	})
+	return timestamp, err
+}
+
+// blockTime reads the timestamp of a SimpleBlock or BlockGroup, relative to its cluster
+func blockTime(r io.ReaderAt, e element) (int64, error) {
+	if e.ID == idBlockGroup {
+		found := false
+		err := readChildren(r, e.Offset, e.Offset+e.Size, func(child element) (bool, error) {
+			if child.ID == idBlock {
+				e, found = child, true
+			}
+			return !found, nil
+		})
+		if err != nil || !found {
+			return 0, errors.New("Invalid Matroska block group")
+		}
+	}
+	_, length, err := readVint(r, e.Offset, false)
+	if err != nil {
+		return 0, err
+	}
+	timestamp := make([]byte, 2)
+	if _, err := r.ReadAt(timestamp, e.Offset+int64(length)); err != nil {
+		return 0, err
+	}
+	return int64(int16(binary.BigEndian.Uint16(timestamp))), nil
+}
+
+// writeBlockClusters writes the blocks from the given index and before a time in clusters of their own.
+// It gives the index of the first block not written
+func writeBlockClusters(m *muxWriter, blocks []newBlock, from int, before uint64) int {
+	for from < len(blocks) && blocks[from].time < before {
+		clusterTime := blocks[from].time
+		data := [][]byte{encodeUint(idTimestamp, clusterTime)}
+		for from < len(blocks) && blocks[from].time < before && blocks[from].time-clusterTime <= maxBlockOffset {
+			data = append(data, blocks[from].encode(clusterTime))
+			from++
+		}
+		m.write(encodeElement(idCluster, data...))
+	}
+	return from
+}
+
+// writeCluster copies a cluster with new blocks, placed before the first original block starting after them.
+// Checksums and positions of the cluster are dropped, as they change
+func writeCluster(m *muxWriter, r io.ReaderAt, cluster span, clusterTime uint64, blocks []newBlock) error {
+	children, err := clusterChildren(r, cluster, cluster.end)
+	if err != nil {
+		return err
+	}
+	var kept []span
+	var size int64
+	for _, c := range children {
+		switch c.ID {
+		case idCRC32, idVoid, idPosition, idPrevSize:
+			continue
+		}
+		kept = append(kept, c)
+		size += c.end - c.start
+	}
+	encoded := make([][]byte, len(blocks))
+	for i, b := range blocks {
+		encoded[i] = b.encode(clusterTime)
+		size += int64(len(encoded[i]))
+	}
+
+	m.write(encodeID(idCluster))
+	m.write(encodeVint(uint64(size)))
+	i := 0
+	for _, c := range kept {
+		if c.ID == idSimpleBlock || c.ID == idBlockGroup {
+			relative, err := blockTime(r, c.element)
+			if err != nil {
+				return err
+			}
+			for ; i < len(blocks) && int64(blocks[i].time) < int64(clusterTime)+relative; i++ {
+				m.write(encoded[i])
+			}
+		}
+		m.copy(r, c.start, c.end)
+	}
+	for ; i < len(blocks); i++ {
+		m.write(encoded[i])
+	}
+	return m.err
+}
+
+// writeTracks writes the Tracks element with the original entries followed by the new ones
+func writeTracks(m *muxWriter, r io.ReaderAt, tracks span, entries [][]byte) error {
+	var data [][]byte
+	err := readChildren(r, tracks.Offset, tracks.end, func(e element) (bool, error) {
+		if e.ID == idCRC32 || e.ID == idVoid {
+			return true, nil
+		}
+		raw, err := readData(r, e)
+		data = append(data, encodeElement(e.ID, raw))
+		return true, err
+	})
+	if err != nil {
+		return err
+	}
+	m.write(encodeElement(idTracks, append(data, entries...)...))
+	return m.err
+}
+
+// rebuildCues rewrites the cues with the new positions of the clusters. Positions inside clusters are dropped,
+// as new blocks move them. It gives nothing when no cue is left
+func rebuildCues(r io.ReaderAt, cues span, clusters map[int64]int64) ([]byte, error) {
+	var points [][]byte
+	err := readChildren(r, cues.Offset, cues.end, func(point element) (bool, error) {
+		if point.ID != idCuePoint {
+			return true, nil
+		}
+		var cueTime []byte
+		var positions [][]byte
+		err := readChildren(r, point.Offset, point.Offset+point.Size, func(e element) (bool, error) {
+			switch e.ID {
+			case idCueTime:
+				value, err := readUint(r, e)
+				cueTime = encodeUint(idCueTime, value)
+				return true, err
+			case idCueTrackPositions:
+				var track, position, duration uint64
+				err := readChildren(r, e.Offset, e.Offset+e.Size, func(child element) (bool, error) {
+					var err error
+					switch child.ID {
+					case idCueTrack:
+						track, err = readUint(r, child)
+					case idCueClusterPosition:
+						position, err = readUint(r, child)
+					case idCueDuration:
+						duration, err = readUint(r, child)
+					}
+					return true, err
+				})
+				if moved, ok := clusters[int64(position)]; ok && err == nil {
+					data := [][]byte{encodeUint(idCueTrack, track), encodeUint(idCueClusterPosition, uint64(moved))}
+					if duration > 0 {
+						data = append(data, encodeUint(idCueDuration, duration))
+					}
+					positions = append(positions, encodeElement(idCueTrackPositions, data...))
+				}
+				return true, err
+			}
+			return true, nil
+		})
+		if err == nil && cueTime != nil && len(positions) > 0 {
+			points = append(points, encodeElement(idCuePoint, append([][]byte{cueTime}, positions...)...))
+		}
+		return true, err
+	})
+	if err != nil || len(points) == 0 {
+		return nil, err
+	}
+	return encodeElement(idCues, points...), nil
+}
@@ -0,0 +1,142 @@
+package container
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// mp4Table reads a table box of the sample table: a full box header, an entry count and the entries
+func mp4Table(r io.ReaderAt, stbl box, boxType string, entrySize int) ([]byte, int, error) {
+	b, ok := findBox(r, stbl, boxType)
+	if !ok {
+		return nil, 0, nil
+	}
+	data, err := readBox(r, b)
+	if err != nil || len(data) < 8 {
+		return nil, 0, fmt.Errorf("Invalid MP4 box %v", boxType)
+	}
+	count := int(binary.BigEndian.Uint32(data[4:8]))
+	if count < 0 || len(data) < 8+count*entrySize {
+		return nil, 0, fmt.Errorf("Invalid MP4 box %v", boxType)
+	}
+	return data[8:], count, nil
+}
+
+// mp4Packets reads the samples of a track of a MP4 file, with the sample table
+func mp4Packets(r io.ReaderAt, size int64, track Track) ([]Packet, error) {
+	moov, ok := findBox(r, box{Offset: 0, Size: size}, "moov")
+	if !ok {
+		return nil, errors.New("No movie box found in the MP4 file")
+	}
+	var stbl box
+	found := false
+	_ = readBoxes(r, moov.Offset, moov.Offset+moov.Size, func(trak box) (bool, error) {
+		if trak.Type != "trak" {
+			return true, nil
+		}
+		if t, err := mp4Track(r, trak); err == nil && t.Number == track.Number {
+			stbl, found = findBox(r, trak, "mdia", "minf", "stbl")
+			return false, nil
+		}
+		return true, nil
+	})
+	if !found {
+		return nil, fmt.Errorf("No sample table found for the track %v", track.Number)
+	}
+
+	// Durations of the samples
+	stts, sttsCount, err := mp4Table(r, stbl, "stts", 8)
+	if err != nil {
+		return nil, err
+	}
+	var durations []uint32
+	for i := 0; i < sttsCount; i++ {
+		count, delta := binary.BigEndian.Uint32(stts[i*8:]), binary.BigEndian.Uint32(stts[i*8+4:])
+		for j := uint32(0); j < count && len(durations) < 1<<22; j++ {
+			durations = append(durations, delta)
+		}
+	}
+
+	// Sizes of the samples
+	stszBox, ok := findBox(r, stbl, "stsz")
+	if !ok {
+		return nil, errors.New("No sample sizes found in the MP4 file")
+	}
+	stsz, err := readBox(r, stszBox)
+	if err != nil || len(stsz) < 12 {
+		return nil, errors.New("Invalid MP4 box stsz")
+	}
+	sampleSize, sampleCount := binary.BigEndian.Uint32(stsz[4:8]), int(binary.BigEndian.Uint32(stsz[8:12]))
+	if sampleSize == 0 && len(stsz) < 12+4*sampleCount {
+		return nil, errors.New("Invalid MP4 box stsz")
+	}
+	sizeOf := func(i int) int64 {
+		if sampleSize != 0 {
+			return int64(sampleSize)
+		}
+		return int64(binary.BigEndian.Uint32(stsz[12+4*i:]))
+	}
+
+	// Positions of the chunks
+	var chunks []int64
+	if stco, count, err := mp4Table(r, stbl, "stco", 4); err != nil {
+		return nil, err
+	} else if stco != nil {
+		for i := 0; i < count; i++ {
+			chunks = append(chunks, int64(binary.BigEndian.Uint32(stco[i*4:])))
+		}
+	} else if co64, count, err := mp4Table(r, stbl, "co64", 8); err != nil {
+		return nil, err
+	} else {
+		for i := 0; i < count; i++ {
+			chunks = append(chunks, int64(binary.BigEndian.Uint64(co64[i*8:])))
+		}
+	}
+
+	// Samples in the chunks
+	stsc, stscCount, err := mp4Table(r, stbl, "stsc", 12)
+	if err != nil {
+		return nil, err
+	}
+	var packets []Packet
+	var elapsed uint64
+	sample := 0
+	for entry := 0; entry < stscCount; entry++ {
+		firstChunk := int(binary.BigEndian.Uint32(stsc[entry*12:])) - 1
+		perChunk := int(binary.BigEndian.Uint32(stsc[entry*12+4:]))
+		lastChunk := len(chunks)
+		if entry+1 < stscCount {
+			lastChunk = int(binary.BigEndian.Uint32(stsc[(entry+1)*12:])) - 1
+		}
+		for chunk := firstChunk; chunk < lastChunk && chunk >= 0 && chunk < len(chunks); chunk++ {
+			offset := chunks[chunk]
+			for i := 0; i < perChunk && sample < sampleCount; i++ {
+				data := make([]byte, sizeOf(sample))
+				if _, err := r.ReadAt(data, offset); err != nil {
+					return nil, fmt.Errorf("Can't read the sample %v of the track %v because of : %v", sample+1, track.Number, err)
+				}
+				var duration uint32
+				if sample < len(durations) {
+					duration = durations[sample]
+				}
+				packets = append(packets, Packet{
+					Start:    mp4Time(elapsed, track.timescale),
+					Duration: mp4Time(uint64(duration), track.timescale),
+					Data:     data,
+				})
+				elapsed += uint64(duration)
+				offset += int64(len(data))
+				sample++
+			}
+		}
+	}
+	return packets, nil
+}
+
+// mp4Time converts a time in the units of the track
+func mp4Time(t uint64, timescale uint32) time.Duration {
+	return time.Duration(float64(t) / float64(timescale) * float64(time.Second))
+}
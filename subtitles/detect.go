@@ -0,0 +1,109 @@
+package subtitles
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/matcornic/subify/subtitles/charset"
+	"github.com/matcornic/subify/subtitles/format"
+	"github.com/matcornic/subify/subtitles/langid"
+	"github.com/matcornic/subify/subtitles/zhconv"
+)
+
+// subtitleText reads the text of the cues of a subtitle, whatever its encoding
+func subtitleText(subtitlePath string) (string, error) {
+	data, err := ioutil.ReadFile(subtitlePath)
+	if err != nil {
+		return "", fmt.Errorf("Can't read the file %v because of : %v", subtitlePath, err)
+	}
+	// The language is unknown, or not trusted
+	data, _, err = charset.ToUTF8(data, "")
+	if err != nil {
+		return "", err
+	}
+	f, err := format.Detect(subtitlePath, data)
+	if err != nil {
+		return "", err
+	}
+	s, err := format.Parse(data, f, format.Options{})
+	if err != nil {
+		return "", err
+	}
+	var text strings.Builder
+	for _, c := range s.Cues {
+		text.WriteString(c.PlainText() + "\n")
+	}
+	return text.String(), nil
+}
+
+// checkLanguage rejects the subtitles whose text is in another language than the asked one.
+// Subtitles which can't be read, or which are too short, are accepted
+func checkLanguage(subtitlePath string, language Language) error {
+	text, err := subtitleText(subtitlePath)
+	if err != nil {
+		return nil
+	}
+	ok, found := langid.Check(text, language.Tag)
+	if ok {
+		return nil
+	}
+	name := found.Language
+	if l := Languages.GetLanguage(found.Language); l != nil {
+		name = l.Description
+	}
+	return fmt.Errorf("The subtitle is in %v, not in %v", name, language.Description)
+}
+
+// DetectLanguage identifies the language of a subtitle from its text
+func DetectLanguage(subtitlePath string) (*Language, error) {
+	text, err := subtitleText(subtitlePath)
+	if err != nil {
+		return nil, err
+	}
+	results := langid.Detect(text)
+	if len(results) == 0 {
+		return nil, errors.New("The subtitle is too short to identify its language")
+	}
+	l := Languages.GetLanguage(results[0].Language)
+	if l == nil {
+		return nil, fmt.Errorf("Language %v is not known by Subify", results[0].Language)
+	}
+	// Chinese is told apart by its script
+	if l.Tag == "zh" {
+		if c := chineseLanguage(text); c != nil {
+			return c, nil
+		}
+	}
+	return l, nil
+}
+
+// NamedAs tells if a subtitle detected in a language is rightly named with another one: the same language,
+// or one too close to be told apart (Serbian for Croatian). Chinese scripts are told apart
+func NamedAs(detected, named Language) bool {
+	if a, ok := zhconv.ScriptOf(detected.Tag); ok {
+		if b, ok := zhconv.ScriptOf(named.Tag); ok && a != b {
+			return false
+		}
+	}
+	return langid.Same(detected.Tag, named.Tag)
+}
+
+// CloseLanguages gives the languages a detected language stands for, itself first, as they can't be told apart
+// (Croatian, Bosnian, Montenegrin and Serbian for Croatian). It is empty for the languages told apart from the others
+func CloseLanguages(detected Language) Langs {
+	close := Langs{}
+	for _, code := range langid.Members(detected.Tag) {
+		if l := Languages.GetLanguage(code); l != nil {
+			close = append(close, *l)
+		}
+	}
+	return close
+}
+
+// TaggedPath gives the path of a subtitle with the language in its name (Movie.srt gives Movie.en.srt)
+func TaggedPath(subtitlePath string, language Language) string {
+	return subtitlePathFor(subtitlePath, language.Tag, filepath.Ext(subtitlePath))
+}
@@ -0,0 +1,51 @@
+package subtitles
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const portugueseSubtitle = `1
+00:00:01,000 --> 00:00:03,000
+Não sei do que você está falando.
+
+2
+00:00:04,000 --> 00:00:06,000
+Ela deixou a cidade na semana passada e ninguém a viu desde então.
+
+3
+00:00:07,000 --> 00:00:09,000
+Devíamos chamar a polícia antes que seja tarde demais.
+`
+
+func TestCheckLanguageShouldRejectMislabeledSubtitles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "subify")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	subtitlePath := filepath.Join(dir, "Movie.en.srt")
+	assert.Nil(t, ioutil.WriteFile(subtitlePath, []byte(portugueseSubtitle), 0644))
+
+	assert.EqualError(t, checkLanguage(subtitlePath, *Languages.GetLanguage("en")), "The subtitle is in Portuguese, not in English")
+	assert.Nil(t, checkLanguage(subtitlePath, *Languages.GetLanguage("pb")))
+	l, err := DetectLanguage(subtitlePath)
+	assert.Nil(t, err)
+	assert.Equal(t, "por", l.ID)
+	assert.Equal(t, "/videos/Movie.pt.srt", TaggedPath("/videos/Movie.srt", *l))
+}
+
+func TestNamedAsShouldAcceptCloseLanguages(t *testing.T) {
+	hr, sr, fr := *Languages.GetLanguage("hr"), *Languages.GetLanguage("sr"), *Languages.GetLanguage("fr")
+	assert.True(t, NamedAs(hr, sr))
+	assert.False(t, NamedAs(hr, fr))
+	assert.False(t, NamedAs(*Languages.GetLanguage("zh-Hans"), *Languages.GetLanguage("zh-Hant")))
+	assert.True(t, NamedAs(*Languages.GetLanguage("zh-Hans"), *Languages.GetLanguage("chi")))
+
+	assert.Equal(t, []string{"Croatian", "Bosnian", "Montenegrin", "Serbian"}, CloseLanguages(hr).GetDescriptions())
+	assert.Equal(t, []string{"Indonesian", "Malay"}, CloseLanguages(*Languages.GetLanguage("id")).GetDescriptions())
+	assert.Empty(t, CloseLanguages(sr))
+	assert.Empty(t, CloseLanguages(fr))
+}
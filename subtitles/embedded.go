@@ -0,0 +1,56 @@
+package subtitles
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/matcornic/subify/subtitles/container"
+	"github.com/olekukonko/tablewriter"
+)
+
+// TrackLanguage gives the language of an embedded track from its ISO 639-2 code or BCP 47 tag, nil when unknown
+func TrackLanguage(code string) *Language {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if code == "" || code == "und" {
+		return nil
+	}
+	return Languages.GetLanguageByCode(code)
+}
+
+// embeddedTrack finds a full or a forced subtitle track in the language, nil if there is none
+func embeddedTrack(tracks []container.Track, language Language, forced bool) *container.Track {
+	for i, t := range tracks {
+		if t.Type != container.Subtitle || t.Forced != forced {
+			continue
+		}
+		if l := TrackLanguage(t.Language); l != nil && language.Covers(*l) {
+			return &tracks[i]
+		}
+	}
+	return nil
+}
+
+// PrintTracks prints the tracks of a video as a nice table
+func PrintTracks(tracks []container.Track) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Track", "Type", "Codec", "Language", "Name", "Default", "Forced", "Hearing impaired"})
+	for _, t := range tracks {
+		language := t.Language
+		if l := TrackLanguage(t.Language); l != nil {
+			language += " (" + l.Description + ")"
+		}
+		table.Append([]string{
+			strconv.Itoa(t.Number),
+			string(t.Type),
+			t.Codec,
+			language,
+			t.Name,
+			yesNo(t.Default),
+			yesNo(t.Forced),
+			yesNo(t.HearingImpaired),
+		})
+	}
+	table.SetAutoWrapText(false)
+	table.Render()
+}
@@ -0,0 +1,65 @@
+package subtitles
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/matcornic/subify/subtitles/container"
+	"github.com/matcornic/subify/subtitles/format"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrackLanguageShouldReadContainerCodes(t *testing.T) {
+	assert.Equal(t, "fre", TrackLanguage("fra").ID)
+	assert.Equal(t, "fre", TrackLanguage("fre").ID)
+	assert.Equal(t, "pt-BR", TrackLanguage("pt-BR").ID)
+	assert.Equal(t, "por", TrackLanguage("pt-PT").ID)
+	assert.Equal(t, "gre", TrackLanguage("ell").ID)
+	assert.Equal(t, "eng", TrackLanguage("en").ID)
+	assert.Nil(t, TrackLanguage("und"))
+}
+
+func TestEmbeddedTrackShouldIgnoreForcedTracks(t *testing.T) {
+	tracks := []container.Track{
+		{Number: 1, Type: container.Audio, Language: "eng"},
+		{Number: 2, Type: container.Subtitle, Language: "eng", Forced: true},
+		{Number: 3, Type: container.Subtitle, Language: "fra"},
+	}
+	assert.Nil(t, embeddedTrack(tracks, *Languages.GetLanguage("en"), false))
+	assert.Equal(t, 3, embeddedTrack(tracks, *Languages.GetLanguage("fr"), false).Number)
+	assert.Equal(t, 2, embeddedTrack(tracks, *Languages.GetLanguage("en"), true).Number)
+}
+
+func TestNewMuxSubtitleShouldReadFlagsFromName(t *testing.T) {
+	s := NewMuxSubtitle("/videos/Movie.en.forced.srt")
+	assert.Equal(t, "eng", s.Language.ID)
+	assert.True(t, s.Forced)
+	assert.False(t, s.HearingImpaired)
+	s = NewMuxSubtitle("Movie.pb.sdh.ass")
+	assert.True(t, s.HearingImpaired)
+	code, tag := matroskaLanguage(s.Language)
+	assert.Equal(t, "por", code)
+	assert.Equal(t, "pt-BR", tag)
+	code, tag = matroskaLanguage(NewMuxSubtitle("Movie.srt").Language)
+	assert.Equal(t, "und", code)
+	assert.Equal(t, "", tag)
+	assert.Equal(t, "/videos/Movie.muxed.mkv", MuxedPath("/videos/Movie.mkv"))
+}
+
+func TestExtractedPathShouldNotTakeExistingNames(t *testing.T) {
+	dir, err := ioutil.TempDir("", "subify")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	video := filepath.Join(dir, "Movie.mkv")
+	english := container.Track{Number: 3, Type: container.Subtitle, Language: "eng"}
+
+	assert.Equal(t, filepath.Join(dir, "Movie.en.srt"), ExtractedPath(video, english, format.SRT))
+	assert.Equal(t, filepath.Join(dir, "Movie.track5.srt"), ExtractedPath(video, container.Track{Number: 5}, format.SRT))
+	sdh := container.Track{Number: 4, Type: container.Subtitle, Language: "eng", Forced: true, HearingImpaired: true}
+	assert.Equal(t, filepath.Join(dir, "Movie.en.forced.sdh.srt"), ExtractedPath(video, sdh, format.SRT))
+
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "Movie.en.srt"), []byte("Existing"), 0644))
+	assert.Equal(t, filepath.Join(dir, "Movie.en.track3.srt"), ExtractedPath(video, english, format.SRT))
+}
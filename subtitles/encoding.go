@@ -0,0 +1,69 @@
+package subtitles
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/matcornic/subify/subtitles/charset"
+)
+
+// vobSubHeader starts the binary .sub files of VobSub subtitles, which have no text encoding
+var vobSubHeader = []byte{0x00, 0x00, 0x01, 0xBA}
+
+// NormalizeEncoding transcodes a subtitle file to UTF-8, with a BOM if asked.
+// The language, if known, guides the detection of the original encoding.
+func NormalizeEncoding(subtitlePath string, language *Language, bom bool) (charset.Result, error) {
+	data, err := ioutil.ReadFile(subtitlePath)
+	if err != nil {
+		return charset.Result{}, fmt.Errorf("Can't read the file %v because of : %v", subtitlePath, err)
+	}
+	if bytes.HasPrefix(data, vobSubHeader) {
+		return charset.Result{}, errors.New("VobSub subtitles are images, they have no text encoding")
+	}
+
+	var lang string
+	if language != nil {
+		lang = language.Tag
+	}
+	normalized, detected, err := charset.ToUTF8(data, lang)
+	if err != nil {
+		return detected, err
+	}
+	if bom {
+		normalized = charset.WithBOM(normalized)
+	}
+	if bytes.Equal(data, normalized) {
+		return detected, nil
+	}
+	if err := ioutil.WriteFile(subtitlePath, normalized, 0644); err != nil {
+		return detected, fmt.Errorf("Can't save the file %v because of : %v", subtitlePath, err)
+	}
+	return detected, nil
+}
+
+// nameFlagRegexp matches the flags written after the language in the names of subtitles (Movie.en.forced.sdh.srt)
+var nameFlagRegexp = regexp.MustCompile(`^(?i:forced|sdh|cc|track\d+)$`)
+
+// LanguageFromPath gives the language written in the name of a subtitle (Movie.fr.srt), nil if there is none.
+// The language is the last part before the flags, written as a lower case code (fr, fre, pt-BR), not to take
+// words of the title for languages (Her, Man)
+func LanguageFromPath(subtitlePath string) *Language {
+	name := strings.TrimSuffix(filepath.Base(subtitlePath), filepath.Ext(subtitlePath))
+	parts := strings.Split(name, ".")[1:]
+	for i := len(parts) - 1; i >= 0; i-- {
+		if nameFlagRegexp.MatchString(parts[i]) {
+			continue
+		}
+		primary := strings.SplitN(strings.Replace(parts[i], "_", "-", -1), "-", 2)[0]
+		if len(primary) < 2 || len(primary) > 3 || primary != strings.ToLower(primary) {
+			return nil
+		}
+		return Languages.GetLanguageByCode(parts[i])
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package subtitles
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/matcornic/subify/subtitles/container"
+	"github.com/matcornic/subify/subtitles/format"
+)
+
+// Extract saves an embedded text subtitle track as a subtitle file, next to the video unless an output is given.
+// It gives the path of the subtitle
+func Extract(videoPath string, track container.Track, output string) (string, error) {
+	if !track.Extractable() {
+		return "", fmt.Errorf("The track %v is not a text subtitle (codec %v), it can't be extracted", track.Number, track.Codec)
+	}
+	track, packets, err := container.ReadPackets(videoPath, track.Number)
+	if err != nil {
+		return "", err
+	}
+	data, f, err := container.Extract(track, packets)
+	if err != nil {
+		return "", err
+	}
+	if output == "" {
+		output = ExtractedPath(videoPath, track, f)
+	}
+	// Existing subtitles are never overwritten
+	file, err := os.OpenFile(output, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if os.IsExist(err) {
+		return "", fmt.Errorf("Can't save the file %v because it already exists", output)
+	}
+	if err != nil {
+		return "", fmt.Errorf("Can't save the file %v because of : %v", output, err)
+	}
+	if _, err = file.Write(data); err == nil {
+		err = file.Close()
+	} else {
+		file.Close()
+	}
+	if err != nil {
+		return "", fmt.Errorf("Can't save the file %v because of : %v", output, err)
+	}
+	return output, nil
+}
+
+// ExtractedPath gives the path of an extracted track, next to the video with its language and flags in the name
+// (Movie.en.srt, Movie.en.forced.srt, Movie.en.sdh.srt). Tracks without language are named by their number
+// (Movie.track3.srt), and so are the tracks whose name is already taken (Movie.en.track4.srt)
+func ExtractedPath(videoPath string, track container.Track, f format.Format) string {
+	number := "track" + strconv.Itoa(track.Number)
+	tag := number
+	if l := TrackLanguage(track.Language); l != nil {
+		tag = l.Tag
+	}
+	if track.Forced {
+		tag += ".forced"
+	}
+	if track.HearingImpaired {
+		tag += ".sdh"
+	}
+	path := subtitlePathFor(videoPath, tag, f.Extension())
+	if _, err := os.Stat(path); err == nil && tag != number {
+		path = subtitlePathFor(videoPath, tag+"."+number, f.Extension())
+	}
+	return path
+}
+
+// FindTracks selects the subtitle tracks of a language, full subtitles first
+func FindTracks(tracks []container.Track, language Language) []container.Track {
+	var full, forced []container.Track
+	for _, t := range container.SubtitleTracks(tracks) {
+		if l := TrackLanguage(t.Language); l == nil || l.ID != language.ID {
+			continue
+		}
+		if t.Forced {
+			forced = append(forced, t)
+		} else {
+			full = append(full, t)
+		}
+	}
+	return append(full, forced...)
+}
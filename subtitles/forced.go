@@ -0,0 +1,65 @@
+package subtitles
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/matcornic/subify/subtitles/format"
+)
+
+const (
+	// forcedDensity is the number of cues per minute under which a subtitle only translates the foreign parts.
+	// Full subtitles have around 10 cues per minute
+	forcedDensity = 3.0
+	// forcedMinSpan is the shortest subtitle whose density tells if it is forced. Shorter ones are clips or samples
+	forcedMinSpan = 10 * time.Minute
+)
+
+// forcedNameRegexp matches the names of the forced subtitles (Movie.en.forced.srt, Movie.Foreign.Parts.srt)
+var forcedNameRegexp = regexp.MustCompile(`(?i)(^|[^a-z])(forced|foreign[ ._-]?parts?)($|[^a-z])`)
+
+// isForcedName tells if the name of a subtitle flags it as forced
+func isForcedName(name string) bool {
+	return forcedNameRegexp.MatchString(name)
+}
+
+// subtitleTag gives the tag of a subtitle in its file name: the language, followed by .forced for forced subtitles
+func subtitleTag(lang string, forced bool) string {
+	if forced {
+		return lang + ".forced"
+	}
+	return lang
+}
+
+// cueDensity gives the number of cues per minute of a subtitle, from its start to the end of its last cue,
+// and this span. The span is 0 when there is no cue
+func cueDensity(s *format.Subtitle) (float64, time.Duration) {
+	var span time.Duration
+	for _, c := range s.Cues {
+		if c.End > span {
+			span = c.End
+		}
+	}
+	if span <= 0 {
+		return 0, 0
+	}
+	return float64(len(s.Cues)) / span.Minutes(), span
+}
+
+// checkForced rejects the full subtitles downloaded as forced ones, from the density of their cues.
+// Subtitles which can't be read, or which are too short, are accepted
+func checkForced(subtitlePath string) error {
+	s, _, err := format.ReadFile(subtitlePath, format.Options{})
+	if err != nil {
+		return nil
+	}
+	density, span := cueDensity(s)
+	if span < forcedMinSpan {
+		return nil
+	}
+	if density >= forcedDensity {
+		return fmt.Errorf("The subtitle has %.1f cues per minute, it is a full subtitle and not a forced one", density)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package subtitles
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matcornic/subify/subtitles/format"
+	"github.com/stretchr/testify/assert"
+)
+
+// srtEvery writes a SRT subtitle of a 20 minutes video, with a cue every interval
+func srtEvery(interval time.Duration) string {
+	var b strings.Builder
+	for i, start := 1, interval; start < 20*time.Minute; i, start = i+1, start+interval {
+		fmt.Fprintf(&b, "%d\n%s --> %s\nLine %d\n\n", i, format.FormatTimestamp(start, ","), format.FormatTimestamp(start+2*time.Second, ","), i)
+	}
+	return b.String()
+}
+
+func TestCheckForcedShouldUseCueDensity(t *testing.T) {
+	dir, err := ioutil.TempDir("", "subify")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	full := filepath.Join(dir, "Movie.en.srt")
+	forced := filepath.Join(dir, "Movie.en.forced.srt")
+	assert.Nil(t, ioutil.WriteFile(full, []byte(srtEvery(6*time.Second)), 0644))
+	assert.Nil(t, ioutil.WriteFile(forced, []byte(srtEvery(time.Minute)), 0644))
+
+	assert.EqualError(t, checkForced(full), "The subtitle has 10.0 cues per minute, it is a full subtitle and not a forced one")
+	assert.Nil(t, checkForced(forced))
+
+	// Too short to tell
+	short := filepath.Join(dir, "Clip.en.srt")
+	assert.Nil(t, ioutil.WriteFile(short, []byte("1\n00:00:01,000 --> 00:00:02,000\nHello\n"), 0644))
+	assert.Nil(t, checkForced(short))
+}
+
+func TestIsForcedNameShouldReadFlags(t *testing.T) {
+	assert.True(t, isForcedName("Movie.2019.en.forced.srt"))
+	assert.True(t, isForcedName("Movie 2019 Foreign Parts Only.srt"))
+	assert.True(t, isForcedName("FORCED"))
+	assert.False(t, isForcedName("Movie.2019.Enforcer.srt"))
+	assert.False(t, isForcedName("Foreign.Correspondent.1940.srt"))
+	assert.Equal(t, "en.forced", subtitleTag("en", true))
+	assert.Equal(t, "en", subtitleTag("en", false))
+}
@@ -0,0 +1,759 @@
+package format
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	assOverrideRegexp    = regexp.MustCompile(`\{([^}]*)\}`)
+	assOverrideTagRegexp = regexp.MustCompile(`^(an|a|[ibus]|1?c)(&H[0-9A-Fa-f]+&?|\d+)?$`)
+	assDrawingTagRegexp  = regexp.MustCompile(`^p(\d+)$`)
+	// assEscapeRegexp matches the line breaks and the hard spaces of the text
+	assEscapeRegexp = regexp.MustCompile(`\\[Nnh]`)
+)
+
+var assEscapes = strings.NewReplacer(`\N`, "\n", `\n`, "\n", `\h`, " ")
+
+// assDefaultStyle is used when the subtitle has no style
+var assDefaultStyle = Style{Name: "Default", FontName: "Arial", FontSize: 20, Color: "#ffffff", Alignment: AlignBottomCenter}
+
+// Fields of the styles and the events written to ASS (V4+) and SSA (V4) subtitles
+var (
+	assStyleFormat = []string{"Name", "Fontname", "Fontsize", "PrimaryColour", "SecondaryColour", "OutlineColour", "BackColour", "Bold", "Italic", "Underline", "StrikeOut", "ScaleX", "ScaleY", "Spacing", "Angle", "BorderStyle", "Outline", "Shadow", "Alignment", "MarginL", "MarginR", "MarginV", "Encoding"}
+	ssaStyleFormat = []string{"Name", "Fontname", "Fontsize", "PrimaryColour", "SecondaryColour", "TertiaryColour", "BackColour", "Bold", "Italic", "BorderStyle", "Outline", "Shadow", "Alignment", "MarginL", "MarginR", "MarginV", "AlphaLevel", "Encoding"}
+	assEventFormat = []string{"Layer", "Start", "End", "Style", "Name", "MarginL", "MarginR", "MarginV", "Effect", "Text"}
+	ssaEventFormat = []string{"Marked", "Start", "End", "Style", "Name", "MarginL", "MarginR", "MarginV", "Effect", "Text"}
+)
+
+// assStyleDefaults are the values of the style fields without equivalent in Style, when the style has none
+var assStyleDefaults = map[string]string{
+	"SecondaryColour": "&H000000FF", "OutlineColour": "&H00000000", "BackColour": "&H00000000", "StrikeOut": "0",
+	"ScaleX": "100", "ScaleY": "100", "Spacing": "0", "Angle": "0", "BorderStyle": "1", "Outline": "2", "Shadow": "1",
+	"MarginL": "10", "MarginR": "10", "MarginV": "10", "Encoding": "1",
+}
+
+// ssaStyleDefaults are the defaults of SSA styles, when they differ from assStyleDefaults
+var ssaStyleDefaults = map[string]string{"SecondaryColour": "&H00FFFF", "TertiaryColour": "&H000000", "BackColour": "&H000000", "AlphaLevel": "0"}
+
+// assStyleModel are the style fields read into Style, the others are kept in Style.Fields
+var assStyleModel = map[string]bool{"Name": true, "Fontname": true, "Fontsize": true, "PrimaryColour": true, "Bold": true, "Italic": true, "Underline": true, "Alignment": true}
+
+// assLayout keeps the lines of an ASS/SSA file which are neither styles nor events (script info, comments,
+// fonts and other sections), and where the styles and the events go among them
+type assLayout struct {
+	legacy      bool
+	newline     string
+	lines       []string
+	info        []string // Keys of the script info, in their order
+	styles      int      // Index of the lines where the styles go, -1 without styles section
+	events      int      // Index of the lines where the events go
+	styleFormat []string
+	eventFormat []string
+	comments    []assComment
+}
+
+// assComment is a Comment event, which is not displayed. It is written after the cue it followed
+type assComment struct {
+	cue   *Cue
+	after *Cue
+}
+
+// assStyle is the Style line a style was read from
+type assStyle struct {
+	line   string
+	parsed Style
+}
+
+// assEvent is the event line a cue was read from
+type assEvent struct {
+	line   string
+	format []string
+	text   string // Text field of the line
+	parsed Cue
+}
+
+// ParseASS parses the content of an Advanced SubStation Alpha (ASS) or SubStation Alpha (SSA) subtitle.
+// Styles are kept, and the bold, italic, underline, color and alignment overrides of the text are converted to inline tags.
+// The other overrides, the fields of the events and the styles, the comments and the other sections are kept
+// to write the subtitle back as it was. Drawings are left out of the text
+func ParseASS(data []byte) (*Subtitle, error) {
+	sub := &Subtitle{Metadata: map[string]string{}}
+	layout := &assLayout{newline: "\n", styles: -1, events: -1}
+	if bytes.Contains(data, []byte("\r\n")) {
+		layout.newline = "\r\n"
+	}
+	section := ""
+	var last *Cue // Last dialogue, the comments following it are written after it
+
+	for i, raw := range splitLines(data) {
+		line := strings.TrimSpace(raw)
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.ToLower(line)
+			if section == "[v4 styles]" {
+				layout.legacy = true
+			}
+			layout.lines = append(layout.lines, raw)
+			continue
+		}
+		var key, value string
+		if kv := strings.SplitN(line, ":", 2); len(kv) == 2 && !strings.HasPrefix(line, ";") {
+			key, value = strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+		}
+		styles := strings.HasSuffix(section, "styles]")
+
+		switch {
+		case section == "[script info]" && key != "":
+			sub.Metadata[key] = value
+			layout.info = append(layout.info, key)
+		case styles && key == "Format":
+			layout.styleFormat = splitFields(value, -1)
+			layout.styles = len(layout.lines) + 1
+		case styles && key == "Style":
+			style := parseASSStyle(layout.styleFormat, splitFields(value, len(layout.styleFormat)), layout.legacy)
+			style.ass = &assStyle{line: raw, parsed: style.copy()}
+			if len(sub.Styles) == 0 {
+				layout.styles = len(layout.lines)
+			}
+			sub.Styles = append(sub.Styles, style)
+			continue
+		case section == "[events]" && key == "Format":
+			layout.eventFormat = splitFields(value, -1)
+			layout.events = len(layout.lines) + 1
+		case section == "[events]" && (key == "Dialogue" || key == "Comment"):
+			cue, err := parseASSEvent(layout.eventFormat, splitFields(value, len(layout.eventFormat)))
+			if err != nil {
+				return nil, err
+			}
+			cue.ass.line, cue.pos = raw, i+1
+			if len(sub.Cues) == 0 && len(layout.comments) == 0 {
+				layout.events = len(layout.lines)
+			}
+			if key == "Comment" {
+				layout.comments = append(layout.comments, assComment{cue: cue, after: last})
+				continue
+			}
+			cue.Index = len(sub.Cues) + 1
+			sub.Cues = append(sub.Cues, cue)
+			last = cue
+			continue
+		}
+		layout.lines = append(layout.lines, raw)
+	}
+	if layout.eventFormat == nil {
+		return nil, errors.New("No [Events] section found")
+	}
+	sub.ass = layout
+	return sub, nil
+}
+
+// splitFields splits comma separated values. The last field (the text) may contain commas
+func splitFields(value string, n int) []string {
+	fields := strings.SplitN(value, ",", n)
+	for i := range fields {
+		if i < len(fields)-1 || n < 0 {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+	}
+	return fields
+}
+
+// field gives the value of a named field, from the Format line
+func field(format, values []string, name string) string {
+	for i, f := range format {
+		if strings.EqualFold(f, name) && i < len(values) {
+			return values[i]
+		}
+	}
+	return ""
+}
+
+// parseASSStyle reads a Style line
+func parseASSStyle(format, values []string, legacy bool) *Style {
+	style := &Style{
+		Name:      field(format, values, "Name"),
+		FontName:  field(format, values, "Fontname"),
+		Color:     parseASSColor(field(format, values, "PrimaryColour")),
+		Bold:      field(format, values, "Bold") == "-1" || field(format, values, "Bold") == "1",
+		Italic:    field(format, values, "Italic") == "-1" || field(format, values, "Italic") == "1",
+		Underline: field(format, values, "Underline") == "-1" || field(format, values, "Underline") == "1",
+		Fields:    map[string]string{},
+	}
+	style.FontSize, _ = strconv.ParseFloat(field(format, values, "Fontsize"), 64)
+	style.Alignment, _ = strconv.Atoi(field(format, values, "Alignment"))
+	if legacy {
+		style.Alignment = fromSSAAlignment(style.Alignment)
+	}
+	for i, f := range format {
+		if !assStyleModel[f] && i < len(values) {
+			style.Fields[f] = values[i]
+		}
+	}
+	return style
+}
+
+// copy copies the style, with its own fields
+func (s *Style) copy() Style {
+	c := *s
+	c.Fields = map[string]string{}
+	for k, v := range s.Fields {
+		c.Fields[k] = v
+	}
+	c.ass = nil
+	return c
+}
+
+// parseASSEvent reads a Dialogue or a Comment line
+func parseASSEvent(format, values []string) (*Cue, error) {
+	start, err := parseASSTimestamp(field(format, values, "Start"))
+	if err != nil {
+		return nil, err
+	}
+	end, err := parseASSTimestamp(field(format, values, "End"))
+	if err != nil {
+		return nil, err
+	}
+	cue := &Cue{
+		Start:  start,
+		End:    end,
+		Style:  strings.TrimPrefix(field(format, values, "Style"), "*"),
+		Name:   field(format, values, "Name"),
+		Effect: field(format, values, "Effect"),
+	}
+	cue.Layer, _ = strconv.Atoi(field(format, values, "Layer"))
+	cue.MarginL, _ = strconv.Atoi(field(format, values, "MarginL"))
+	cue.MarginR, _ = strconv.Atoi(field(format, values, "MarginR"))
+	cue.MarginV, _ = strconv.Atoi(field(format, values, "MarginV"))
+	text := field(format, values, "Text")
+	cue.readASSText(text)
+	cue.ass = &assEvent{format: format, text: text, parsed: *cue}
+	cue.ass.parsed.Lines = append([]string(nil), cue.Lines...)
+	return cue, nil
+}
+
+// readASSText reads the text of an event into the cue. The overrides with an inline tag equivalent become
+// inline tags, the others are kept in Overrides, and drawings are left out
+func (c *Cue) readASSText(text string) {
+	var b strings.Builder
+	var overrides []string
+	c.Align, c.Drawing = 0, false
+	drawing := false
+	add := func(segment string) {
+		if !drawing {
+			b.WriteString(escapeBrackets.Replace(segment))
+		} else if strings.TrimSpace(segment) != "" {
+			c.Drawing = true
+		}
+	}
+
+	pos := 0
+	for _, m := range assOverrideRegexp.FindAllStringSubmatchIndex(text, -1) {
+		add(text[pos:m[0]])
+		pos = m[1]
+		for _, t := range strings.Split(text[m[2]:m[3]], `\`)[1:] {
+			if d := assDrawingTagRegexp.FindStringSubmatch(strings.TrimSpace(t)); d != nil {
+				drawing = d[1] != "0"
+				continue
+			}
+			tag := assOverrideTagRegexp.FindStringSubmatch(strings.TrimSpace(t))
+			if tag == nil {
+				overrides = append(overrides, `\`+t)
+				continue
+			}
+			name, arg := tag[1], tag[2]
+			switch name {
+			case "an":
+				c.Align, _ = strconv.Atoi(arg)
+			case "a":
+				a, _ := strconv.Atoi(arg)
+				c.Align = fromSSAAlignment(a)
+			case "i", "b", "u", "s":
+				if arg == "0" {
+					b.WriteString("</" + name + ">")
+				} else if arg != "" {
+					b.WriteString("<" + name + ">")
+				}
+			case "c", "1c":
+				if color := parseASSColor(arg); color != "" {
+					b.WriteString(`<font color="` + color + `">`)
+				} else {
+					b.WriteString("</font>")
+				}
+			}
+		}
+	}
+	add(text[pos:])
+	c.Overrides = strings.Join(overrides, "")
+	// The lines of the drawings are only left with the tags of their overrides
+	c.Lines = nil
+	for _, l := range cleanLines(strings.Split(assEscapes.Replace(b.String()), "\n")) {
+		if strings.TrimSpace(StripTags(l)) != "" {
+			c.Lines = append(c.Lines, l)
+		}
+	}
+}
+
+// mapText changes the text of the event between its override blocks and its line breaks, drawings apart,
+// and reads the cue again from it
+func (e *assEvent) mapText(c *Cue, f func(string) string) bool {
+	var b strings.Builder
+	drawing := false
+	add := func(segment string) {
+		if drawing {
+			b.WriteString(segment)
+			return
+		}
+		pos := 0
+		for _, m := range assEscapeRegexp.FindAllStringIndex(segment, -1) {
+			b.WriteString(f(segment[pos:m[0]]) + segment[m[0]:m[1]])
+			pos = m[1]
+		}
+		b.WriteString(f(segment[pos:]))
+	}
+
+	pos := 0
+	for _, m := range assOverrideRegexp.FindAllStringSubmatchIndex(e.text, -1) {
+		add(e.text[pos:m[0]])
+		b.WriteString(e.text[m[0]:m[1]])
+		for _, t := range strings.Split(e.text[m[2]:m[3]], `\`)[1:] {
+			if d := assDrawingTagRegexp.FindStringSubmatch(strings.TrimSpace(t)); d != nil {
+				drawing = d[1] != "0"
+			}
+		}
+		pos = m[1]
+	}
+	add(e.text[pos:])
+	text := b.String()
+	if text == e.text {
+		return false
+	}
+
+	i := strings.LastIndex(e.line, e.text)
+	e.line = e.line[:i] + text + e.line[i+len(e.text):]
+	e.text = text
+	c.readASSText(text)
+	e.parsed.Lines = append([]string(nil), c.Lines...)
+	e.parsed.Align, e.parsed.Overrides, e.parsed.Drawing = c.Align, c.Overrides, c.Drawing
+	return true
+}
+
+// textEdited tells if the text of the cue changed since it was read from the event
+func (e *assEvent) textEdited(c *Cue) bool {
+	p := e.parsed
+	if c.Align != p.Align || c.Overrides != p.Overrides || c.Drawing != p.Drawing || len(c.Lines) != len(p.Lines) {
+		return true
+	}
+	for i, l := range c.Lines {
+		if l != p.Lines[i] {
+			return true
+		}
+	}
+	return false
+}
+
+// edited tells if the cue changed since it was read from the event, its times apart
+func (e *assEvent) edited(c *Cue) bool {
+	p := e.parsed
+	return c.Style != p.Style || c.Layer != p.Layer || c.Name != p.Name || c.MarginL != p.MarginL ||
+		c.MarginR != p.MarginR || c.MarginV != p.MarginV || c.Effect != p.Effect || e.textEdited(c)
+}
+
+// retimed gives the line of the event with the times of the cue, the rest of the line as it was
+func (e *assEvent) retimed(c *Cue) string {
+	if c.Start == e.parsed.Start && c.End == e.parsed.End {
+		return e.line
+	}
+	return retimeASSLine(e.line, e.format, c)
+}
+
+// retimeASSLine rewrites the Start and End fields of an event line with the times of the cue
+func retimeASSLine(line string, format []string, c *Cue) string {
+	colon := strings.Index(line, ":")
+	values := strings.SplitN(line[colon+1:], ",", len(format))
+	for i, f := range format {
+		t := c.Start
+		switch {
+		case i >= len(values):
+			continue
+		case strings.EqualFold(f, "End"):
+			t = c.End
+		case !strings.EqualFold(f, "Start"):
+			continue
+		}
+		trimmed := strings.TrimSpace(values[i])
+		at := strings.Index(values[i], trimmed)
+		values[i] = values[i][:at] + formatASSTimestamp(t) + values[i][at+len(trimmed):]
+	}
+	return line[:colon+1] + strings.Join(values, ",")
+}
+
+// parseASSTimestamp parses H:MM:SS.cc timestamps
+func parseASSTimestamp(s string) (time.Duration, error) {
+	return ParseTimestamp(s)
+}
+
+// parseASSColor converts &HAABBGGRR, &HBBGGRR or decimal BGR colors to #rrggbb
+func parseASSColor(s string) string {
+	s = strings.Trim(strings.TrimSpace(s), "&")
+	var n uint64
+	var err error
+	if strings.HasPrefix(strings.ToUpper(s), "H") {
+		n, err = strconv.ParseUint(s[1:], 16, 64)
+	} else {
+		n, err = strconv.ParseUint(s, 10, 64)
+	}
+	if err != nil || s == "" {
+		return ""
+	}
+	return fmt.Sprintf("#%02x%02x%02x", n&0xff, (n>>8)&0xff, (n>>16)&0xff)
+}
+
+// formatASSColor converts #rrggbb colors to &H00BBGGRR
+func formatASSColor(color string) string {
+	n, err := strconv.ParseUint(strings.TrimPrefix(color, "#"), 16, 32)
+	if err != nil || len(color) != 7 {
+		return "&H00FFFFFF"
+	}
+	return fmt.Sprintf("&H00%02X%02X%02X", n&0xff, (n>>8)&0xff, (n>>16)&0xff)
+}
+
+// fromSSAAlignment converts the SSA alignment (1-3 bottom, 5-7 top, 9-11 middle) to a numeric keypad alignment
+func fromSSAAlignment(a int) int {
+	switch {
+	case a >= 9 && a <= 11:
+		return a - 5
+	case a >= 5 && a <= 7:
+		return a + 2
+	case a >= 1 && a <= 3:
+		return a
+	}
+	return 0
+}
+
+// toSSAAlignment converts a numeric keypad alignment to the SSA alignment
+func toSSAAlignment(a int) int {
+	switch {
+	case a >= AlignTopLeft:
+		return a - 2
+	case a >= AlignMiddleLeft:
+		return a + 5
+	}
+	return a
+}
+
+// WriteASS writes the subtitle as Advanced SubStation Alpha
+func WriteASS(w io.Writer, s *Subtitle) error {
+	return writeASS(w, s, false)
+}
+
+// WriteSSA writes the subtitle as SubStation Alpha (V4)
+func WriteSSA(w io.Writer, s *Subtitle) error {
+	return writeASS(w, s, true)
+}
+
+// writeASS writes the subtitle read from an ASS/SSA file of the same version as it was, the changes apart.
+// Other subtitles get a new header and the default style
+func writeASS(w io.Writer, s *Subtitle, legacy bool) error {
+	if s.keepsASSLayout(legacy) {
+		_, err := io.WriteString(w, strings.Join(s.ass.write(s, true), s.ass.newline))
+		return err
+	}
+
+	header, eventFormat := assHeader(s, legacy)
+	bw := bufio.NewWriter(w)
+	fmt.Fprint(bw, header)
+	for _, line := range assEventLines(s, eventFormat, legacy, false) {
+		fmt.Fprintln(bw, line)
+	}
+	return bw.Flush()
+}
+
+// keepsASSLayout tells if the subtitle is written in the layout of the file it was read from
+func (s *Subtitle) keepsASSLayout(legacy bool) bool {
+	l := s.ass
+	return l != nil && l.legacy == legacy && (l.styles >= 0 || len(s.Styles) == 0)
+}
+
+// assHeader gives a new header, up to the Format line of the events, and the format of the events
+func assHeader(s *Subtitle, legacy bool) (string, []string) {
+	var b strings.Builder
+	fmt.Fprint(&b, "[Script Info]\n")
+	scriptType := "v4.00+"
+	if legacy {
+		scriptType = "v4.00"
+	}
+	fmt.Fprintf(&b, "ScriptType: %s\n", scriptType)
+	keys := []string{"Title", "PlayResX", "PlayResY", "WrapStyle", "ScaledBorderAndShadow", "YCbCr Matrix"}
+	if s.ass != nil {
+		keys = s.ass.info
+	}
+	for _, key := range keys {
+		if value, ok := s.Metadata[key]; ok && key != "ScriptType" {
+			fmt.Fprintf(&b, "%s: %s\n", key, value)
+		}
+	}
+
+	styles := s.Styles
+	if s.Style("Default") == nil {
+		styles = append([]*Style{&assDefaultStyle}, styles...)
+	}
+	styleFormat, eventFormat := assStyleFormat, assEventFormat
+	if legacy {
+		styleFormat, eventFormat = ssaStyleFormat, ssaEventFormat
+		fmt.Fprint(&b, "\n[V4 Styles]\n")
+	} else {
+		fmt.Fprint(&b, "\n[V4+ Styles]\n")
+	}
+	fmt.Fprintf(&b, "Format: %s\n", strings.Join(styleFormat, ", "))
+	for _, st := range styles {
+		fmt.Fprintln(&b, assStyleLine(st, styleFormat, legacy, false))
+	}
+
+	fmt.Fprint(&b, "\n[Events]\n")
+	fmt.Fprintf(&b, "Format: %s\n", strings.Join(eventFormat, ", "))
+	return b.String(), eventFormat
+}
+
+// ASSEvents gives the subtitle written as ASS, or SSA when legacy, split in its header (all the lines but the events)
+// and the Dialogue event of each cue, as values by lower case field name (layer, style, text...).
+// Like WriteASS, the lines read from a file are kept as they were, their new times apart. Comments are left out
+func ASSEvents(s *Subtitle, legacy bool) (string, []map[string]string) {
+	var header string
+	var eventFormat []string
+	original := s.keepsASSLayout(legacy)
+	if original {
+		header = strings.Join(s.ass.write(s, false), "\n")
+		eventFormat = s.ass.eventFormat
+	} else {
+		header, eventFormat = assHeader(s, legacy)
+	}
+
+	events := make([]map[string]string, len(s.Cues))
+	for i, c := range s.Cues {
+		line := assEventLine("Dialogue", c, eventFormat, legacy, original)
+		values := strings.SplitN(line[strings.Index(line, ":")+1:], ",", len(eventFormat))
+		events[i] = map[string]string{}
+		for j, f := range eventFormat {
+			if j < len(values) {
+				value := values[j]
+				if !strings.EqualFold(f, "Text") {
+					value = strings.TrimSpace(value)
+				}
+				events[i][strings.ToLower(f)] = value
+			}
+		}
+	}
+	return header, events
+}
+
+// write gives the lines of the file, with the styles and the events of the subtitle at their place.
+// The events are left out unless asked
+func (l *assLayout) write(s *Subtitle, events bool) []string {
+	var lines []string
+	for i := 0; i <= len(l.lines); i++ {
+		if i == l.styles {
+			for _, st := range s.Styles {
+				lines = append(lines, assStyleLine(st, l.styleFormat, l.legacy, true))
+			}
+		}
+		if i == l.events && events {
+			lines = append(lines, assEventLines(s, l.eventFormat, l.legacy, true)...)
+		}
+		if i < len(l.lines) {
+			lines = append(lines, l.lines[i])
+		}
+	}
+	return lines
+}
+
+// assStyleLine gives the Style line of a style. The line it was read from is kept when asked and not changed
+func assStyleLine(st *Style, format []string, legacy, original bool) string {
+	if original && st.ass != nil && reflect.DeepEqual(st.copy(), st.ass.parsed) {
+		return st.ass.line
+	}
+	values := make([]string, len(format))
+	for i, f := range format {
+		switch f {
+		case "Name":
+			values[i] = st.Name
+		case "Fontname":
+			values[i] = st.FontName
+			if values[i] == "" {
+				values[i] = assDefaultStyle.FontName
+			}
+		case "Fontsize":
+			size := st.FontSize
+			if size == 0 {
+				size = assDefaultStyle.FontSize
+			}
+			values[i] = formatFloat(size)
+		case "PrimaryColour":
+			values[i] = formatASSColor(st.Color)
+		case "Bold":
+			values[i] = assBool(st.Bold)
+		case "Italic":
+			values[i] = assBool(st.Italic)
+		case "Underline":
+			values[i] = assBool(st.Underline)
+		case "Alignment":
+			align := st.Alignment
+			if align == 0 {
+				align = AlignBottomCenter
+			}
+			if legacy {
+				align = toSSAAlignment(align)
+			}
+			values[i] = strconv.Itoa(align)
+		default:
+			value, ok := st.Fields[f]
+			if !ok && legacy {
+				value, ok = ssaStyleDefaults[f]
+			}
+			if !ok {
+				value = assStyleDefaults[f]
+			}
+			values[i] = value
+		}
+	}
+	return "Style: " + strings.Join(values, ",")
+}
+
+// assEventLines gives the Dialogue lines of the cues, with the Comment lines of the subtitle after the cue
+// they followed. The lines the cues were read from are kept when asked, with their new times
+func assEventLines(s *Subtitle, format []string, legacy, original bool) []string {
+	after := map[*Cue][]*Cue{}
+	var orphans []*Cue // Comments after a removed cue
+	if s.ass != nil {
+		kept := map[*Cue]bool{nil: true}
+		for _, c := range s.Cues {
+			kept[c] = true
+		}
+		for _, comment := range s.ass.comments {
+			if kept[comment.after] {
+				after[comment.after] = append(after[comment.after], comment.cue)
+			} else {
+				orphans = append(orphans, comment.cue)
+			}
+		}
+	}
+
+	var lines []string
+	for _, c := range after[nil] {
+		lines = append(lines, assEventLine("Comment", c, format, legacy, original))
+	}
+	for _, c := range s.Cues {
+		lines = append(lines, assEventLine("Dialogue", c, format, legacy, original))
+		for _, comment := range after[c] {
+			lines = append(lines, assEventLine("Comment", comment, format, legacy, original))
+		}
+	}
+	for _, c := range orphans {
+		lines = append(lines, assEventLine("Comment", c, format, legacy, original))
+	}
+	return lines
+}
+
+// assEventLine gives the event line of a cue
+func assEventLine(kind string, c *Cue, format []string, legacy, original bool) string {
+	if original && c.ass != nil && !c.ass.edited(c) {
+		return c.ass.retimed(c)
+	}
+	var text string
+	if c.ass != nil && !c.ass.textEdited(c) {
+		text = c.ass.text
+	} else {
+		text = toASSText(strings.Join(c.Lines, `\N`))
+		overrides := c.Overrides
+		if c.Align != 0 && legacy {
+			overrides = fmt.Sprintf(`\a%d`, toSSAAlignment(c.Align)) + overrides
+		} else if c.Align != 0 {
+			overrides = fmt.Sprintf(`\an%d`, c.Align) + overrides
+		}
+		if overrides != "" {
+			text = "{" + overrides + "}" + text
+		}
+	}
+
+	values := make([]string, len(format))
+	for i, f := range format {
+		switch strings.ToLower(f) {
+		case "layer":
+			values[i] = strconv.Itoa(c.Layer)
+		case "marked":
+			values[i] = "Marked=0"
+		case "start":
+			values[i] = formatASSTimestamp(c.Start)
+		case "end":
+			values[i] = formatASSTimestamp(c.End)
+		case "style":
+			values[i] = c.Style
+			if values[i] == "" {
+				values[i] = "Default"
+			}
+		case "name":
+			values[i] = c.Name
+		case "marginl":
+			values[i] = strconv.Itoa(c.MarginL)
+		case "marginr":
+			values[i] = strconv.Itoa(c.MarginR)
+		case "marginv":
+			values[i] = strconv.Itoa(c.MarginV)
+		case "effect":
+			values[i] = c.Effect
+		case "text":
+			values[i] = text
+		}
+	}
+	return kind + ": " + strings.Join(values, ",")
+}
+
+// toASSText converts inline tags to ASS overrides, and unescapes the angle brackets
+func toASSText(text string) string {
+	return unescapeBrackets.Replace(tagRegexp.ReplaceAllStringFunc(text, func(tag string) string {
+		m := tagRegexp.FindStringSubmatch(tag)
+		closing, name := m[1] == "/", strings.ToLower(m[2])
+		switch name {
+		case "i", "b", "u", "s":
+			if closing {
+				return `{\` + name + `0}`
+			}
+			return `{\` + name + `1}`
+		case "font":
+			if closing {
+				return `{\c}`
+			}
+			if c := fontColorRegex.FindStringSubmatch(m[3]); c != nil {
+				return `{\c&H` + strings.TrimPrefix(formatASSColor(c[1]), "&H00") + `&}`
+			}
+		}
+		return ""
+	}))
+}
+
+// formatASSTimestamp formats a duration as H:MM:SS.cc
+func formatASSTimestamp(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	cs := int64(d / (10 * time.Millisecond))
+	return fmt.Sprintf("%d:%02d:%02d.%02d", cs/360000, cs/6000%60, cs/100%60, cs%100)
+}
+
+func assBool(b bool) string {
+	if b {
+		return "-1"
+	}
+	return "0"
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
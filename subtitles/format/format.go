@@ -0,0 +1,186 @@
+// Package format reads and writes subtitle files into an in-memory model of cues,
+// so that subtitles can be inspected, fixed and converted.
+package format
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Subtitle is the in-memory model of a subtitle file
+type Subtitle struct {
+	Cues     []*Cue
+	Styles   []*Style          // Named styles, for formats supporting them (ASS/SSA)
+	Metadata map[string]string // Headers of the file (ASS script info, WebVTT header...)
+
+	ass *assLayout // Parts of the ASS/SSA file the subtitle was read from, to write them back as they were
+}
+
+// Cue is one text displayed between two instants
+type Cue struct {
+	Index int           // Number of the cue in the original file, 1 based
+	Start time.Duration // Display time
+	End   time.Duration // Hiding time
+	Lines []string      // Text of the cue, with inline styling as HTML like tags (<i>, <b>, <u>, <font color="#rrggbb">) and &lt; &gt; for < and >
+	Style string        // Name of the style of the cue, empty for the default style
+	Align int           // Position on the screen like Style.Alignment, 0 to use the style
+
+	// Fields of ASS/SSA events, written back to ASS/SSA files
+	Layer     int    // Cues of higher layers are drawn over the others
+	Name      string // Name of the speaker
+	MarginL   int    // Margins overriding the ones of the style, 0 to use the style
+	MarginR   int
+	MarginV   int
+	Effect    string
+	Overrides string // Override tags without inline tag (\pos, \fad, \k...), kept when the text is rewritten
+	Drawing   bool   // The text draws shapes ({\p1}), which are not in Lines
+
+	pos int       // Line of the timing of the cue in the file it was read from (offset of the p element in TTML), from 1
+	end int       // Last line of the text of the cue in the file it was read from (end of the p element in TTML)
+	src *source   // Text the cue was read from, to write it back as it was
+	ass *assEvent // Event the cue was read from, to write it back as it was
+}
+
+// Style is a named style of cues
+type Style struct {
+	Name      string
+	FontName  string
+	FontSize  float64
+	Color     string // Primary color as #rrggbb
+	Bold      bool
+	Italic    bool
+	Underline bool
+	Alignment int // Position on the screen, as a numeric keypad (2 is bottom center, 8 is top center)
+	// Fields of ASS/SSA styles without equivalent (OutlineColour, Outline, Shadow, MarginV...), by name
+	Fields map[string]string
+
+	ass *assStyle // Style line the style was read from, to write it back as it was
+}
+
+// Alignments on the screen, as a numeric keypad
+const (
+	AlignBottomLeft   = 1
+	AlignBottomCenter = 2
+	AlignBottomRight  = 3
+	AlignMiddleLeft   = 4
+	AlignMiddleCenter = 5
+	AlignMiddleRight  = 6
+	AlignTopLeft      = 7
+	AlignTopCenter    = 8
+	AlignTopRight     = 9
+)
+
+// Text gives the lines of the cue, separated by new lines
+func (c *Cue) Text() string {
+	return strings.Join(c.Lines, "\n")
+}
+
+// PlainText gives the text of the cue without any styling
+func (c *Cue) PlainText() string {
+	return StripTags(c.Text())
+}
+
+// MapText changes the text of the cue with f, given the text between its tags. The override blocks and
+// the drawings of ASS/SSA cues are kept, like the markup of the text read from other formats, written back by Patch.
+// It tells if the text changed
+func (c *Cue) MapText(f func(string) string) bool {
+	if c.ass != nil {
+		return c.ass.mapText(c, f)
+	}
+	src := c.src
+	if src != nil && src.edited(c) {
+		src = nil
+	}
+	changed := false
+	for i, l := range c.Lines {
+		if t := f(l); t != l {
+			c.Lines[i], changed = t, true
+		}
+	}
+	if src != nil {
+		changed = src.mapText(f) || changed
+		src.lines = append([]string(nil), c.Lines...)
+	}
+	return changed
+}
+
+// Duration gives the display duration of the cue
+func (c *Cue) Duration() time.Duration {
+	return c.End - c.Start
+}
+
+// Style gives the named style, nil if it does not exist
+func (s *Subtitle) Style(name string) *Style {
+	for _, style := range s.Styles {
+		if style.Name == name {
+			return style
+		}
+	}
+	return nil
+}
+
+// Sort sorts the cues by start time, keeping the original order for cues starting together
+func (s *Subtitle) Sort() {
+	sort.SliceStable(s.Cues, func(i, j int) bool {
+		return s.Cues[i].Start < s.Cues[j].Start
+	})
+}
+
+// Renumber sets the index of the cues to their position, starting at 1
+func (s *Subtitle) Renumber() {
+	for i, c := range s.Cues {
+		c.Index = i + 1
+	}
+}
+
+// textCues gives the cues with a text to show, for the formats which can't draw: ASS drawings are left out
+func (s *Subtitle) textCues() []*Cue {
+	var cues []*Cue
+	for _, c := range s.Cues {
+		if !c.Drawing || len(c.Lines) > 0 {
+			cues = append(cues, c)
+		}
+	}
+	return cues
+}
+
+// Alignment gives the position of the cue on the screen, from the cue or its style
+func (s *Subtitle) Alignment(c *Cue) int {
+	if c.Align != 0 {
+		return c.Align
+	}
+	if style := s.Style(c.Style); style != nil && style.Alignment != 0 {
+		return style.Alignment
+	}
+	return AlignBottomCenter
+}
+
+// StyledLines gives the lines of the cue with the bold, italic, underline and color of its style
+// turned into inline tags, for formats without named styles
+func (s *Subtitle) StyledLines(c *Cue) []string {
+	style := s.Style(c.Style)
+	if style == nil {
+		return c.Lines
+	}
+	var open, close string
+	if style.Color != "" && !strings.EqualFold(style.Color, "#ffffff") {
+		open, close = open+`<font color="`+style.Color+`">`, "</font>"+close
+	}
+	for _, t := range []struct {
+		enabled bool
+		tag     string
+	}{{style.Bold, "b"}, {style.Italic, "i"}, {style.Underline, "u"}} {
+		if t.enabled {
+			open, close = open+"<"+t.tag+">", "</"+t.tag+">"+close
+		}
+	}
+	if open == "" {
+		return c.Lines
+	}
+	lines := make([]string, len(c.Lines))
+	for i, l := range c.Lines {
+		lines[i] = open + l + close
+	}
+	return lines
+}
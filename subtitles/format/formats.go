@@ -0,0 +1,238 @@
+package format
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/matcornic/subify/subtitles/charset"
+)
+
+// Format is a subtitle file format
+type Format string
+
+// Supported formats
+const (
+	SRT       Format = "srt"
+	VTT       Format = "vtt"
+	ASS       Format = "ass"
+	SSA       Format = "ssa"
+	MicroDVD  Format = "sub"
+	SubViewer Format = "subviewer"
+	TTML      Format = "ttml"
+	SBV       Format = "sbv"
+)
+
+// DefaultFrameRate is used for frame based formats (MicroDVD) when the frame rate is unknown
+const DefaultFrameRate = 23.976
+
+// Formats lists all supported formats
+var Formats = []Format{SRT, VTT, ASS, SSA, MicroDVD, SubViewer, TTML, SBV}
+
+// formatAliases are the other names accepted for formats
+var formatAliases = map[string]Format{
+	"subrip":   SRT,
+	"webvtt":   VTT,
+	"microdvd": MicroDVD,
+	"mdvd":     MicroDVD,
+	"dfxp":     TTML,
+	"xml":      TTML,
+}
+
+// Options changes how subtitles are read and written
+type Options struct {
+	FrameRate float64 // Frames per second for frame based formats. 0 to use the one of the file or DefaultFrameRate
+}
+
+// ParseFormat gives the format from its name or extension (srt, .vtt, webvtt...)
+func ParseFormat(name string) (Format, error) {
+	name = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(name), "."))
+	for _, f := range Formats {
+		if string(f) == name {
+			return f, nil
+		}
+	}
+	if f, ok := formatAliases[name]; ok {
+		return f, nil
+	}
+	return "", fmt.Errorf("Unknown subtitle format %v", name)
+}
+
+// Extension gives the file extension of the format, with the dot
+func (f Format) Extension() string {
+	if f == SubViewer {
+		return ".sub"
+	}
+	return "." + string(f)
+}
+
+// Detect guesses the format from the file name and its content.
+// The .sub extension is shared by MicroDVD and SubViewer, hence the content decides
+func Detect(name string, data []byte) (Format, error) {
+	ext := strings.ToLower(filepath.Ext(name))
+	head := strings.TrimSpace(string(bytes.TrimPrefix(data[:min(len(data), 1024)], utf8BOM)))
+	switch {
+	case ext == ".sub":
+		if strings.HasPrefix(head, "{") {
+			return MicroDVD, nil
+		}
+		return SubViewer, nil
+	case ext != "":
+		if f, err := ParseFormat(ext); err == nil {
+			return f, nil
+		}
+	}
+
+	// No (known) extension, look at the content
+	switch {
+	case strings.HasPrefix(head, "WEBVTT"):
+		return VTT, nil
+	case strings.HasPrefix(head, "[Script Info]"):
+		if strings.Contains(string(data), "[V4+ Styles]") {
+			return ASS, nil
+		}
+		return SSA, nil
+	case strings.HasPrefix(head, "<?xml") || strings.HasPrefix(head, "<tt"):
+		return TTML, nil
+	case strings.HasPrefix(head, "{"):
+		return MicroDVD, nil
+	case strings.HasPrefix(head, "[INFORMATION]"):
+		return SubViewer, nil
+	case sbvTimingRegexp.MatchString(strings.SplitN(head, "\n", 2)[0]):
+		return SBV, nil
+	case timingRegexp.MatchString(head) || indexRegexp.MatchString(strings.SplitN(head, "\n", 2)[0]):
+		return SRT, nil
+	}
+	return "", fmt.Errorf("Can't recognize the format of subtitle %v", name)
+}
+
+// Parse reads the content of a subtitle in the given format
+func Parse(data []byte, f Format, opts Options) (*Subtitle, error) {
+	switch f {
+	case SRT:
+		return ParseSRT(data)
+	case VTT:
+		return ParseVTT(data)
+	case ASS, SSA:
+		return ParseASS(data)
+	case MicroDVD:
+		return ParseMicroDVD(data, opts.FrameRate)
+	case SubViewer:
+		return ParseSubViewer(data)
+	case TTML:
+		return ParseTTML(data)
+	case SBV:
+		return ParseSBV(data)
+	}
+	return nil, fmt.Errorf("Unknown subtitle format %v", f)
+}
+
+// Write writes the subtitle in the given format
+func Write(w io.Writer, s *Subtitle, f Format, opts Options) error {
+	switch f {
+	case SRT:
+		return WriteSRT(w, s)
+	case VTT:
+		return WriteVTT(w, s)
+	case ASS:
+		return WriteASS(w, s)
+	case SSA:
+		return WriteSSA(w, s)
+	case MicroDVD:
+		return WriteMicroDVD(w, s, opts.FrameRate)
+	case SubViewer:
+		return WriteSubViewer(w, s)
+	case TTML:
+		return WriteTTML(w, s)
+	case SBV:
+		return WriteSBV(w, s)
+	}
+	return fmt.Errorf("Unknown subtitle format %v", f)
+}
+
+// ReadFile reads a subtitle file, guessing its format
+func ReadFile(path string, opts Options) (*Subtitle, Format, error) {
+	data, f, err := readFile(path)
+	if err != nil {
+		return nil, "", err
+	}
+	s, err := Parse(data, f, opts)
+	if err != nil {
+		return nil, "", fmt.Errorf("Can't read %v as %v: %v", path, f, err)
+	}
+	return s, f, nil
+}
+
+// DetectFile guesses the format of a subtitle file, from its name and content
+func DetectFile(path string) (Format, error) {
+	_, f, err := readFile(path)
+	return f, err
+}
+
+// readFile reads a subtitle file as UTF-8, as parsers work on UTF-8 text, and guesses its format
+func readFile(path string) ([]byte, Format, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("Can't read the file %v because of : %v", path, err)
+	}
+	if data, _, err = charset.ToUTF8(data, ""); err != nil {
+		return nil, "", err
+	}
+	f, err := Detect(path, data)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, f, nil
+}
+
+// WriteFile writes a subtitle file in the given format
+func WriteFile(path string, s *Subtitle, f Format, opts Options) error {
+	buf := new(bytes.Buffer)
+	if err := Write(buf, s, f, opts); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("Can't save the file %v because of : %v", path, err)
+	}
+	return nil
+}
+
+// PatchFile saves to output the subtitle read from input, patching the content of input with its changes. See Patch
+func PatchFile(input, output string, s *Subtitle, f Format, opts Options) error {
+	data, _, err := readFile(input)
+	if err != nil {
+		return err
+	}
+	patched, err := Patch(data, s, f, opts)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(output, patched, 0644); err != nil {
+		return fmt.Errorf("Can't save the file %v because of : %v", output, err)
+	}
+	return nil
+}
+
+// Convert converts a subtitle file to another format
+func Convert(input, output string, to Format, opts Options) error {
+	s, _, err := ReadFile(input, opts)
+	if err != nil {
+		return err
+	}
+	return WriteFile(output, s, to, opts)
+}
+
+// ConvertedPath gives the path of the subtitle converted to another format, next to the original
+func ConvertedPath(path string, to Format) string {
+	return strings.TrimSuffix(path, filepath.Ext(path)) + to.Extension()
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
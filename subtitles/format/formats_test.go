@@ -0,0 +1,438 @@
+package format
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func sampleSubtitle() *Subtitle {
+	return &Subtitle{Cues: []*Cue{
+		{Index: 1, Start: time.Second, End: 2500 * time.Millisecond, Lines: []string{"<i>Hello</i>", "World"}},
+		{Index: 2, Start: time.Minute, End: time.Minute + 1500*time.Millisecond, Lines: []string{"Top & <b>bold</b>"}, Align: AlignTopCenter},
+	}}
+}
+
+func TestConversionShouldRoundTripAllFormats(t *testing.T) {
+	for _, f := range Formats {
+		buf := new(bytes.Buffer)
+		assert.Nil(t, Write(buf, sampleSubtitle(), f, Options{}), string(f))
+
+		detected, err := Detect("subtitle"+f.Extension(), buf.Bytes())
+		assert.Nil(t, err, string(f))
+		assert.Equal(t, f, detected)
+
+		sub, err := Parse(buf.Bytes(), f, Options{})
+		assert.Nil(t, err, string(f))
+		if !assert.Equal(t, 2, len(sub.Cues), string(f)) {
+			continue
+		}
+		// Frame based and centisecond formats are approximate
+		assert.InDelta(t, float64(time.Second), float64(sub.Cues[0].Start), float64(50*time.Millisecond), string(f))
+		assert.InDelta(t, float64(time.Minute+1500*time.Millisecond), float64(sub.Cues[1].End), float64(50*time.Millisecond), string(f))
+		assert.Equal(t, "Hello\nWorld", sub.Cues[0].PlainText(), string(f))
+		assert.Equal(t, "Top & bold", sub.Cues[1].PlainText(), string(f))
+	}
+}
+
+func TestConversionShouldPreserveStylingWherePossible(t *testing.T) {
+	for _, f := range []Format{SRT, VTT, ASS, SSA, MicroDVD, TTML} {
+		buf := new(bytes.Buffer)
+		assert.Nil(t, Write(buf, sampleSubtitle(), f, Options{}))
+		sub, err := Parse(buf.Bytes(), f, Options{})
+		assert.Nil(t, err, string(f))
+		assert.Equal(t, "<i>Hello</i>", sub.Cues[0].Lines[0], string(f))
+	}
+	for _, f := range []Format{SRT, VTT, ASS, TTML} {
+		buf := new(bytes.Buffer)
+		assert.Nil(t, Write(buf, sampleSubtitle(), f, Options{}))
+		sub, _ := Parse(buf.Bytes(), f, Options{})
+		assert.Equal(t, AlignTopCenter, sub.Alignment(sub.Cues[1]), string(f))
+	}
+}
+
+func TestParseMicroDVDShouldUseFrameRate(t *testing.T) {
+	data := []byte("{1}{1}25\n{25}{50}{Y:i}Hello|World\n")
+	sub, err := ParseMicroDVD(data, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(sub.Cues))
+	assert.Equal(t, time.Second, sub.Cues[0].Start)
+	assert.Equal(t, 2*time.Second, sub.Cues[0].End)
+	assert.Equal(t, []string{"<i>Hello</i>", "<i>World</i>"}, sub.Cues[0].Lines)
+
+	// The declared frame rate is written back
+	buf := new(bytes.Buffer)
+	assert.Nil(t, WriteMicroDVD(buf, sub, 0))
+	assert.Equal(t, "{1}{1}25\n{25}{50}{y:i}Hello|{y:i}World\n", buf.String())
+
+	sub, _ = ParseMicroDVD([]byte("{25}{50}{y:i}Hello|World"), 25)
+	assert.Equal(t, []string{"<i>Hello</i>", "World"}, sub.Cues[0].Lines)
+
+	// The given frame rate wins over the declared one
+	sub, err = ParseMicroDVD(data, 50)
+	assert.Nil(t, err)
+	assert.Equal(t, 500*time.Millisecond, sub.Cues[0].Start)
+
+	// Without declaration, the default frame rate is used
+	sub, err = ParseMicroDVD([]byte("{23976}{24000}Hello"), 0)
+	assert.Nil(t, err)
+	assert.InDelta(t, float64(1000*time.Second), float64(sub.Cues[0].Start), float64(time.Millisecond))
+}
+
+func TestParseTTMLShouldReadTimeExpressions(t *testing.T) {
+	data := []byte(`<?xml version="1.0" encoding="utf-8"?>
+<tt xmlns="http://www.w3.org/ns/ttml" xmlns:ttp="http://www.w3.org/ns/ttml#parameter" xmlns:tts="http://www.w3.org/ns/ttml#styling" ttp:tickRate="10000000">
+  <head><styling><style xml:id="it" tts:fontStyle="italic"/></styling></head>
+  <body><div>
+    <p begin="10000000t" end="25000000t">Hello<br/><span style="it">World</span></p>
+    <p begin="3s" dur="500ms">Bye</p>
+  </div></body>
+</tt>`)
+	sub, err := ParseTTML(data)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(sub.Cues))
+	assert.Equal(t, time.Second, sub.Cues[0].Start)
+	assert.Equal(t, 2500*time.Millisecond, sub.Cues[0].End)
+	assert.Equal(t, []string{"Hello", "<i>World</i>"}, sub.Cues[0].Lines)
+	assert.Equal(t, 3500*time.Millisecond, sub.Cues[1].End)
+}
+
+func TestDetectShouldTellSubFormatsApart(t *testing.T) {
+	f, err := Detect("movie.sub", []byte("{1}{25}Hello"))
+	assert.Nil(t, err)
+	assert.Equal(t, MicroDVD, f)
+	f, err = Detect("movie.sub", []byte("[INFORMATION]\n00:00:01.00,00:00:02.00\nHello"))
+	assert.Nil(t, err)
+	assert.Equal(t, SubViewer, f)
+	f, err = Detect("captions", []byte("0:00:01.000,0:00:02.000\nHello"))
+	assert.Nil(t, err)
+	assert.Equal(t, SBV, f)
+}
+
+func TestShiftShouldMoveSelectedCues(t *testing.T) {
+	sub := sampleSubtitle()
+	sub.Shift(-1500*time.Millisecond, nil)
+	assert.Equal(t, time.Duration(0), sub.Cues[0].Start)
+	assert.Equal(t, time.Second, sub.Cues[0].End)
+	assert.Equal(t, time.Minute-1500*time.Millisecond, sub.Cues[1].Start)
+
+	sub = sampleSubtitle()
+	sub.Shift(time.Second, func(i int, c *Cue) bool { return i >= 1 })
+	assert.Equal(t, time.Second, sub.Cues[0].Start)
+	assert.Equal(t, time.Minute+time.Second, sub.Cues[1].Start)
+}
+
+func TestParseOffsetShouldAcceptDurationsAndTimestamps(t *testing.T) {
+	for s, expected := range map[string]time.Duration{
+		"+1.5s":         1500 * time.Millisecond,
+		"-200ms":        -200 * time.Millisecond,
+		"1m2s":          62 * time.Second,
+		"-00:00:01,500": -1500 * time.Millisecond,
+		"01:02.5":       62500 * time.Millisecond,
+	} {
+		d, err := ParseOffset(s)
+		assert.Nil(t, err, s)
+		assert.Equal(t, expected, d, s)
+	}
+	_, err := ParseOffset("soon")
+	assert.NotNil(t, err)
+}
+
+func TestTwoPointTransformShouldMapBothPoints(t *testing.T) {
+	scale, offset, err := TwoPointTransform(10*time.Second, 12*time.Second, 110*time.Second, 122*time.Second)
+	assert.Nil(t, err)
+	sub := &Subtitle{Cues: []*Cue{{Start: 10 * time.Second, End: 60 * time.Second}, {Start: 110 * time.Second, End: 111 * time.Second}}}
+	sub.Transform(scale, offset, nil)
+	assert.Equal(t, 12*time.Second, sub.Cues[0].Start)
+	assert.Equal(t, 67*time.Second, sub.Cues[0].End)
+	assert.Equal(t, 122*time.Second, sub.Cues[1].Start)
+
+	_, _, err = TwoPointTransform(time.Second, time.Second, time.Second, 2*time.Second)
+	assert.NotNil(t, err)
+}
+
+func TestFrameRateScaleShouldConvertPALToFilm(t *testing.T) {
+	scale, err := FrameRateScale(25, 23.976)
+	assert.Nil(t, err)
+	sub := &Subtitle{Cues: []*Cue{{Start: 23976 * time.Second, End: 23976 * time.Second}}}
+	sub.Transform(scale, 0, nil)
+	assert.InDelta(t, float64(25000*time.Second), float64(sub.Cues[0].Start), float64(time.Millisecond))
+}
+
+func TestWrapShouldBalanceLinesAndKeepDialogues(t *testing.T) {
+	lines := Wrap([]string{"- Are you coming with us to the party tonight?", "- No."}, 42)
+	assert.Equal(t, []string{"- Are you coming with", "us to the party tonight?", "- No."}, lines)
+
+	lines = Wrap([]string{"<i>Short</i>", "<i>lines are joined</i>"}, 42)
+	assert.Equal(t, []string{"<i>Short</i> <i>lines are joined</i>"}, lines)
+}
+
+func TestMergeShouldAttachSecondaryCuesToOverlappingPrimaryCues(t *testing.T) {
+	primary := &Subtitle{Cues: []*Cue{
+		{Start: time.Second, End: 3 * time.Second, Lines: []string{"Hello"}},
+		{Start: 4 * time.Second, End: 6 * time.Second, Lines: []string{"How are you?"}},
+		{Start: 10 * time.Second, End: 11 * time.Second, Align: AlignTopCenter, Lines: []string{"EXIT"}},
+	}}
+	secondary := &Subtitle{Cues: []*Cue{
+		{Start: 1200 * time.Millisecond, End: 3100 * time.Millisecond, Lines: []string{"Bonjour"}},
+		{Start: 3900 * time.Millisecond, End: 5 * time.Second, Lines: []string{"Comment"}},
+		{Start: 5 * time.Second, End: 6200 * time.Millisecond, Lines: []string{"vas-tu ?"}},
+		{Start: 8 * time.Second, End: 9 * time.Second, Lines: []string{"Seul"}},
+	}}
+
+	stacked := Merge(primary, secondary, true)
+	if assert.Equal(t, 4, len(stacked.Cues)) {
+		assert.Equal(t, []string{"Hello", `<font color="#ffff00">Bonjour</font>`}, stacked.Cues[0].Lines)
+		assert.Equal(t, []string{"How are you?", `<font color="#ffff00">Comment</font>`, `<font color="#ffff00">vas-tu ?</font>`}, stacked.Cues[1].Lines)
+		assert.Equal(t, 8*time.Second, stacked.Cues[2].Start)
+		assert.Equal(t, 3, stacked.Cues[2].Index)
+		assert.Equal(t, AlignBottomCenter, stacked.Alignment(stacked.Cues[0]))
+		assert.Equal(t, AlignTopCenter, stacked.Alignment(stacked.Cues[3]))
+	}
+	buf := new(bytes.Buffer)
+	assert.Nil(t, WriteSRT(buf, stacked))
+	assert.Equal(t, 1, strings.Count(buf.String(), `{\an`))
+	assert.Contains(t, buf.String(), `{\an8}EXIT`)
+
+	styled := Merge(primary, secondary, false)
+	if assert.Equal(t, 6, len(styled.Cues)) {
+		assert.Equal(t, PrimaryStyle, styled.Cues[0].Style)
+		assert.Equal(t, 0, styled.Cues[0].Align)
+		assert.Equal(t, AlignTopCenter, styled.Alignment(styled.Cues[5]))
+		assert.Equal(t, SecondaryStyle, styled.Cues[1].Style)
+		assert.Equal(t, time.Second, styled.Cues[1].Start)
+		assert.Equal(t, AlignTopCenter, styled.Alignment(styled.Cues[1]))
+		assert.Equal(t, []string{"Comment", "vas-tu ?"}, styled.Cues[3].Lines)
+	}
+	// The originals are not changed
+	assert.Equal(t, []string{"Hello"}, primary.Cues[0].Lines)
+}
+
+func TestTTMLShouldRoundTripAlignment(t *testing.T) {
+	buf := new(bytes.Buffer)
+	assert.Nil(t, WriteTTML(buf, sampleSubtitle()))
+	sub, err := ParseTTML(buf.Bytes())
+	assert.Nil(t, err)
+	assert.Equal(t, 0, sub.Cues[0].Align)
+	assert.Equal(t, AlignBottomCenter, sub.Alignment(sub.Cues[0]))
+	assert.Equal(t, AlignTopCenter, sub.Cues[1].Align)
+
+	data := []byte(`<tt xmlns="http://www.w3.org/ns/ttml" xmlns:tts="http://www.w3.org/ns/ttml#styling">
+  <head><layout>
+    <region xml:id="low" tts:origin="10% 70%" tts:extent="80% 20%"/>
+    <region xml:id="high" tts:origin="10% 5%" tts:extent="80% 20%"/>
+    <region xml:id="full" tts:origin="10% 10%" tts:extent="80% 80%"/>
+  </layout></head>
+  <body><div>
+    <p begin="1s" end="2s" region="low">Low</p>
+    <p begin="3s" end="4s" region="high">High</p>
+    <p begin="5s" end="6s" region="full">Full</p>
+  </div></body>
+</tt>`)
+	sub, err = ParseTTML(data)
+	assert.Nil(t, err)
+	assert.Equal(t, []int{0, AlignTopCenter, 0}, []int{sub.Cues[0].Align, sub.Cues[1].Align, sub.Cues[2].Align})
+}
+
+func TestParseVTTShouldNotTakeEscapedTextForTags(t *testing.T) {
+	sub, err := ParseVTT([]byte("WEBVTT\n\n00:00:01.000 --> 00:00:02.000\n<i>1</i> &lt;span&gt; &amp; &lt;b&gt;2\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"<i>1</i> &lt;span&gt; & &lt;b&gt;2"}, sub.Cues[0].Lines)
+
+	written := map[Format]string{}
+	for _, f := range []Format{VTT, SRT, ASS, TTML, SBV} {
+		buf := new(bytes.Buffer)
+		assert.Nil(t, Write(buf, sub, f, Options{}))
+		written[f] = buf.String()
+	}
+	assert.Contains(t, written[VTT], "<i>1</i> &lt;span&gt; &amp; &lt;b&gt;2\n")
+	assert.Contains(t, written[SRT], "<i>1</i> &lt;span&gt; & &lt;b&gt;2\n")
+	assert.Contains(t, written[ASS], `{\i1}1{\i0} <span> & <b>2`)
+	assert.Contains(t, written[TTML], "&lt;span&gt; &amp; &lt;b&gt;2")
+	assert.Contains(t, written[SBV], "1 <span> & <b>2\n")
+}
+
+const typesetASS = `[Script Info]
+; Script generated by Aegisub
+Title: Typeset
+ScriptType: v4.00+
+PlayResX: 1920
+PlayResY: 1080
+
+[Aegisub Project Garbage]
+Video File: ep01.mkv
+
+[V4+ Styles]
+Format: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, OutlineColour, BackColour, Bold, Italic, Underline, StrikeOut, ScaleX, ScaleY, Spacing, Angle, BorderStyle, Outline, Shadow, Alignment, MarginL, MarginR, MarginV, Encoding
+Style: Default,Open Sans,72,&H00FFFFFF,&H000000FF,&H00101010,&H80000000,-1,0,0,0,100,100,0,0,1,3.5,1.5,2,120,120,60,1
+Style: Sign,Arial,48,&H0032E6FF,&H000000FF,&H00000000,&H00000000,0,0,0,0,100,100,0,0,1,0,0,8,10,10,10,1
+
+[Events]
+Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text
+Comment: 0,0:00:00.00,0:00:05.00,Default,,0,0,0,,Typeset by someone
+Dialogue: 0,0:00:01.00,0:00:03.50,Default,Hero,0,0,0,,{\k20}Ka{\k30}ra{\i1}oke{\i0}\Nsecond line
+Dialogue: 5,0:00:02.00,0:00:06.00,Sign,,0,0,0,,{\an8\pos(960,120)\fad(200,300)\blur2}WELCOME TO TOKYO
+Dialogue: 4,0:00:02.00,0:00:06.00,Sign,,0,0,0,,{\p1\pos(100,100)\c&H000000&}m 0 0 l 100 0 100 100 0 100{\p0}
+Dialogue: 0,0:00:07.00,0:00:09.00,Default,,0,0,0,Scroll up;10;20;,{\fad(100,100)}Next time
+`
+
+func TestASSShouldRoundTripTypesetSubtitles(t *testing.T) {
+	sub, err := ParseASS([]byte(typesetASS))
+	assert.Nil(t, err)
+	if !assert.Equal(t, 4, len(sub.Cues)) {
+		return
+	}
+	assert.Equal(t, []string{"Kara<i>oke</i>", "second line"}, sub.Cues[0].Lines)
+	assert.Equal(t, "Hero", sub.Cues[0].Name)
+	assert.Equal(t, `\pos(960,120)\fad(200,300)\blur2`, sub.Cues[1].Overrides)
+	assert.Equal(t, 5, sub.Cues[1].Layer)
+	assert.Equal(t, AlignTopCenter, sub.Cues[1].Align)
+	assert.True(t, sub.Cues[2].Drawing)
+	assert.Nil(t, sub.Cues[2].Lines)
+	assert.Equal(t, "&H80000000", sub.Style("Default").Fields["BackColour"])
+
+	buf := new(bytes.Buffer)
+	assert.Nil(t, WriteASS(buf, sub))
+	assert.Equal(t, typesetASS, buf.String())
+	crlf := strings.Replace(typesetASS, "\n", "\r\n", -1)
+	windows, _ := ParseASS([]byte(crlf))
+	buf.Reset()
+	assert.Nil(t, WriteASS(buf, windows))
+	assert.Equal(t, crlf, buf.String())
+
+	// SSA keeps the fields and the overrides of the events
+	buf.Reset()
+	assert.Nil(t, WriteSSA(buf, windows))
+	ssa, err := ParseASS(buf.Bytes())
+	assert.Nil(t, err)
+	assert.Equal(t, `\pos(960,120)\fad(200,300)\blur2`, ssa.Cues[1].Overrides)
+	assert.Equal(t, "Hero", ssa.Cues[0].Name)
+	assert.Equal(t, "3.5", ssa.Style("Default").Fields["Outline"])
+
+	// Drawings are not text
+	buf.Reset()
+	assert.Nil(t, WriteSRT(buf, sub))
+	assert.Equal(t, 3, strings.Count(buf.String(), "-->"))
+	assert.NotContains(t, buf.String(), "m 0 0")
+
+	// Only the changes are written
+	sub.Shift(time.Second, nil)
+	sub.Cues[1].Lines = []string{"WELCOME TO OSAKA"}
+	sub.Cues[0].MapText(strings.ToUpper)
+	sub.Style("Default").Bold = false
+	buf.Reset()
+	assert.Nil(t, WriteASS(buf, sub))
+	expected := strings.NewReplacer(
+		"Style: Default,Open Sans,72,&H00FFFFFF,&H000000FF,&H00101010,&H80000000,-1,", "Style: Default,Open Sans,72,&H00FFFFFF,&H000000FF,&H00101010,&H80000000,0,",
+		"Comment: 0,0:00:00.00,0:00:05.00,", "Comment: 0,0:00:01.00,0:00:06.00,",
+		`0:00:01.00,0:00:03.50,Default,Hero,0,0,0,,{\k20}Ka{\k30}ra{\i1}oke{\i0}\Nsecond line`, `0:00:02.00,0:00:04.50,Default,Hero,0,0,0,,{\k20}KA{\k30}RA{\i1}OKE{\i0}\NSECOND LINE`,
+		`0:00:02.00,0:00:06.00,Sign,,0,0,0,,{\an8\pos(960,120)\fad(200,300)\blur2}WELCOME TO TOKYO`, `0:00:03.00,0:00:07.00,Sign,,0,0,0,,{\an8\pos(960,120)\fad(200,300)\blur2}WELCOME TO OSAKA`,
+		`0:00:02.00,0:00:06.00,Sign,,0,0,0,,{\p1`, `0:00:03.00,0:00:07.00,Sign,,0,0,0,,{\p1`,
+		"0:00:07.00,0:00:09.00,", "0:00:08.00,0:00:10.00,",
+	).Replace(typesetASS)
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestPatchShouldOnlyRewriteTheTimestampsOfMovedCues(t *testing.T) {
+	sub, err := ParseASS([]byte(typesetASS))
+	assert.Nil(t, err)
+	sub.Shift(time.Second, nil)
+	retimed, err := Patch([]byte(typesetASS), sub, ASS, Options{})
+	assert.Nil(t, err)
+	expected := strings.NewReplacer(
+		"0:00:00.00,0:00:05.00", "0:00:01.00,0:00:06.00",
+		"0:00:01.00,0:00:03.50", "0:00:02.00,0:00:04.50",
+		"0:00:02.00,0:00:06.00", "0:00:03.00,0:00:07.00",
+		"0:00:07.00,0:00:09.00", "0:00:08.00,0:00:10.00",
+	).Replace(typesetASS)
+	assert.Equal(t, expected, string(retimed))
+
+	srt := "\xEF\xBB\xBF1\r\n00:00:01.000 --> 00:00:02,000 X1:10\r\n<ruby>漢<rt>kan</rt></ruby> {\\an8}\r\n\r\n2\r\n00:00:03,000 --> 00:00:04,000\r\n<font face=\"Arial\">Text</font>\r\n"
+	sub, err = ParseSRT([]byte(srt))
+	assert.Nil(t, err)
+	sub.Shift(-500*time.Millisecond, func(i int, c *Cue) bool { return i == 1 })
+	retimed, err = Patch([]byte(srt), sub, SRT, Options{})
+	assert.Nil(t, err)
+	assert.Equal(t, strings.Replace(srt, "00:00:03,000 --> 00:00:04,000", "00:00:02,500 --> 00:00:03,500", 1), string(retimed))
+
+	ttml := `<tt xmlns="http://www.w3.org/ns/ttml"><body><div><p begin="1s" dur="2s" xml:id="a">One</p><p begin="00:00:04.000" end="00:00:05.000">Two</p></div></body></tt>`
+	sub, err = ParseTTML([]byte(ttml))
+	assert.Nil(t, err)
+	sub.Shift(time.Second, nil)
+	retimed, err = Patch([]byte(ttml), sub, TTML, Options{})
+	assert.Nil(t, err)
+	assert.Equal(t, `<tt xmlns="http://www.w3.org/ns/ttml"><body><div><p begin="00:00:02.000" dur="00:00:02.000" xml:id="a">One</p><p begin="00:00:05.000" end="00:00:06.000">Two</p></div></body></tt>`, string(retimed))
+}
+
+func TestPatchShouldTakeOutRemovedCuesAndRewriteEditedText(t *testing.T) {
+	srt := "1\r\n00:00:01,000 --> 00:00:02,000\r\nSubtitles by someone\r\n\r\n2\r\n00:00:03,000 --> 00:00:04,000\r\n<ruby>漢<rt>kan</rt></ruby>\r\n\r\n" +
+		"3\r\n00:00:05,000 --> 00:00:06,000\r\n{\\an8}Keep\r\nwww.ads.com\r\n\r\n4\r\n00:00:07,000 --> 00:00:08,000\r\nLast ad\r\n"
+	sub, err := ParseSRT([]byte(srt))
+	assert.Nil(t, err)
+	sub.Cues[2].Lines = sub.Cues[2].Lines[:1]
+	sub.Cues = sub.Cues[1:3]
+	sub.Renumber()
+	patched, err := Patch([]byte(srt), sub, SRT, Options{})
+	assert.Nil(t, err)
+	assert.Equal(t, "1\r\n00:00:03,000 --> 00:00:04,000\r\n<ruby>漢<rt>kan</rt></ruby>\r\n\r\n2\r\n00:00:05,000 --> 00:00:06,000\r\n{\\an8}Keep\r\n", string(patched))
+
+	mdvd := "{1}{1}25\n{25}{50}{y:i}Hello|www.ads.com\n{100}{125}{f:Arial}Bye\n"
+	sub, err = ParseMicroDVD([]byte(mdvd), 0)
+	assert.Nil(t, err)
+	sub.Cues[0].Lines = sub.Cues[0].Lines[:1]
+	sub.Cues = sub.Cues[:1]
+	patched, err = Patch([]byte(mdvd), sub, MicroDVD, Options{})
+	assert.Nil(t, err)
+	assert.Equal(t, "{1}{1}25\n{25}{50}{y:i}Hello\n", string(patched))
+
+	ttml := "<tt xmlns=\"http://www.w3.org/ns/ttml\"><body><div>\n  <p begin=\"1s\" end=\"2s\">Ad</p>\n  <p begin=\"3s\" end=\"4s\" xml:id=\"b\">Two<br/>www.ads.com</p>\n</div></body></tt>"
+	sub, err = ParseTTML([]byte(ttml))
+	assert.Nil(t, err)
+	sub.Cues[1].Lines = sub.Cues[1].Lines[:1]
+	sub.Cues = sub.Cues[1:]
+	patched, err = Patch([]byte(ttml), sub, TTML, Options{})
+	assert.Nil(t, err)
+	assert.Equal(t, "<tt xmlns=\"http://www.w3.org/ns/ttml\"><body><div>\n  <p begin=\"3s\" end=\"4s\" xml:id=\"b\">Two</p>\n</div></body></tt>", string(patched))
+
+	// Added cues can't be patched
+	sub.Cues = append(sub.Cues, &Cue{Index: 2, Start: time.Minute, End: time.Minute + time.Second, Lines: []string{"New"}})
+	_, err = Patch([]byte(ttml), sub, TTML, Options{})
+	assert.NotNil(t, err)
+}
+
+func TestPatchShouldKeepTheMarkupOfMappedText(t *testing.T) {
+	ttml := `<tt xmlns="http://www.w3.org/ns/ttml"><body><div><p begin="1s" end="2s"><span tts:color="red">red</span> &amp; blue<br/>sky</p></div></body></tt>`
+	sub, err := ParseTTML([]byte(ttml))
+	assert.Nil(t, err)
+	assert.True(t, sub.Cues[0].MapText(strings.ToUpper))
+	patched, err := Patch([]byte(ttml), sub, TTML, Options{})
+	assert.Nil(t, err)
+	assert.Equal(t, `<tt xmlns="http://www.w3.org/ns/ttml"><body><div><p begin="1s" end="2s"><span tts:color="red">RED</span> &amp; BLUE<br/>SKY</p></div></body></tt>`, string(patched))
+
+	vtt := "WEBVTT\n\nintro\n00:01.000 --> 00:02.000 line:0\n<v Bob>Tom &amp; <c.yellow>Jerry</c>\n"
+	sub, err = ParseVTT([]byte(vtt))
+	assert.Nil(t, err)
+	sub.Cues[0].MapText(strings.ToUpper)
+	patched, err = Patch([]byte(vtt), sub, VTT, Options{})
+	assert.Nil(t, err)
+	assert.Equal(t, "WEBVTT\n\nintro\n00:01.000 --> 00:02.000 line:0\n<v Bob>TOM &amp; <c.yellow>JERRY</c>\n", string(patched))
+
+	// Edited cues are written again
+	sub.Cues[0].Lines = []string{"New"}
+	patched, err = Patch([]byte(vtt), sub, VTT, Options{})
+	assert.Nil(t, err)
+	assert.Equal(t, "WEBVTT\n\nintro\n00:01.000 --> 00:02.000 line:0\nNew\n", string(patched))
+}
+
+func TestWriteSRTShouldOnlyWriteTheAlignmentOfCuesWithText(t *testing.T) {
+	s := &Subtitle{Cues: []*Cue{
+		{Start: time.Second, End: 2 * time.Second, Align: AlignTopCenter},
+		{Start: 3 * time.Second, End: 4 * time.Second, Align: AlignTopCenter, Lines: []string{"Top", "Second"}},
+	}}
+	buf := new(bytes.Buffer)
+	assert.Nil(t, WriteSRT(buf, s))
+	assert.Equal(t, "1\n00:00:01,000 --> 00:00:02,000\n\n2\n00:00:03,000 --> 00:00:04,000\n{\\an8}Top\nSecond\n", buf.String())
+}
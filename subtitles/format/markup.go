@@ -0,0 +1,76 @@
+package format
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// tagRegexp matches the known tags only, so that a < b in a dialogue is not taken for a tag
+	tagRegexp = regexp.MustCompile(`(?i)<(/?)(i|b|u|s|font|br)([\s/][^>]*)?>`)
+	// anyTagRegexp matches the tags of any name, like <span>, to remove them
+	anyTagRegexp   = regexp.MustCompile(`(?i)</?([a-z][a-z0-9]*)(?:[\s/][^<>]*)?>`)
+	fontColorRegex = regexp.MustCompile(`(?i)color\s*=\s*["']?([#a-z0-9]+)["']?`)
+	assTagRegexp   = regexp.MustCompile(`\{\\[^}]*\}`)
+)
+
+// Angle brackets of the text itself are kept escaped in the lines of the cues (&lt;b&gt;), not to be taken for tags
+var (
+	escapeBrackets   = strings.NewReplacer("<", "&lt;", ">", "&gt;")
+	unescapeBrackets = strings.NewReplacer("&lt;", "<", "&gt;", ">")
+)
+
+// inlineTags are the HTML like tags kept in the text of the cues
+var inlineTags = map[string]bool{"i": true, "b": true, "u": true, "s": true, "font": true}
+
+// CleanTags keeps the supported inline tags of a text (<i>, <b>, <u>, <s> and <font color>), removes the others,
+// and closes the tags left opened. <br> tags become new lines. Angle brackets of the dialogues (a < b) are kept
+func CleanTags(text string) string {
+	text = anyTagRegexp.ReplaceAllStringFunc(text, func(tag string) string {
+		if name := strings.ToLower(anyTagRegexp.FindStringSubmatch(tag)[1]); inlineTags[name] || name == "br" {
+			return tag
+		}
+		return ""
+	})
+	var open []string
+	cleaned := tagRegexp.ReplaceAllStringFunc(text, func(tag string) string {
+		m := tagRegexp.FindStringSubmatch(tag)
+		closing, name, attrs := m[1] == "/", strings.ToLower(m[2]), m[3]
+		if name == "br" {
+			return "\n"
+		}
+		if !inlineTags[name] {
+			return ""
+		}
+		if closing {
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i] == name {
+					open = append(open[:i], open[i+1:]...)
+					return "</" + name + ">"
+				}
+			}
+			// Closing a tag which was never opened
+			return ""
+		}
+		if name == "font" {
+			c := fontColorRegex.FindStringSubmatch(attrs)
+			if c == nil {
+				return ""
+			}
+			open = append(open, name)
+			return `<font color="` + strings.ToLower(c[1]) + `">`
+		}
+		open = append(open, name)
+		return "<" + name + ">"
+	})
+	for i := len(open) - 1; i >= 0; i-- {
+		cleaned += "</" + open[i] + ">"
+	}
+	return cleaned
+}
+
+// StripTags removes all HTML like tags and ASS override blocks from a text, and unescapes its angle brackets
+func StripTags(text string) string {
+	text = assTagRegexp.ReplaceAllString(text, "")
+	return unescapeBrackets.Replace(anyTagRegexp.ReplaceAllString(text, ""))
+}
@@ -0,0 +1,120 @@
+package format
+
+import "time"
+
+// Styles of the merged subtitles
+const (
+	PrimaryStyle   = "Primary"
+	SecondaryStyle = "Secondary"
+	// secondaryColor distinguishes the second language when the lines are stacked
+	secondaryColor = "#ffff00"
+)
+
+// Merge combines the subtitles of a video in two languages. The cues of the secondary subtitle are attached
+// to the primary cue they overlap the most, and take its timing, so that both languages are displayed together.
+// When stacked, the secondary lines are added under the primary ones, in color, for formats like SRT.
+// Otherwise the languages are separate cues with styles: the primary one at the bottom, the secondary one at the top
+func Merge(primary, secondary *Subtitle, stacked bool) *Subtitle {
+	primaryCues, secondaryCues := styledCopy(primary), styledCopy(secondary)
+
+	attached := make([][]*Cue, len(primaryCues))
+	var alone []*Cue
+	for _, c := range secondaryCues {
+		best, bestOverlap := -1, time.Duration(0)
+		for i, p := range primaryCues {
+			if p.Start >= c.End {
+				break
+			}
+			start, end := p.Start, p.End
+			if c.Start > start {
+				start = c.Start
+			}
+			if c.End < end {
+				end = c.End
+			}
+			if end-start > bestOverlap {
+				best, bestOverlap = i, end-start
+			}
+		}
+		if best < 0 {
+			alone = append(alone, c)
+			continue
+		}
+		attached[best] = append(attached[best], c)
+	}
+
+	merged := &Subtitle{}
+	if !stacked {
+		merged.Styles = []*Style{
+			{Name: PrimaryStyle, Alignment: AlignBottomCenter},
+			{Name: SecondaryStyle, Color: secondaryColor, Alignment: AlignTopCenter},
+		}
+	}
+	secondaryCue := func(start, end time.Duration, lines []string) *Cue {
+		if stacked {
+			return &Cue{Start: start, End: end, Lines: colored(lines)}
+		}
+		return &Cue{Start: start, End: end, Style: SecondaryStyle, Lines: lines}
+	}
+
+	for i, p := range primaryCues {
+		var lines []string
+		for _, c := range attached[i] {
+			lines = append(lines, c.Lines...)
+		}
+		if stacked {
+			p.Lines = append(p.Lines, colored(lines)...)
+			merged.Cues = append(merged.Cues, p)
+			continue
+		}
+		p.Style = PrimaryStyle
+		if p.Align == AlignBottomCenter {
+			p.Align = 0
+		}
+		merged.Cues = append(merged.Cues, p)
+		if len(lines) > 0 {
+			merged.Cues = append(merged.Cues, secondaryCue(p.Start, p.End, lines))
+		}
+	}
+	for _, c := range alone {
+		merged.Cues = append(merged.Cues, secondaryCue(c.Start, c.End, c.Lines))
+	}
+	merged.Sort()
+	merged.Renumber()
+	return merged
+}
+
+// styledCopy copies the cues with a text of a subtitle sorted by time, with the styling and the alignment of their style
+// set on the cues
+func styledCopy(s *Subtitle) []*Cue {
+	var cues []*Cue
+	for _, c := range s.textCues() {
+		cues = append(cues, &Cue{Start: c.Start, End: c.End, Align: s.Alignment(c), Lines: append([]string{}, s.StyledLines(c)...)})
+	}
+	sorted := &Subtitle{Cues: cues}
+	sorted.Sort()
+	return sorted.Cues
+}
+
+// colored gives the lines in the color of the secondary language
+func colored(lines []string) []string {
+	result := make([]string, len(lines))
+	for i, l := range lines {
+		result[i] = `<font color="` + secondaryColor + `">` + l + "</font>"
+	}
+	return result
+}
+
+// MergeFiles merges the subtitle files of a video in two languages into the output file.
+// The lines are stacked, unless the format has named styles (ASS/SSA)
+func MergeFiles(primary, secondary, output string, to Format, opts Options) error {
+	p, _, err := ReadFile(primary, opts)
+	if err != nil {
+		return err
+	}
+	s, _, err := ReadFile(secondary, opts)
+	if err != nil {
+		return err
+	}
+	return WriteFile(output, Merge(p, s, to != ASS && to != SSA), to, opts)
+}
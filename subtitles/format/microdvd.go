@@ -0,0 +1,156 @@
+package format
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	microDVDRegexp      = regexp.MustCompile(`^\{(\d+)\}\{(\d*)\}(.*)$`)
+	microDVDStyleRegexp = regexp.MustCompile(`(?i)\{([yc]):([^}]*)\}`)
+	microDVDCodeRegexp  = regexp.MustCompile(`\{[A-Za-z]:[^}]*\}`)
+)
+
+// ParseMicroDVD parses the content of a MicroDVD subtitle, where times are frame numbers.
+// The frame rate is the given one, or the one declared by the file ({1}{1}23.976), or DefaultFrameRate
+func ParseMicroDVD(data []byte, frameRate float64) (*Subtitle, error) {
+	sub := &Subtitle{Metadata: map[string]string{}}
+	fps := frameRate
+	for i, line := range splitLines(data) {
+		m := microDVDRegexp.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		start, _ := strconv.Atoi(m[1])
+		end, _ := strconv.Atoi(m[2])
+		// The first line may declare the frame rate
+		if i == 0 && start <= 1 && end <= 1 {
+			if declared, err := strconv.ParseFloat(strings.TrimSpace(m[3]), 64); err == nil && declared > 0 {
+				sub.Metadata["framerate"] = m[3]
+				if fps == 0 {
+					fps = declared
+				}
+				continue
+			}
+		}
+		if fps == 0 {
+			fps = DefaultFrameRate
+		}
+		if m[2] == "" {
+			end = start
+		}
+
+		cue := &Cue{Index: len(sub.Cues) + 1, Start: framesToDuration(start, fps), End: framesToDuration(end, fps), pos: i + 1, end: i + 1}
+		// Upper case codes ({Y:i}) apply to all the lines of the cue, lower case ones to their line only
+		var global string
+		text := microDVDStyleRegexp.ReplaceAllStringFunc(m[3], func(code string) string {
+			if code[1] == 'Y' || code[1] == 'C' {
+				global += code
+				return ""
+			}
+			return code
+		})
+		for _, l := range strings.Split(text, "|") {
+			cue.Lines = append(cue.Lines, microDVDLine(global+l))
+		}
+		cue.Lines = cleanLines(cue.Lines)
+		cue.src = newSource([]string{m[3]}, cue)
+		sub.Cues = append(sub.Cues, cue)
+	}
+	if len(sub.Cues) == 0 {
+		return nil, errors.New("No MicroDVD cue found")
+	}
+	return sub, nil
+}
+
+// microDVDLine converts the style codes of a line to inline tags
+func microDVDLine(line string) string {
+	var open, close string
+	for _, m := range microDVDStyleRegexp.FindAllStringSubmatch(line, -1) {
+		switch strings.ToLower(m[1]) {
+		case "y":
+			for _, s := range strings.Split(strings.ToLower(m[2]), ",") {
+				if s = strings.TrimSpace(s); s == "i" || s == "b" || s == "u" || s == "s" {
+					open, close = open+"<"+s+">", "</"+s+">"+close
+				}
+			}
+		case "c":
+			// Colors are $BBGGRR
+			if n, err := strconv.ParseUint(strings.TrimPrefix(m[2], "$"), 16, 32); err == nil {
+				open, close = open+fmt.Sprintf(`<font color="#%02x%02x%02x">`, n&0xff, (n>>8)&0xff, (n>>16)&0xff), "</font>"+close
+			}
+		}
+	}
+	return open + microDVDCodeRegexp.ReplaceAllString(line, "") + close
+}
+
+// WriteMicroDVD writes the subtitle as MicroDVD, with the frame rate declared on the first line.
+// Without frame rate, the one declared by the read file is kept, or DefaultFrameRate is used
+func WriteMicroDVD(w io.Writer, s *Subtitle, frameRate float64) error {
+	if frameRate == 0 {
+		frameRate, _ = strconv.ParseFloat(s.Metadata["framerate"], 64)
+	}
+	if frameRate <= 0 {
+		frameRate = DefaultFrameRate
+	}
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "{1}{1}%s\n", formatFloat(frameRate))
+	for _, c := range s.textCues() {
+		fmt.Fprintf(bw, "{%d}{%d}%s\n", durationToFrames(c.Start, frameRate), durationToFrames(c.End, frameRate), microDVDText(s, c))
+	}
+	return bw.Flush()
+}
+
+// microDVDText gives the text of a cue as written in MicroDVD, after its frames
+func microDVDText(s *Subtitle, c *Cue) string {
+	var lines []string
+	for _, l := range s.StyledLines(c) {
+		lines = append(lines, toMicroDVDLine(l))
+	}
+	return strings.Join(lines, "|")
+}
+
+// toMicroDVDLine converts a line to MicroDVD. Only the styles applied to the whole line can be kept
+func toMicroDVDLine(line string) string {
+	var codes []string
+	for {
+		m := tagRegexp.FindStringSubmatchIndex(line)
+		if m == nil || m[0] != 0 || line[m[2]:m[3]] == "/" {
+			break
+		}
+		name := strings.ToLower(line[m[4]:m[5]])
+		closing := "</" + name + ">"
+		if !strings.HasSuffix(strings.ToLower(line), closing) {
+			break
+		}
+		switch name {
+		case "i", "b", "u", "s":
+			codes = append(codes, "{y:"+name+"}")
+		case "font":
+			if c := fontColorRegex.FindStringSubmatch(line[m[6]:m[7]]); c != nil {
+				if n, err := strconv.ParseUint(strings.TrimPrefix(c[1], "#"), 16, 32); err == nil {
+					codes = append(codes, fmt.Sprintf("{c:$%02X%02X%02X}", n&0xff, (n>>8)&0xff, (n>>16)&0xff))
+				}
+			}
+		}
+		line = line[m[1] : len(line)-len(closing)]
+	}
+	return strings.Join(codes, "") + StripTags(line)
+}
+
+// framesToDuration converts a frame number to a duration
+func framesToDuration(frames int, fps float64) time.Duration {
+	return time.Duration(float64(frames) / fps * float64(time.Second))
+}
+
+// durationToFrames converts a duration to a frame number
+func durationToFrames(d time.Duration, fps float64) int {
+	return int(math.Round(d.Seconds() * fps))
+}
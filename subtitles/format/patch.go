@@ -0,0 +1,264 @@
+package format
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ttmlTimeRegexp matches the time attributes of TTML elements
+var ttmlTimeRegexp = regexp.MustCompile(`(\s(?:[\w-]+:)?(begin|end|dur)\s*=\s*["'])([^"']*)(["'])`)
+
+// Patch writes the changes of a subtitle to the content it was read from, and leaves the rest of the content as it was:
+// the timestamps of the cues are rewritten, the removed cues are taken out and only the text of the edited cues
+// is written again. Cues can't be added nor reordered. ASS and SSA subtitles are written back like WriteASS does
+func Patch(data []byte, s *Subtitle, f Format, opts Options) ([]byte, error) {
+	content := bytes.TrimPrefix(data, utf8BOM)
+	var patched []byte
+	var err error
+	switch f {
+	case ASS, SSA:
+		buf := new(bytes.Buffer)
+		err = Write(buf, s, f, opts)
+		patched = buf.Bytes()
+	case TTML:
+		patched, err = patchTTML(content, s)
+	default:
+		patched, err = patchLines(content, s, f, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(content) < len(data) {
+		patched = append(append([]byte{}, utf8BOM...), patched...)
+	}
+	return patched, nil
+}
+
+// patchedCues reads the cues of the content, and gives the cue of the subtitle each of them became, nil when removed
+func patchedCues(content []byte, s *Subtitle, f Format, opts Options) ([]*Cue, []*Cue, error) {
+	read, err := Parse(content, f, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	indexes := map[int]int{}
+	for i, c := range read.Cues {
+		indexes[c.pos] = i
+	}
+	patched := make([]*Cue, len(read.Cues))
+	last := -1
+	for _, c := range s.Cues {
+		i, ok := indexes[c.pos]
+		if !ok || c.pos == 0 || i <= last {
+			return nil, nil, errors.New("Cues were added or moved, the subtitle must be written again")
+		}
+		patched[i], last = c, i
+	}
+	return read.Cues, patched, nil
+}
+
+// patchedText gives the text of a cue read as r, as written in the file, and tells if it changed.
+// The text read is kept, with the changes of MapText, unless the cue was edited otherwise
+func patchedText(s *Subtitle, r, c *Cue, f Format) ([]string, bool) {
+	if c.src != nil && !c.src.edited(c) {
+		return c.src.text, strings.Join(c.src.text, "\n") != strings.Join(r.src.text, "\n")
+	}
+	if c.Align == r.Align && strings.Join(c.Lines, "\n") == strings.Join(r.Lines, "\n") {
+		return nil, false
+	}
+	return cueText(s, c, f), true
+}
+
+// patchLines patches the content of a text format, where each cue has its own lines
+func patchLines(content []byte, s *Subtitle, f Format, opts Options) ([]byte, error) {
+	read, patched, err := patchedCues(content, s, f, opts)
+	if err != nil {
+		return nil, err
+	}
+	// Frame based times are written with the given frame rate, or the declared one
+	frameRate := opts.FrameRate
+	if frameRate == 0 {
+		frameRate, _ = strconv.ParseFloat(strings.TrimSpace(s.Metadata["framerate"]), 64)
+	}
+	if frameRate <= 0 {
+		frameRate = DefaultFrameRate
+	}
+
+	lines := splitLinesKeepingEnds(string(content))
+	// From the last cue, so that the lines of the cues left to patch don't move
+	for i := len(read) - 1; i >= 0; i-- {
+		r, c := read[i], patched[i]
+		if c == nil {
+			start, end := cueBlock(lines, r, f)
+			lines = append(lines[:start], lines[end:]...)
+			continue
+		}
+
+		timing := r.pos - 1
+		line := strings.TrimRight(lines[timing], "\r\n")
+		newline := lines[timing][len(line):]
+		if newline == "" {
+			newline = "\n"
+		}
+		retimed, ok := line, true
+		if c.Start != r.Start || c.End != r.End {
+			retimed, ok = retimeLine(line, c, f, frameRate)
+		}
+		if !ok {
+			return nil, fmt.Errorf("Can't find the timing of cue %v in the subtitle", r.Index)
+		}
+		if text, changed := patchedText(s, r, c, f); changed && f == MicroDVD {
+			trimmed := strings.TrimLeft(retimed, " \t")
+			m := microDVDRegexp.FindStringSubmatchIndex(trimmed)
+			retimed = retimed[:len(retimed)-len(trimmed)] + trimmed[:m[6]] + strings.Join(text, "")
+		} else if changed {
+			var ended []string
+			for _, l := range text {
+				ended = append(ended, l+newline)
+			}
+			lines = append(lines[:r.pos], append(ended, lines[r.end:]...)...)
+		}
+		lines[timing] = retimed + lines[timing][len(line):]
+
+		// Removed cues change the numbers of the next ones
+		if f == SRT && timing > 0 && indexRegexp.MatchString(lines[timing-1]) {
+			index := strings.TrimRight(lines[timing-1], "\r\n")
+			if n, _ := strconv.Atoi(strings.TrimSpace(index)); n != c.Index {
+				lines[timing-1] = strconv.Itoa(c.Index) + lines[timing-1][len(index):]
+			}
+		}
+	}
+	return []byte(strings.Join(lines, "")), nil
+}
+
+// cueBlock gives the lines of a cue, with its identifier and the blank lines separating it from the next cue
+func cueBlock(lines []string, c *Cue, f Format) (int, int) {
+	start, end := c.pos-1, c.end
+	if f == MicroDVD {
+		return start, end
+	}
+	if start > 0 && (f == SRT && indexRegexp.MatchString(lines[start-1]) || f == VTT && strings.TrimSpace(lines[start-1]) != "") {
+		start--
+	}
+	for end < len(lines) && strings.TrimSpace(lines[end]) == "" {
+		end++
+	}
+	// The last cue takes the blank lines separating it from the previous one instead
+	if end == len(lines) {
+		for start > 0 && strings.TrimSpace(lines[start-1]) == "" {
+			start--
+		}
+	}
+	return start, end
+}
+
+// cueText gives the lines of the text of a cue as written in a format (see source)
+func cueText(s *Subtitle, c *Cue, f Format) []string {
+	switch f {
+	case MicroDVD:
+		return []string{microDVDText(s, c)}
+	case TTML:
+		return []string{ttmlContent(s, c)}
+	case SRT:
+		return srtText(s, c)
+	case VTT:
+		return vttText(s, c)
+	case SubViewer:
+		return []string{strings.Join(plainText(s, c), "[br]")}
+	}
+	return plainText(s, c)
+}
+
+// patchTTML patches the p elements of the cues of a TTML content
+func patchTTML(content []byte, s *Subtitle) ([]byte, error) {
+	read, patched, err := patchedCues(content, s, TTML, Options{})
+	if err != nil {
+		return nil, err
+	}
+	// From the last cue, so that the offsets of the elements left to patch don't move
+	for i := len(read) - 1; i >= 0; i-- {
+		r, c := read[i], patched[i]
+		start, end := r.pos-1, r.end
+		if c == nil {
+			for end < len(content) && isXMLSpace(content[end]) {
+				end++
+			}
+			content = append(append([]byte{}, content[:start]...), content[end:]...)
+			continue
+		}
+
+		element := string(content[start:end])
+		open := strings.IndexByte(element, '>') + 1
+		closing := strings.LastIndex(element, "</")
+		if text, changed := patchedText(s, r, c, TTML); changed && closing >= open {
+			element = element[:open] + strings.Join(text, "") + element[closing:]
+		}
+		if c.Start != r.Start || c.End != r.End {
+			element = ttmlTimeRegexp.ReplaceAllStringFunc(element[:open], func(attr string) string {
+				m := ttmlTimeRegexp.FindStringSubmatch(attr)
+				t := c.Start
+				switch m[2] {
+				case "end":
+					t = c.End
+				case "dur":
+					t = c.Duration()
+				}
+				return m[1] + FormatTimestamp(t, ".") + m[4]
+			}) + element[open:]
+		}
+		content = append(append(append([]byte{}, content[:start]...), element...), content[end:]...)
+	}
+	return content, nil
+}
+
+// retimeLine rewrites the times of a timing line with the ones of the cue
+func retimeLine(line string, c *Cue, f Format, frameRate float64) (string, bool) {
+	// replace gives the line with the start and end times formatted by format from the original ones
+	replace := func(timing *regexp.Regexp, text string, format func(original string, t time.Duration) string) (string, bool) {
+		m := timing.FindStringSubmatchIndex(text)
+		if m == nil {
+			return "", false
+		}
+		return text[:m[2]] + format(text[m[2]:m[3]], c.Start) + text[m[3]:m[4]] + format(text[m[4]:m[5]], c.End) + text[m[5]:], true
+	}
+	// timestamp keeps the timestamps left unchanged, and the separator of the others
+	timestamp := func(original string, t time.Duration) string {
+		if d, err := ParseTimestamp(original); err == nil && d == t {
+			return original
+		}
+		separator := ","
+		if strings.Contains(original, ".") {
+			separator = "."
+		}
+		return FormatTimestamp(t, separator)
+	}
+
+	switch f {
+	case SRT, VTT:
+		timing := timingRegexp
+		if f == VTT {
+			timing = vttTimingRegexp
+		}
+		return replace(timing, line, timestamp)
+	case SubViewer:
+		return replace(subViewerTimingRegexp, line, func(_ string, t time.Duration) string { return formatCentiseconds(t) })
+	case SBV:
+		return replace(sbvTimingRegexp, line, func(_ string, t time.Duration) string { return formatSBVTimestamp(t) })
+	case MicroDVD:
+		trimmed := strings.TrimLeft(line, " \t")
+		retimed, ok := replace(microDVDRegexp, trimmed, func(_ string, t time.Duration) string {
+			return strconv.Itoa(durationToFrames(t, frameRate))
+		})
+		return line[:len(line)-len(trimmed)] + retimed, ok
+	case ASS, SSA:
+		if c.ass == nil {
+			return "", false
+		}
+		return retimeASSLine(line, c.ass.format, c), true
+	}
+	return "", false
+}
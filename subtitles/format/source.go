@@ -0,0 +1,44 @@
+package format
+
+import (
+	"regexp"
+	"strings"
+)
+
+// sourceMarkupRegexp matches the markup of the text formats: tags, style codes, entities and SubViewer line breaks
+var sourceMarkupRegexp = regexp.MustCompile(`<[^>]*>|\{[^}]*\}|&#?\w+;|\[(?i:br)\]`)
+
+// source is the text of a cue in the file it was read from, with the markup the model can't hold
+type source struct {
+	text  []string // Lines of the text (the text after the frames in MicroDVD, the content of the p element in TTML)
+	lines []string // Lines of the cue read from the text
+	align int
+}
+
+// newSource keeps the text a cue was read from
+func newSource(text []string, c *Cue) *source {
+	return &source{text: append([]string(nil), text...), lines: append([]string(nil), c.Lines...), align: c.Align}
+}
+
+// edited tells if the text of the cue changed since it was read from the source
+func (src *source) edited(c *Cue) bool {
+	return c.Align != src.align || strings.Join(c.Lines, "\n") != strings.Join(src.lines, "\n")
+}
+
+// mapText applies f to the text between the markup, and tells if it changed
+func (src *source) mapText(f func(string) string) bool {
+	changed := false
+	for i, line := range src.text {
+		var b strings.Builder
+		pos := 0
+		for _, m := range sourceMarkupRegexp.FindAllStringIndex(line, -1) {
+			b.WriteString(f(line[pos:m[0]]) + line[m[0]:m[1]])
+			pos = m[1]
+		}
+		b.WriteString(f(line[pos:]))
+		if mapped := b.String(); mapped != line {
+			src.text[i], changed = mapped, true
+		}
+	}
+	return changed
+}
@@ -0,0 +1,247 @@
+package format
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// timingRegexp accepts the usual mistakes: missing hours, dots instead of commas, spaces and short arrows
+	timingRegexp = regexp.MustCompile(`^\s*((?:\d+\s*:\s*)?\d+\s*:\s*\d+(?:\s*[,.:]\s*\d+)?)\s*-{1,2}\s*>\s*((?:\d+\s*:\s*)?\d+\s*:\s*\d+(?:\s*[,.:]\s*\d+)?)`)
+	indexRegexp  = regexp.MustCompile(`^\s*\d+\s*$`)
+	// alignTagRegexp is the ASS alignment tag, often found in SubRip files
+	alignTagRegexp = regexp.MustCompile(`\{\\an([1-9])\}`)
+)
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// ReadSRT reads a SubRip subtitle.
+// It is forgiving with BOMs, CRLF, malformed timestamps, missing blank lines and stray HTML tags,
+// but fails when no cue at all can be found.
+func ReadSRT(r io.Reader) (*Subtitle, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSRT(data)
+}
+
+// ParseSRT parses the content of a SubRip subtitle. See ReadSRT
+func ParseSRT(data []byte) (*Subtitle, error) {
+	lines := splitLines(data)
+	sub := &Subtitle{}
+
+	var cue *Cue
+	flush := func() {
+		if cue != nil {
+			// Trailing blank lines are not part of the text
+			for len(cue.Lines) > 0 && strings.TrimSpace(cue.Lines[len(cue.Lines)-1]) == "" {
+				cue.Lines = cue.Lines[:len(cue.Lines)-1]
+			}
+			cue.Align, cue.Lines = extractAlignment(cue.Lines)
+			cue.Lines = cleanLines(cue.Lines)
+			cue.src = newSource(lines[cue.pos:cue.end], cue)
+			sub.Cues = append(sub.Cues, cue)
+		}
+		cue = nil
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if m := timingRegexp.FindStringSubmatch(line); m != nil {
+			start, errStart := ParseTimestamp(m[1])
+			end, errEnd := ParseTimestamp(m[2])
+			if errStart == nil && errEnd == nil {
+				// The index of the new cue may have been taken as text of the previous one (missing blank line)
+				index := 0
+				if cue != nil && len(cue.Lines) > 0 && indexRegexp.MatchString(cue.Lines[len(cue.Lines)-1]) {
+					index, _ = strconv.Atoi(strings.TrimSpace(cue.Lines[len(cue.Lines)-1]))
+					cue.Lines = cue.Lines[:len(cue.Lines)-1]
+					cue.end = i - 1
+				} else if cue == nil && i > 0 && indexRegexp.MatchString(lines[i-1]) {
+					index, _ = strconv.Atoi(strings.TrimSpace(lines[i-1]))
+				}
+				flush()
+				if end < start {
+					end = start
+				}
+				cue = &Cue{Index: index, Start: start, End: end, pos: i + 1, end: i + 1}
+				continue
+			}
+		}
+		if cue == nil {
+			// Index lines and garbage between cues
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			// A blank line ends the cue, unless the text continues without a new timing
+			if nextIsCueStart(lines, i+1) || i+1 == len(lines) {
+				flush()
+			} else {
+				cue.Lines = append(cue.Lines, "")
+			}
+			continue
+		}
+		cue.Lines = append(cue.Lines, line)
+		cue.end = i + 1
+	}
+	flush()
+
+	if len(sub.Cues) == 0 && len(bytes.TrimSpace(data)) > 0 {
+		return nil, errors.New("No SubRip cue found")
+	}
+	for i, c := range sub.Cues {
+		if c.Index == 0 {
+			c.Index = i + 1
+		}
+	}
+	return sub, nil
+}
+
+// nextIsCueStart tells if a new cue starts at line i, after optional blank lines
+func nextIsCueStart(lines []string, i int) bool {
+	for ; i < len(lines) && strings.TrimSpace(lines[i]) == ""; i++ {
+	}
+	if i >= len(lines) {
+		return true
+	}
+	if timingRegexp.MatchString(lines[i]) {
+		return true
+	}
+	return indexRegexp.MatchString(lines[i]) && i+1 < len(lines) && timingRegexp.MatchString(lines[i+1])
+}
+
+// cleanLines cleans the HTML tags of the text and removes blank lines inside the text
+func cleanLines(lines []string) []string {
+	text := CleanTags(strings.Join(lines, "\n"))
+	var cleaned []string
+	for _, l := range strings.Split(text, "\n") {
+		if l = strings.TrimRight(l, " \t"); strings.TrimSpace(l) != "" {
+			cleaned = append(cleaned, l)
+		}
+	}
+	return cleaned
+}
+
+// extractAlignment removes the {\anN} tags of the text and gives the alignment they set
+func extractAlignment(lines []string) (int, []string) {
+	align := 0
+	result := make([]string, len(lines))
+	for i, l := range lines {
+		result[i] = alignTagRegexp.ReplaceAllStringFunc(l, func(tag string) string {
+			align, _ = strconv.Atoi(alignTagRegexp.FindStringSubmatch(tag)[1])
+			return ""
+		})
+	}
+	return align, result
+}
+
+// splitLines removes the BOM and splits the content in lines, whatever the line endings
+func splitLines(data []byte) []string {
+	data = bytes.TrimPrefix(data, utf8BOM)
+	text := strings.Replace(string(data), "\r\n", "\n", -1)
+	text = strings.Replace(text, "\r", "\n", -1)
+	return strings.Split(text, "\n")
+}
+
+// splitLinesKeepingEnds splits the content in lines like splitLines, the lines keeping their line ending
+func splitLinesKeepingEnds(text string) []string {
+	var lines []string
+	start := 0
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '\r':
+			if i+1 < len(text) && text[i+1] == '\n' {
+				i++
+			}
+			fallthrough
+		case '\n':
+			lines = append(lines, text[start:i+1])
+			start = i + 1
+		}
+	}
+	return append(lines, text[start:])
+}
+
+// WriteSRT writes the subtitle as SubRip. Cues are numbered from 1
+func WriteSRT(w io.Writer, s *Subtitle) error {
+	bw := bufio.NewWriter(w)
+	for i, c := range s.textCues() {
+		if i > 0 {
+			fmt.Fprint(bw, "\n")
+		}
+		fmt.Fprintf(bw, "%d\n%s --> %s\n", i+1, FormatTimestamp(c.Start, ","), FormatTimestamp(c.End, ","))
+		for _, l := range srtText(s, c) {
+			fmt.Fprintf(bw, "%s\n", l)
+		}
+	}
+	return bw.Flush()
+}
+
+// srtText gives the lines of a cue in SubRip. Most players understand the ASS alignment tag, written on the first line
+func srtText(s *Subtitle, c *Cue) []string {
+	lines := append([]string{}, s.StyledLines(c)...)
+	if align := s.Alignment(c); align != AlignBottomCenter && len(lines) > 0 {
+		lines[0] = fmt.Sprintf("{\\an%d}", align) + lines[0]
+	}
+	return lines
+}
+
+// ParseTimestamp parses timestamps like 01:02:03,456, 1:2:3.4, 02:03,456 or 01:02:03:456
+func ParseTimestamp(s string) (time.Duration, error) {
+	s = strings.Replace(strings.TrimSpace(s), " ", "", -1)
+	var fraction string
+	// The fraction is separated by a comma, a dot, or a colon when there are 4 parts
+	if i := strings.LastIndexAny(s, ",."); i >= 0 {
+		s, fraction = s[:i], s[i+1:]
+	} else if parts := strings.Split(s, ":"); len(parts) == 4 {
+		s, fraction = strings.Join(parts[:3], ":"), parts[3]
+	}
+
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("Invalid timestamp %v", s)
+	}
+	var d time.Duration
+	for _, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("Invalid timestamp %v", s)
+		}
+		d = d*60 + time.Duration(n)
+	}
+	d *= time.Second
+
+	if fraction != "" {
+		n, err := strconv.Atoi(fraction)
+		if err != nil {
+			return 0, fmt.Errorf("Invalid timestamp fraction %v", fraction)
+		}
+		// ,5 is half a second and ,500 too
+		for i := len(fraction); i < 3; i++ {
+			n *= 10
+		}
+		for i := len(fraction); i > 3; i-- {
+			n /= 10
+		}
+		d += time.Duration(n) * time.Millisecond
+	}
+	return d, nil
+}
+
+// FormatTimestamp formats a duration as HH:MM:SS<sep>mmm
+func FormatTimestamp(d time.Duration, sep string) string {
+	if d < 0 {
+		d = 0
+	}
+	ms := int64(d / time.Millisecond)
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", ms/3600000, ms/60000%60, ms/1000%60, sep, ms%1000)
+}
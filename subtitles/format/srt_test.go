@@ -0,0 +1,74 @@
+package format
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSRTShouldReadWellFormedFile(t *testing.T) {
+	sub, err := ParseSRT([]byte("1\n00:00:01,000 --> 00:00:02,500\nHello\nWorld\n\n2\n00:01:00,000 --> 00:01:01,000\n<i>Bye</i>\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(sub.Cues))
+	assert.Equal(t, 1, sub.Cues[0].Index)
+	assert.Equal(t, time.Second, sub.Cues[0].Start)
+	assert.Equal(t, 2500*time.Millisecond, sub.Cues[0].End)
+	assert.Equal(t, []string{"Hello", "World"}, sub.Cues[0].Lines)
+	assert.Equal(t, "<i>Bye</i>", sub.Cues[1].Text())
+	assert.Equal(t, "Bye", sub.Cues[1].PlainText())
+}
+
+func TestParseSRTShouldForgiveBOMAndCRLF(t *testing.T) {
+	sub, err := ParseSRT([]byte("\xEF\xBB\xBF1\r\n00:00:01,000 --> 00:00:02,000\r\nHello\r\n\r\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(sub.Cues))
+	assert.Equal(t, []string{"Hello"}, sub.Cues[0].Lines)
+}
+
+func TestParseSRTShouldForgiveMissingBlankLines(t *testing.T) {
+	sub, err := ParseSRT([]byte("1\n00:00:01,000 --> 00:00:02,000\nHello\n2\n00:00:03,000 --> 00:00:04,000\nWorld\n00:00:05,000 --> 00:00:06,000\nAgain"))
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(sub.Cues))
+	assert.Equal(t, []string{"Hello"}, sub.Cues[0].Lines)
+	assert.Equal(t, 2, sub.Cues[1].Index)
+	assert.Equal(t, []string{"World"}, sub.Cues[1].Lines)
+	assert.Equal(t, 3, sub.Cues[2].Index)
+}
+
+func TestParseSRTShouldForgiveMalformedTimestamps(t *testing.T) {
+	sub, err := ParseSRT([]byte("1\n0:0:1.5 -> 00:02,250\nHello\n\n2\n00:00:03:100 --> 00 : 00 : 04 , 000\nWorld\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(sub.Cues))
+	assert.Equal(t, 1500*time.Millisecond, sub.Cues[0].Start)
+	assert.Equal(t, 2250*time.Millisecond, sub.Cues[0].End)
+	assert.Equal(t, 3100*time.Millisecond, sub.Cues[1].Start)
+	assert.Equal(t, 4*time.Second, sub.Cues[1].End)
+}
+
+func TestParseSRTShouldCleanStrayTags(t *testing.T) {
+	sub, err := ParseSRT([]byte("1\n00:00:01,000 --> 00:00:02,000\n<span>Hello</span><br>World <B>bold\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Hello", "World <b>bold</b>"}, sub.Cues[0].Lines)
+}
+
+func TestCleanTagsShouldKeepAngleBracketsOfDialogues(t *testing.T) {
+	assert.Equal(t, "I said a < b and c > d", CleanTags("I said a < b and c > d"))
+	assert.Equal(t, []string{"<i>a < b</i>", "<i>c > d</i>"}, balanceTags([]string{"<i>a < b", "c > d</i>"}))
+	assert.Equal(t, "1 < 2 and 3 > 2", StripTags("<i>1 < 2</i> and 3 > 2"))
+}
+
+func TestParseSRTShouldFailWithoutCue(t *testing.T) {
+	_, err := ParseSRT([]byte("This is not a subtitle"))
+	assert.NotNil(t, err)
+}
+
+func TestWriteSRTShouldRoundTrip(t *testing.T) {
+	content := "1\n00:00:01,000 --> 00:00:02,500\nHello\n<i>World</i>\n\n2\n01:00:00,000 --> 01:00:01,001\nBye\n"
+	sub, err := ParseSRT([]byte(content))
+	assert.Nil(t, err)
+	buf := new(bytes.Buffer)
+	assert.Nil(t, WriteSRT(buf, sub))
+	assert.Equal(t, content, buf.String())
+}
@@ -0,0 +1,130 @@
+package format
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	subViewerTimingRegexp = regexp.MustCompile(`^\s*(\d+:\d+:\d+\.\d+)\s*,\s*(\d+:\d+:\d+\.\d+)\s*$`)
+	// sbvTimingRegexp is stricter than SubViewer's so that both can be told apart: milliseconds instead of centiseconds
+	sbvTimingRegexp = regexp.MustCompile(`^\s*(\d+:\d{2}:\d{2}\.\d{3})\s*,\s*(\d+:\d{2}:\d{2}\.\d{3})\s*$`)
+)
+
+// ParseSubViewer parses the content of a SubViewer 2.0 subtitle. The [INFORMATION] header is ignored
+func ParseSubViewer(data []byte) (*Subtitle, error) {
+	sub, err := parseCommaTimings(data, subViewerTimingRegexp, "[br]")
+	if err != nil {
+		return nil, errors.New("No SubViewer cue found")
+	}
+	return sub, nil
+}
+
+// ParseSBV parses the content of a YouTube SBV subtitle
+func ParseSBV(data []byte) (*Subtitle, error) {
+	sub, err := parseCommaTimings(data, sbvTimingRegexp, "")
+	if err != nil {
+		return nil, errors.New("No SBV cue found")
+	}
+	return sub, nil
+}
+
+// parseCommaTimings parses the formats where cues start with a "start,end" line and end with a blank line
+func parseCommaTimings(data []byte, timing *regexp.Regexp, lineBreak string) (*Subtitle, error) {
+	sub := &Subtitle{}
+	lines := splitLines(data)
+	var cue *Cue
+	flush := func() {
+		if cue != nil {
+			cue.Lines = cleanLines(cue.Lines)
+			cue.src = newSource(lines[cue.pos:cue.end], cue)
+			sub.Cues = append(sub.Cues, cue)
+		}
+		cue = nil
+	}
+	for i, line := range lines {
+		if m := timing.FindStringSubmatch(line); m != nil {
+			start, errStart := ParseTimestamp(m[1])
+			end, errEnd := ParseTimestamp(m[2])
+			if errStart == nil && errEnd == nil {
+				flush()
+				cue = &Cue{Index: len(sub.Cues) + 1, Start: start, End: end, pos: i + 1, end: i + 1}
+				continue
+			}
+		}
+		if strings.TrimSpace(line) == "" {
+			flush()
+			continue
+		}
+		if cue != nil {
+			if lineBreak != "" {
+				cue.Lines = append(cue.Lines, strings.Split(strings.Replace(line, strings.ToUpper(lineBreak), lineBreak, -1), lineBreak)...)
+			} else {
+				cue.Lines = append(cue.Lines, line)
+			}
+			cue.end = i + 1
+		}
+	}
+	flush()
+	if len(sub.Cues) == 0 {
+		return nil, errors.New("No cue found")
+	}
+	return sub, nil
+}
+
+// WriteSubViewer writes the subtitle as SubViewer 2.0
+func WriteSubViewer(w io.Writer, s *Subtitle) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprint(bw, "[INFORMATION]\n[TITLE]\n[AUTHOR]\n[SOURCE]\n[PRG]\n[FILEPATH]\n[DELAY]0\n[CD TRACK]0\n[COMMENT]\n[END INFORMATION]\n[SUBTITLE]\n[COLF]&HFFFFFF,[STYLE]no,[SIZE]18,[FONT]Arial\n")
+	for _, c := range s.textCues() {
+		fmt.Fprintf(bw, "%s,%s\n%s\n\n", formatCentiseconds(c.Start), formatCentiseconds(c.End), strings.Join(plainText(s, c), "[br]"))
+	}
+	return bw.Flush()
+}
+
+// WriteSBV writes the subtitle as YouTube SBV
+func WriteSBV(w io.Writer, s *Subtitle) error {
+	bw := bufio.NewWriter(w)
+	for i, c := range s.textCues() {
+		if i > 0 {
+			fmt.Fprint(bw, "\n")
+		}
+		fmt.Fprintf(bw, "%s,%s\n", formatSBVTimestamp(c.Start), formatSBVTimestamp(c.End))
+		for _, l := range plainText(s, c) {
+			fmt.Fprintf(bw, "%s\n", l)
+		}
+	}
+	return bw.Flush()
+}
+
+// plainText gives the lines of a cue without styling, for the formats without tags
+func plainText(s *Subtitle, c *Cue) []string {
+	var lines []string
+	for _, l := range s.StyledLines(c) {
+		lines = append(lines, StripTags(l))
+	}
+	return lines
+}
+
+// formatCentiseconds formats a duration as HH:MM:SS.cc
+func formatCentiseconds(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	cs := int64(d / (10 * time.Millisecond))
+	return fmt.Sprintf("%02d:%02d:%02d.%02d", cs/360000, cs/6000%60, cs/100%60, cs%100)
+}
+
+// formatSBVTimestamp formats a duration as H:MM:SS.mmm
+func formatSBVTimestamp(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	ms := int64(d / time.Millisecond)
+	return fmt.Sprintf("%d:%02d:%02d.%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
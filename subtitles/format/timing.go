@@ -0,0 +1,78 @@
+package format
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Shift moves the selected cues by the offset. All cues are selected when selected is nil.
+// Times are never negative: cues moved before the start of the video are clamped to 0
+func (s *Subtitle) Shift(offset time.Duration, selected func(i int, c *Cue) bool) {
+	s.Transform(1, offset, selected)
+}
+
+// Transform applies a linear transform to the times of the selected cues: t' = t * scale + offset.
+// All cues are selected when selected is nil
+func (s *Subtitle) Transform(scale float64, offset time.Duration, selected func(i int, c *Cue) bool) {
+	apply := func(t time.Duration) time.Duration {
+		t = time.Duration(float64(t)*scale) + offset
+		if t < 0 {
+			return 0
+		}
+		return t
+	}
+	for i, c := range s.Cues {
+		if selected == nil || selected(i, c) {
+			c.Start, c.End = apply(c.Start), apply(c.End)
+		}
+	}
+	// Comments of ASS/SSA subtitles follow when the whole subtitle moves
+	if s.ass != nil && selected == nil {
+		for _, comment := range s.ass.comments {
+			comment.cue.Start, comment.cue.End = apply(comment.cue.Start), apply(comment.cue.End)
+		}
+	}
+}
+
+// ParseOffset parses a signed offset, as a Go duration (+1.5s, -200ms, 1m2s) or a timestamp (-00:00:01,500)
+func ParseOffset(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	sign := time.Duration(1)
+	unsigned := s
+	if strings.HasPrefix(s, "+") || strings.HasPrefix(s, "-") {
+		if s[0] == '-' {
+			sign = -1
+		}
+		unsigned = s[1:]
+	}
+	if d, err := time.ParseDuration(unsigned); err == nil {
+		return sign * d, nil
+	}
+	if d, err := ParseTimestamp(unsigned); err == nil {
+		return sign * d, nil
+	}
+	return 0, fmt.Errorf("Invalid offset %v. Examples of valid offsets: +1.5s, -200ms, 00:01:02,500", s)
+}
+
+// TwoPointTransform gives the linear transform moving the time a to x and the time b to y, for Transform
+func TwoPointTransform(a, x, b, y time.Duration) (scale float64, offset time.Duration, err error) {
+	if a == b {
+		return 0, 0, fmt.Errorf("The two points must have different times, both are %v", FormatTimestamp(a, ","))
+	}
+	scale = float64(y-x) / float64(b-a)
+	if scale <= 0 {
+		return 0, 0, errors.New("The two points must keep their order")
+	}
+	return scale, x - time.Duration(float64(a)*scale), nil
+}
+
+// FrameRateScale gives the scale of Transform converting the times of a subtitle made for a video at
+// the frame rate from, to a video at the frame rate to (25 fps PAL to 23.976 fps for example)
+func FrameRateScale(from, to float64) (float64, error) {
+	if from <= 0 || to <= 0 {
+		return 0, fmt.Errorf("Frame rates must be positive, got %v and %v", from, to)
+	}
+	return from / to, nil
+}
@@ -0,0 +1,355 @@
+package format
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ttmlStyle is the styling of a TTML element, as inline tags
+type ttmlStyle struct {
+	italic, bold, underline bool
+	color                   string
+	align                   int
+}
+
+// ParseTTML parses the content of a TTML (DFXP) subtitle.
+// Styles are read from the head and from the p and span attributes, regions are only used for the alignment.
+func ParseTTML(data []byte) (*Subtitle, error) {
+	sub := &Subtitle{Metadata: map[string]string{}}
+	data = bytes.TrimPrefix(data, utf8BOM)
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	dec.Strict = false
+	dec.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) { return input, nil }
+
+	var (
+		frameRate = 30.0
+		tickRate  = 1.0
+		styles    = map[string]ttmlStyle{}
+		regions   = map[string]int{}
+		cue       *Cue
+		content   int // Offset of the content of the p element of the cue
+		line      strings.Builder
+		opened    [][2]string // Opening and closing tags of the opened p and spans
+	)
+	for {
+		offset := dec.InputOffset()
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Invalid TTML: %v", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "tt":
+				if v := ttmlAttr(t, "frameRate"); v != "" {
+					if f, err := strconv.ParseFloat(v, 64); err == nil && f > 0 {
+						frameRate = f
+					}
+				}
+				if v := ttmlAttr(t, "frameRateMultiplier"); v != "" {
+					var num, den float64
+					if n, _ := fmt.Sscanf(v, "%g %g", &num, &den); n == 2 && den > 0 {
+						frameRate = frameRate * num / den
+					}
+				}
+				if v := ttmlAttr(t, "tickRate"); v != "" {
+					if f, err := strconv.ParseFloat(v, 64); err == nil && f > 0 {
+						tickRate = f
+					}
+				}
+			case "style":
+				if cue == nil {
+					if id := ttmlAttr(t, "id"); id != "" {
+						styles[id] = ttmlStyleOf(t, styles)
+					}
+				}
+			case "region":
+				if id := ttmlAttr(t, "id"); id != "" {
+					regions[id] = ttmlRegionAlignment(t)
+				}
+			case "p":
+				begin, errBegin := parseTTMLTime(ttmlAttr(t, "begin"), frameRate, tickRate)
+				end, errEnd := parseTTMLTime(ttmlAttr(t, "end"), frameRate, tickRate)
+				if errBegin != nil {
+					continue
+				}
+				if dur := ttmlAttr(t, "dur"); errEnd != nil && dur != "" {
+					d, err := parseTTMLTime(dur, frameRate, tickRate)
+					if err != nil {
+						continue
+					}
+					end = begin + d
+				} else if errEnd != nil {
+					continue
+				}
+				style := ttmlStyleOf(t, styles)
+				cue = &Cue{Index: len(sub.Cues) + 1, Start: begin, End: end, Align: style.align, pos: int(offset) + 1}
+				content = int(dec.InputOffset())
+				if a, ok := regions[ttmlAttr(t, "region")]; ok && cue.Align == 0 {
+					cue.Align = a
+				}
+				line.Reset()
+				open, close := style.tags()
+				line.WriteString(open)
+				opened = [][2]string{{open, close}}
+			case "br":
+				if cue != nil {
+					line.WriteString(closeTags(opened))
+					cue.Lines = append(cue.Lines, line.String())
+					line.Reset()
+					for _, tags := range opened {
+						line.WriteString(tags[0])
+					}
+				}
+			case "span":
+				if cue != nil {
+					open, close := ttmlStyleOf(t, styles).tags()
+					line.WriteString(open)
+					opened = append(opened, [2]string{open, close})
+				}
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "span":
+				if cue != nil && len(opened) > 1 {
+					line.WriteString(opened[len(opened)-1][1])
+					opened = opened[:len(opened)-1]
+				}
+			case "p":
+				if cue != nil {
+					line.WriteString(closeTags(opened))
+					cue.Lines = cleanLines(append(cue.Lines, line.String()))
+					cue.end = int(dec.InputOffset())
+					cue.src = newSource([]string{string(data[content:offset])}, cue)
+					sub.Cues = append(sub.Cues, cue)
+				}
+				cue, opened = nil, nil
+			}
+		case xml.CharData:
+			if cue != nil {
+				// Whitespace in XML is not significant, only <br/> breaks lines
+				text := strings.Join(strings.Fields(string(t)), " ")
+				if text != "" && len(t) > 0 && isXMLSpace(t[0]) && line.Len() > 0 {
+					text = " " + text
+				}
+				if text != "" && isXMLSpace(t[len(t)-1]) {
+					text += " "
+				}
+				line.WriteString(escapeBrackets.Replace(text))
+			}
+		}
+	}
+	if len(sub.Cues) == 0 {
+		return nil, errors.New("No TTML cue found")
+	}
+	return sub, nil
+}
+
+// ttmlAttr gives the value of an attribute, whatever its namespace
+func ttmlAttr(e xml.StartElement, name string) string {
+	for _, a := range e.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// ttmlStyleOf gives the style of an element, from its style references and its own attributes
+func ttmlStyleOf(e xml.StartElement, styles map[string]ttmlStyle) ttmlStyle {
+	var s ttmlStyle
+	for _, ref := range strings.Fields(ttmlAttr(e, "style")) {
+		if r, ok := styles[ref]; ok {
+			s = r
+		}
+	}
+	for _, a := range e.Attr {
+		switch a.Name.Local {
+		case "fontStyle":
+			s.italic = a.Value == "italic" || a.Value == "oblique"
+		case "fontWeight":
+			s.bold = a.Value == "bold"
+		case "textDecoration":
+			s.underline = strings.Contains(a.Value, "underline")
+		case "color":
+			if strings.HasPrefix(a.Value, "#") && len(a.Value) >= 7 {
+				s.color = strings.ToLower(a.Value[:7])
+			} else if a.Value != "white" {
+				s.color = a.Value
+			}
+		case "textAlign":
+			switch a.Value {
+			case "left", "start":
+				s.align = AlignBottomLeft
+			case "right", "end":
+				s.align = AlignBottomRight
+			}
+		}
+	}
+	return s
+}
+
+// tags gives the opening and closing inline tags of the style
+func (s ttmlStyle) tags() (string, string) {
+	var open, close string
+	add := func(o, c string) {
+		open, close = open+o, c+close
+	}
+	if s.italic {
+		add("<i>", "</i>")
+	}
+	if s.bold {
+		add("<b>", "</b>")
+	}
+	if s.underline {
+		add("<u>", "</u>")
+	}
+	if s.color != "" {
+		add(`<font color="`+s.color+`">`, "</font>")
+	}
+	return open, close
+}
+
+// ttmlRegionAlignment guesses the alignment of a region from its displayAlign, then from its origin and extent
+// when it lies in the upper third of the screen
+func ttmlRegionAlignment(e xml.StartElement) int {
+	switch ttmlAttr(e, "displayAlign") {
+	case "before":
+		return AlignTopCenter
+	case "after":
+		return 0
+	}
+	var x, y, width, height float64
+	if n, _ := fmt.Sscanf(ttmlAttr(e, "origin"), "%g%% %g%%", &x, &y); n != 2 {
+		return 0
+	}
+	if n, _ := fmt.Sscanf(ttmlAttr(e, "extent"), "%g%% %g%%", &width, &height); n != 2 {
+		return 0
+	}
+	if y+height <= 100.0/3 {
+		return AlignTopCenter
+	}
+	return 0
+}
+
+// parseTTMLTime parses clock times (00:00:01.500, 00:00:01:12 with frames) and offset times (1.5s, 1500ms, 36f, 15000t)
+func parseTTMLTime(s string, frameRate, tickRate float64) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, errors.New("Empty TTML time")
+	}
+	units := []struct {
+		suffix string
+		unit   float64
+	}{{"ms", 0.001}, {"h", 3600}, {"m", 60}, {"s", 1}, {"f", 1 / frameRate}, {"t", 1 / tickRate}}
+	for _, u := range units {
+		if strings.HasSuffix(s, u.suffix) {
+			v, err := strconv.ParseFloat(strings.TrimSuffix(s, u.suffix), 64)
+			if err != nil {
+				return 0, fmt.Errorf("Invalid TTML time %v", s)
+			}
+			return time.Duration(v * u.unit * float64(time.Second)), nil
+		}
+	}
+	parts := strings.Split(s, ":")
+	if len(parts) == 4 {
+		// Frames
+		d, err := ParseTimestamp(strings.Join(parts[:3], ":"))
+		if err != nil {
+			return 0, err
+		}
+		frames, err := strconv.ParseFloat(parts[3], 64)
+		if err != nil {
+			return 0, fmt.Errorf("Invalid TTML time %v", s)
+		}
+		return d + time.Duration(frames/frameRate*float64(time.Second)), nil
+	}
+	return ParseTimestamp(s)
+}
+
+// WriteTTML writes the subtitle as TTML. Styles are inlined as spans, alignments become a bottom or a top region
+func WriteTTML(w io.Writer, s *Subtitle) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprint(bw, `<?xml version="1.0" encoding="UTF-8"?>`+"\n")
+	fmt.Fprint(bw, `<tt xmlns="http://www.w3.org/ns/ttml" xmlns:tts="http://www.w3.org/ns/ttml#styling">`+"\n")
+	fmt.Fprint(bw, "  <head>\n    <layout>\n")
+	fmt.Fprint(bw, `      <region xml:id="bottom" tts:origin="10% 10%" tts:extent="80% 80%" tts:displayAlign="after" tts:textAlign="center"/>`+"\n")
+	fmt.Fprint(bw, `      <region xml:id="top" tts:origin="10% 10%" tts:extent="80% 80%" tts:displayAlign="before" tts:textAlign="center"/>`+"\n")
+	fmt.Fprint(bw, "    </layout>\n  </head>\n  <body>\n    <div>\n")
+	for _, c := range s.textCues() {
+		region := "bottom"
+		if s.Alignment(c) >= AlignTopLeft {
+			region = "top"
+		}
+		fmt.Fprintf(bw, `      <p begin="%s" end="%s" region="%s">%s</p>`+"\n",
+			FormatTimestamp(c.Start, "."), FormatTimestamp(c.End, "."), region, ttmlContent(s, c))
+	}
+	fmt.Fprint(bw, "    </div>\n  </body>\n</tt>\n")
+	return bw.Flush()
+}
+
+// ttmlContent gives the content of the p element of a cue
+func ttmlContent(s *Subtitle, c *Cue) string {
+	var lines []string
+	for _, l := range s.StyledLines(c) {
+		lines = append(lines, ttmlText(l))
+	}
+	return strings.Join(lines, "<br/>")
+}
+
+// ttmlText converts the inline tags of a line to TTML spans
+func ttmlText(line string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range tagRegexp.FindAllStringSubmatchIndex(line, -1) {
+		b.WriteString(escapeText(line[last:loc[0]]))
+		last = loc[1]
+		if line[loc[2]:loc[3]] == "/" {
+			b.WriteString("</span>")
+			continue
+		}
+		switch strings.ToLower(line[loc[4]:loc[5]]) {
+		case "i":
+			b.WriteString(`<span tts:fontStyle="italic">`)
+		case "b":
+			b.WriteString(`<span tts:fontWeight="bold">`)
+		case "u":
+			b.WriteString(`<span tts:textDecoration="underline">`)
+		case "s":
+			b.WriteString(`<span tts:textDecoration="lineThrough">`)
+		case "font":
+			color := "white"
+			if m := fontColorRegex.FindStringSubmatch(line[loc[6]:loc[7]]); m != nil {
+				color = m[1]
+			}
+			b.WriteString(`<span tts:color="` + escapeText(color) + `">`)
+		default:
+			b.WriteString("<span>")
+		}
+	}
+	b.WriteString(escapeText(line[last:]))
+	return b.String()
+}
+
+// closeTags gives the closing tags of the opened elements, innermost first
+func closeTags(opened [][2]string) string {
+	var close string
+	for _, tags := range opened {
+		close = tags[1] + close
+	}
+	return close
+}
+
+func isXMLSpace(b byte) bool {
+	return b == ' ' || b == '\n' || b == '\r' || b == '\t'
+}
@@ -0,0 +1,186 @@
+package format
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	vttTimingRegexp = regexp.MustCompile(`^\s*((?:\d+:)?\d+:\d+[.,]\d+)\s*-->\s*((?:\d+:)?\d+:\d+[.,]\d+)(.*)$`)
+	// vttVoiceRegexp matches voice and class tags (<v Bob>, <c.yellow>) and inner timestamps (<00:00:01.000>)
+	vttSpanRegexp = regexp.MustCompile(`</?(?:v|c|lang|ruby|rt)(?:[.\s][^>]*)?>|<\d[\d:.]*>`)
+)
+
+// vttEntities decodes the entities of WebVTT but the angle brackets, which stay escaped in the lines of the cues
+var vttEntities = strings.NewReplacer("&amp;", "&", "&nbsp;", " ", "&lrm;", "‎", "&rlm;", "‏")
+
+// ParseVTT parses the content of a WebVTT subtitle.
+// NOTE, STYLE and REGION blocks are ignored, cue settings are only used for the alignment.
+func ParseVTT(data []byte) (*Subtitle, error) {
+	lines := splitLines(data)
+	if len(lines) == 0 || !strings.HasPrefix(strings.TrimSpace(lines[0]), "WEBVTT") {
+		return nil, errors.New("WebVTT files must start with WEBVTT")
+	}
+	sub := &Subtitle{Metadata: map[string]string{}}
+	if header := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(lines[0]), "WEBVTT")); header != "" {
+		sub.Metadata["header"] = header
+	}
+
+	var cue *Cue
+	inBlock := false // Inside a NOTE, STYLE or REGION block
+	for i, line := range lines[1:] {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			if cue != nil {
+				cue.Lines = vttLines(cue.Lines)
+				cue.src = newSource(lines[cue.pos:cue.end], cue)
+				sub.Cues = append(sub.Cues, cue)
+			}
+			cue, inBlock = nil, false
+			continue
+		}
+		if cue == nil && (strings.HasPrefix(trimmed, "NOTE") || trimmed == "STYLE" || trimmed == "REGION") {
+			inBlock = true
+		}
+		if inBlock {
+			continue
+		}
+		if m := vttTimingRegexp.FindStringSubmatch(line); m != nil && cue == nil {
+			start, err := ParseTimestamp(m[1])
+			if err != nil {
+				return nil, err
+			}
+			end, err := ParseTimestamp(m[2])
+			if err != nil {
+				return nil, err
+			}
+			cue = &Cue{Index: len(sub.Cues) + 1, Start: start, End: end, Align: vttAlignment(m[3]), pos: i + 2, end: i + 2}
+			continue
+		}
+		if cue != nil {
+			cue.Lines = append(cue.Lines, vttSpanRegexp.ReplaceAllString(line, ""))
+			cue.end = i + 2
+		}
+		// Otherwise it is the identifier of the next cue
+	}
+	if cue != nil {
+		cue.Lines = vttLines(cue.Lines)
+		cue.src = newSource(lines[cue.pos:cue.end], cue)
+		sub.Cues = append(sub.Cues, cue)
+	}
+	return sub, nil
+}
+
+// vttLines cleans the tags of the lines of a cue, then decodes their entities. An escaped &lt;b&gt; stays escaped, not to be a tag
+func vttLines(lines []string) []string {
+	lines = cleanLines(lines)
+	for i, l := range lines {
+		lines[i] = vttEntities.Replace(l)
+	}
+	return lines
+}
+
+// vttAlignment converts the line and align settings of a cue to a numeric keypad alignment
+func vttAlignment(settings string) int {
+	row, column := 0, 2
+	for _, s := range strings.Fields(settings) {
+		kv := strings.SplitN(s, ":", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "line":
+			value := strings.SplitN(kv[1], ",", 2)[0]
+			if strings.HasSuffix(value, "%") {
+				if p, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64); err == nil && p < 30 {
+					row = 6
+				} else if err == nil && p < 70 {
+					row = 3
+				}
+			} else if n, err := strconv.Atoi(value); err == nil && n >= 0 && n < 3 {
+				row = 6
+			}
+		case "align":
+			switch kv[1] {
+			case "start", "left":
+				column = 1
+			case "end", "right":
+				column = 3
+			}
+		}
+	}
+	if row == 0 && column == 2 {
+		return 0
+	}
+	return row + column
+}
+
+// WriteVTT writes the subtitle as WebVTT
+func WriteVTT(w io.Writer, s *Subtitle) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprint(bw, "WEBVTT")
+	if header := s.Metadata["header"]; header != "" {
+		fmt.Fprint(bw, " "+header)
+	}
+	fmt.Fprint(bw, "\n")
+	for _, c := range s.textCues() {
+		fmt.Fprintf(bw, "\n%s --> %s%s\n", FormatTimestamp(c.Start, "."), FormatTimestamp(c.End, "."), vttSettings(s.Alignment(c)))
+		for _, l := range vttText(s, c) {
+			fmt.Fprintf(bw, "%s\n", l)
+		}
+	}
+	return bw.Flush()
+}
+
+// vttText gives the lines of a cue as written in WebVTT
+func vttText(s *Subtitle, c *Cue) []string {
+	var lines []string
+	for _, l := range s.StyledLines(c) {
+		lines = append(lines, vttEscape(l))
+	}
+	return lines
+}
+
+// vttSettings gives the cue settings of an alignment
+func vttSettings(align int) string {
+	var settings string
+	switch {
+	case align >= AlignTopLeft:
+		settings += " line:0"
+	case align >= AlignMiddleLeft:
+		settings += " line:50%"
+	}
+	switch align % 3 {
+	case 1:
+		settings += " align:start"
+	case 0:
+		settings += " align:end"
+	}
+	return settings
+}
+
+// vttEscape escapes the text for WebVTT, keeping the <i>, <b> and <u> tags. Colors are not supported without CSS
+func vttEscape(line string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range tagRegexp.FindAllStringIndex(line, -1) {
+		b.WriteString(escapeText(line[last:loc[0]]))
+		tag := tagRegexp.FindStringSubmatch(line[loc[0]:loc[1]])
+		if name := strings.ToLower(tag[2]); name == "i" || name == "b" || name == "u" {
+			b.WriteString("<" + tag[1] + name + ">")
+		}
+		last = loc[1]
+	}
+	b.WriteString(escapeText(line[last:]))
+	return b.String()
+}
+
+// escapeText escapes the characters reserved by HTML like formats. Angle brackets already escaped are kept
+func escapeText(s string) string {
+	return strings.NewReplacer("&lt;", "&lt;", "&gt;", "&gt;", "&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
@@ -0,0 +1,107 @@
+package format
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// LineLength gives the number of characters displayed for a line, without its tags
+func LineLength(line string) int {
+	return utf8.RuneCountInString(StripTags(line))
+}
+
+// Wrap rewraps the lines of a cue so that they are not longer than max characters,
+// in as few lines as possible, balanced when there are two of them.
+// Dialogue lines (starting with a dash) are kept on their own lines. Inline tags are kept
+func Wrap(lines []string, max int) []string {
+	var wrapped []string
+	for _, block := range dialogueBlocks(lines) {
+		wrapped = append(wrapped, wrapBlock(block, max)...)
+	}
+	return wrapped
+}
+
+// dialogueBlocks joins the lines of a cue, except when they start a new speaker's line
+func dialogueBlocks(lines []string) []string {
+	var blocks []string
+	for _, l := range lines {
+		l = strings.TrimSpace(l)
+		if l == "" {
+			continue
+		}
+		if len(blocks) == 0 || strings.HasPrefix(StripTags(l), "-") {
+			blocks = append(blocks, l)
+			continue
+		}
+		blocks[len(blocks)-1] += " " + l
+	}
+	return blocks
+}
+
+// wrapBlock wraps a text. Two balanced lines are preferred when they fit, lines are filled greedily otherwise
+func wrapBlock(text string, max int) []string {
+	words := strings.Fields(text)
+	if LineLength(text) <= max || len(words) < 2 {
+		return []string{strings.Join(words, " ")}
+	}
+
+	// Balanced split in two lines
+	best, bestDiff := -1, 0
+	for i := 1; i < len(words); i++ {
+		first, second := LineLength(strings.Join(words[:i], " ")), LineLength(strings.Join(words[i:], " "))
+		if first > max || second > max {
+			continue
+		}
+		diff := first - second
+		if diff < 0 {
+			diff = -diff
+		}
+		if best < 0 || diff < bestDiff {
+			best, bestDiff = i, diff
+		}
+	}
+	if best > 0 {
+		return balanceTags([]string{strings.Join(words[:best], " "), strings.Join(words[best:], " ")})
+	}
+
+	var lines []string
+	var line []string
+	for _, w := range words {
+		if len(line) > 0 && LineLength(strings.Join(append(line, w), " ")) > max {
+			lines = append(lines, strings.Join(line, " "))
+			line = nil
+		}
+		line = append(line, w)
+	}
+	lines = append(lines, strings.Join(line, " "))
+	return balanceTags(lines)
+}
+
+// balanceTags closes the tags left opened at the end of a line, and opens them again on the next one
+func balanceTags(lines []string) []string {
+	var carried string
+	result := make([]string, len(lines))
+	for i, l := range lines {
+		l = carried + l
+		var open []string
+		for _, m := range tagRegexp.FindAllStringSubmatch(l, -1) {
+			name := strings.ToLower(m[2])
+			if !inlineTags[name] {
+				continue
+			}
+			if m[1] == "/" {
+				for j := len(open) - 1; j >= 0; j-- {
+					if strings.ToLower(tagRegexp.FindStringSubmatch(open[j])[2]) == name {
+						open = append(open[:j], open[j+1:]...)
+						break
+					}
+				}
+				continue
+			}
+			open = append(open, m[0])
+		}
+		carried = strings.Join(open, "")
+		result[i] = CleanTags(l)
+	}
+	return result
+}
@@ -0,0 +1,48 @@
+package subtitles
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/matcornic/subify/common/config"
+)
+
+// HearingImpaired is the preference for hearing impaired (SDH) subtitles
+type HearingImpaired string
+
+// Preferences for hearing impaired subtitles. Empty means no preference
+const (
+	HearingImpairedPrefer  HearingImpaired = "prefer"
+	HearingImpairedAvoid   HearingImpaired = "avoid"
+	HearingImpairedRequire HearingImpaired = "require"
+)
+
+// hearingImpairedBonus is added to the score of the subtitles matching the preference
+const hearingImpairedBonus = 0.2
+
+// ParseHearingImpaired reads a preference for hearing impaired subtitles
+func ParseHearingImpaired(preference string) (HearingImpaired, error) {
+	switch h := HearingImpaired(strings.ToLower(strings.TrimSpace(preference))); h {
+	case "", HearingImpairedPrefer, HearingImpairedAvoid, HearingImpairedRequire:
+		return h, nil
+	}
+	return "", fmt.Errorf("Hearing impaired preference %v is not available. Use prefer, avoid or require", preference)
+}
+
+// configuredHearingImpaired gives the preference of the configuration, parsed when the command starts
+func configuredHearingImpaired() HearingImpaired {
+	return HearingImpaired(config.HearingImpaired)
+}
+
+// Accepts tells if a subtitle can be used
+func (h HearingImpaired) Accepts(hearingImpaired bool) bool {
+	return h != HearingImpairedRequire || hearingImpaired
+}
+
+// Score gives the bonus of a subtitle, when it matches the preference
+func (h HearingImpaired) Score(hearingImpaired bool) float64 {
+	if (h == HearingImpairedPrefer && hearingImpaired) || (h == HearingImpairedAvoid && !hearingImpaired) {
+		return hearingImpairedBonus
+	}
+	return 0
+}
@@ -0,0 +1,51 @@
+package subtitles
+
+import (
+	"testing"
+
+	"github.com/oz/osdb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseHearingImpairedShouldRejectUnknownPreference(t *testing.T) {
+	hi, err := ParseHearingImpaired(" Prefer")
+	assert.Nil(t, err)
+	assert.Equal(t, HearingImpairedPrefer, hi)
+	_, err = ParseHearingImpaired("sometimes")
+	assert.NotNil(t, err)
+}
+
+func TestBestOSDBSubtitleShouldUseHearingImpairedPreference(t *testing.T) {
+	subs := func() osdb.Subtitles {
+		return osdb.Subtitles{
+			{IDSubtitleFile: "1", SubDownloadsCnt: "50", SubHearingImpaired: "0"},
+			{IDSubtitleFile: "2", SubDownloadsCnt: "900", SubHearingImpaired: "0"},
+			{IDSubtitleFile: "3", SubDownloadsCnt: "100", SubHearingImpaired: "1"},
+		}
+	}
+	assert.Equal(t, "2", bestOSDBSubtitle(subs(), "", false).IDSubtitleFile)
+	assert.Equal(t, "2", bestOSDBSubtitle(subs(), HearingImpairedAvoid, false).IDSubtitleFile)
+	assert.Equal(t, "3", bestOSDBSubtitle(subs(), HearingImpairedPrefer, false).IDSubtitleFile)
+	assert.Equal(t, "3", bestOSDBSubtitle(subs(), HearingImpairedRequire, false).IDSubtitleFile)
+	assert.Nil(t, bestOSDBSubtitle(subs()[:2], HearingImpairedRequire, false))
+
+	forced := append(subs(), osdb.Subtitle{IDSubtitleFile: "4", SubDownloadsCnt: "5000", SubForeignPartsOnly: "1"})
+	assert.Equal(t, "2", bestOSDBSubtitle(forced, "", false).IDSubtitleFile)
+	assert.Equal(t, "4", bestOSDBSubtitle(forced, "", true).IDSubtitleFile)
+	assert.Nil(t, bestOSDBSubtitle(subs(), "", true))
+}
+
+func TestRankSubDLSubtitlesShouldUseHearingImpairedPreference(t *testing.T) {
+	video := ParseRelease("Movie.2019.1080p.BluRay.x264-GROUP.mkv")
+	subs := []subdlSubtitle{
+		{ReleaseName: "Movie.2019.1080p.BluRay.x264-GROUP", HI: true},
+		{ReleaseName: "Movie.2019.1080p.BluRay.x264-GROUP"},
+		{ReleaseName: "Movie.2019.720p.WEB.x264-OTHER", HI: true},
+	}
+	ranked := rankSubDLSubtitles(subs, video, HearingImpairedAvoid)
+	assert.False(t, ranked[0].HI)
+	ranked = rankSubDLSubtitles(subs, video, HearingImpairedRequire)
+	if assert.Equal(t, 2, len(ranked)) {
+		assert.Equal(t, "Movie.2019.1080p.BluRay.x264-GROUP", ranked[0].ReleaseName)
+	}
+}
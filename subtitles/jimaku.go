@@ -0,0 +1,232 @@
+package subtitles
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lafikl/fluent"
+	"github.com/matcornic/subify/common/config"
+	logger "github.com/spf13/jwalterweatherman"
+)
+
+const (
+	jimakuUserAgent = "Subify"
+	jimakuAPIURL    = "https://jimaku.cc/api"
+)
+
+// Jimaku only stores Japanese subtitles for animes
+var jimakuLangs = map[string]string{
+	"jpn": "ja",
+}
+
+// JimakuAPI is the endpoint for downloading anime subtitles from Jimaku.
+// Videos are matched with the absolute episode number and the fansub group found in their name
+type JimakuAPI struct {
+	Name    string
+	Aliases []string
+}
+
+// Jimaku creates a new API for Jimaku
+func Jimaku() JimakuAPI {
+	return JimakuAPI{
+		Name:    "Jimaku",
+		Aliases: []string{"jimaku", "jmk", "anime"},
+	}
+}
+
+// jimakuEntry is an anime known by Jimaku
+type jimakuEntry struct {
+	ID           int    `json:"id"`
+	Name         string `json:"name"`
+	EnglishName  string `json:"english_name"`
+	JapaneseName string `json:"japanese_name"`
+}
+
+// jimakuFile is a subtitle file (or an archive of subtitles) of an anime
+type jimakuFile struct {
+	URL  string `json:"url"`
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+}
+
+// Download downloads the Jimaku subtitle from a video
+func (s JimakuAPI) Download(videoPath string, language Language) (subtitlePath string, err error) {
+	if config.JimakuAPIKey == "" {
+		return "", errors.New("Jimaku needs an API key. Set 'jimaku.apikey' in your configuration file")
+	}
+	lang, ok := jimakuLangs[language.ID]
+	if !ok {
+		return "", errors.New("Language exists but is not available for Jimaku")
+	}
+
+	video := ParseRelease(videoPath)
+	if video.Title == "" || !video.IsEpisode() {
+		return "", errors.New("Can't guess the anime and its episode number from the name of the video")
+	}
+
+	entry, err := jimakuSearchEntry(video)
+	if err != nil {
+		return "", err
+	}
+	files, err := jimakuFiles(entry.ID)
+	if err != nil {
+		return "", err
+	}
+
+	name, content, err := jimakuBestFile(files, video)
+	if err != nil {
+		return "", err
+	}
+
+	// ASS subtitles are kept as is, to preserve their styling
+	subtitlePath = subtitlePathFor(videoPath, lang, strings.ToLower(path.Ext(name)))
+	if err = ioutil.WriteFile(subtitlePath, content, 0644); err != nil {
+		return "", fmt.Errorf("Can't save the file %v because of : %v", subtitlePath, err)
+	}
+	logger.INFO.Println("Original name of subtitle :", name, "("+entry.Name+")")
+
+	return subtitlePath, nil
+}
+
+// Upload uploads the subtitle to Jimaku, for the given video
+func (s JimakuAPI) Upload(subtitlePath string, language Language, videoPath string) error {
+	return errors.New("Not yet implemented")
+}
+
+// GetName returns the name of the api
+func (s JimakuAPI) GetName() string {
+	return s.Name
+}
+
+// GetAliases returns aliases to identify this API
+func (s JimakuAPI) GetAliases() []string {
+	return s.Aliases
+}
+
+// GetCapabilities returns what this API is able to do
+func (s JimakuAPI) GetCapabilities() Capabilities {
+	return Capabilities{
+		Languages:   jimakuLangs,
+		SearchModes: []SearchMode{SearchByName},
+		NeedsAuth:   true,
+	}
+}
+
+// jimakuGet calls the Jimaku API and returns the raw body
+func jimakuGet(u string) ([]byte, error) {
+	req := fluent.New()
+	req.Get(u).
+		SetHeader("User-Agent", jimakuUserAgent).
+		SetHeader("Authorization", config.JimakuAPIKey).
+		InitialInterval(time.Duration(time.Millisecond)).
+		Retry(3)
+	res, err := req.Send()
+	if err != nil {
+		return nil, fmt.Errorf("Can't reach the Jimaku Web API. Are you connected to the Internet ? %v", err.Error())
+	}
+	defer res.Body.Close()
+	if res.StatusCode == 401 {
+		return nil, errors.New("Jimaku refused the API key")
+	}
+	if res.StatusCode != 200 {
+		return nil, fmt.Errorf("Jimaku answered with status %v", res.StatusCode)
+	}
+	return ioutil.ReadAll(res.Body)
+}
+
+// jimakuSearchEntry finds the anime whose name is the closest to the title of the video
+func jimakuSearchEntry(video Release) (jimakuEntry, error) {
+	v := url.Values{}
+	v.Set("anime", "true")
+	v.Set("query", video.Title)
+	body, err := jimakuGet(jimakuAPIURL + "/entries/search?" + v.Encode())
+	if err != nil {
+		return jimakuEntry{}, err
+	}
+	var entries []jimakuEntry
+	if err := json.Unmarshal(body, &entries); err != nil {
+		return jimakuEntry{}, fmt.Errorf("The answer of Jimaku can't be read: %v", err)
+	}
+	if len(entries) == 0 {
+		return jimakuEntry{}, fmt.Errorf("Anime %v is not known by Jimaku", video.Title)
+	}
+
+	title := ParseRelease(video.Title)
+	best, bestScore := entries[0], -1.0
+	for _, e := range entries {
+		for _, name := range []string{e.Name, e.EnglishName, e.JapaneseName} {
+			if score := title.Similarity(ParseRelease(name)); score > bestScore {
+				best, bestScore = e, score
+			}
+		}
+	}
+	return best, nil
+}
+
+// jimakuFiles lists the files of an anime
+func jimakuFiles(id int) ([]jimakuFile, error) {
+	body, err := jimakuGet(jimakuAPIURL + "/entries/" + strconv.Itoa(id) + "/files")
+	if err != nil {
+		return nil, err
+	}
+	var files []jimakuFile
+	if err := json.Unmarshal(body, &files); err != nil {
+		return nil, fmt.Errorf("The answer of Jimaku can't be read: %v", err)
+	}
+	return files, nil
+}
+
+// jimakuBestFile downloads the file matching the best the episode and the fansub group of the video.
+// Single subtitle files are preferred over ZIP archives, which are opened to find the episode.
+func jimakuBestFile(files []jimakuFile, video Release) (name string, content []byte, err error) {
+	var subtitles, archives []string
+	byName := make(map[string]jimakuFile)
+	for _, f := range files {
+		byName[f.Name] = f
+		if isSubtitleFile(f.Name) && ParseRelease(f.Name).Episode == video.Episode {
+			subtitles = append(subtitles, f.Name)
+		} else if strings.EqualFold(path.Ext(f.Name), ".zip") && f.Size <= maxSubtitleSize {
+			archives = append(archives, f.Name)
+		}
+	}
+
+	if best := chooseSubtitleFile(subtitles, video); best != "" {
+		content, err := jimakuGet(byName[best].URL)
+		return best, content, err
+	}
+
+	for len(archives) > 0 {
+		best := chooseSubtitleFile(archives, video)
+		if best == "" {
+			break
+		}
+		data, err := jimakuGet(byName[best].URL)
+		if err == nil {
+			if name, content, err = extractFromZip(data, video); err == nil {
+				return name, content, nil
+			}
+		}
+		logger.INFO.Println("Can't use Jimaku archive", best, ":", err)
+		archives = removeString(archives, best)
+	}
+
+	return "", nil, fmt.Errorf("No Jimaku subtitle for episode %v", video.Episode)
+}
+
+// removeString removes a value from a slice of strings
+func removeString(values []string, value string) []string {
+	var result []string
+	for _, v := range values {
+		if v != value {
+			result = append(result, v)
+		}
+	}
+	return result
+}
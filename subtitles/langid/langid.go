@@ -0,0 +1,295 @@
+// Package langid identifies the language of subtitles, without network.
+// Texts are first sorted by script. Languages sharing a script are then told apart by the n-grams of
+// their characters, ranked and compared to the profiles of built-in sample texts (Cavnar and Trenkle)
+package langid
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+const (
+	maxGram     = 3   // Longest n-grams, in characters
+	profileSize = 400 // Most frequent n-grams kept in a profile
+	minLetters  = 60  // Shorter texts are not identified
+	minIdeogram = 20  // Shorter Chinese and Japanese texts are not identified
+	maxLetters  = 30000
+	// margin is the difference of score under which a language can't be told apart from the best one
+	margin = 0.03
+)
+
+// Result is a language identified for a text
+type Result struct {
+	Language string  // ISO 639-1 code
+	Score    float64 // From 0 to 1, how close the text is to the language
+}
+
+// Scripts of the texts
+const (
+	latin    = "latin"
+	cyrillic = "cyrillic"
+	arabic   = "arabic"
+	greek    = "greek"
+	hebrew   = "hebrew"
+	thai     = "thai"
+	hangul   = "hangul"
+	kana     = "kana"
+	han      = "han"
+)
+
+// scriptLanguages are the languages of the scripts used by a single language
+var scriptLanguages = map[string]string{
+	greek:  "el",
+	hebrew: "he",
+	thai:   "th",
+	hangul: "ko",
+	kana:   "ja",
+	han:    "zh",
+}
+
+// families gather the languages written so closely that they are not told apart, under one language
+var families = map[string]string{
+	"sr":  "hr",
+	"bs":  "hr",
+	"cnr": "hr",
+	"sh":  "hr",
+	"nb":  "no",
+	"nn":  "no",
+	"ms":  "id",
+}
+
+// profile is the ranking of the most frequent n-grams of a language
+type profile struct {
+	script string
+	ranks  map[string]int
+}
+
+var (
+	profiles     map[string]profile
+	profilesOnce sync.Once
+)
+
+// loadProfiles builds the profiles of the sample texts
+func loadProfiles() map[string]profile {
+	profilesOnce.Do(func() {
+		profiles = make(map[string]profile, len(samples))
+		for lang, text := range samples {
+			s, _, _ := dominantScript(text)
+			profiles[lang] = profile{script: s, ranks: ranks(text)}
+		}
+	})
+	return profiles
+}
+
+// Family gives the language standing for the languages too close to be told apart (hr for Serbian, Bosnian and Croatian).
+// Languages are given by ISO 639-1 code or BCP 47 tag, whose region and script are ignored (pt-BR is pt)
+func Family(lang string) string {
+	lang = strings.ToLower(strings.SplitN(lang, "-", 2)[0])
+	if f, ok := families[lang]; ok {
+		return f
+	}
+	return lang
+}
+
+// Members gives the languages a detected language stands for, itself first, when it stands for a family
+// (hr for Croatian, Serbian, Bosnian and Montenegrin in Latin script). It is nil for the other languages
+func Members(lang string) []string {
+	lang = strings.ToLower(lang)
+	var members []string
+	for l, f := range families {
+		if f == lang && l != lang {
+			members = append(members, l)
+		}
+	}
+	if len(members) == 0 {
+		return nil
+	}
+	sort.Strings(members)
+	return append([]string{lang}, members...)
+}
+
+// Same tells if two languages are the same, or can't be told apart
+func Same(a, b string) bool {
+	return Family(a) == Family(b)
+}
+
+// Supported tells if the language can be identified
+func Supported(lang string) bool {
+	lang = Family(lang)
+	if _, ok := loadProfiles()[lang]; ok {
+		return true
+	}
+	for _, l := range scriptLanguages {
+		if l == lang {
+			return true
+		}
+	}
+	return false
+}
+
+// Detect identifies the language of a text, like the text of the cues of a subtitle.
+// Languages are given from the most to the least likely. Nothing is given when the text is too short
+func Detect(text string) []Result {
+	s, count, letters := dominantScript(text)
+	if s == han || s == kana {
+		if letters < minIdeogram {
+			return nil
+		}
+	} else if letters < minLetters {
+		return nil
+	}
+	if lang, ok := scriptLanguages[s]; ok {
+		return []Result{{lang, float64(count) / float64(letters)}}
+	}
+
+	text = truncate(text)
+	grams := ranks(text)
+	var results []Result
+	for lang, p := range loadProfiles() {
+		if p.script != s {
+			continue
+		}
+		results = append(results, Result{lang, similarity(grams, p.ranks)})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Language < results[j].Language
+	})
+	return results
+}
+
+// Check tells if a text is in the language. Short texts, languages which can't be identified and languages
+// too close to the identified one are accepted. It gives the language identified for the text, if any
+func Check(text, lang string) (bool, *Result) {
+	results := Detect(text)
+	if len(results) == 0 {
+		return true, nil
+	}
+	best := results[0]
+	if !Supported(lang) || Same(best.Language, lang) {
+		return true, &best
+	}
+	for _, r := range results[1:] {
+		if Same(r.Language, lang) {
+			return best.Score-r.Score < margin, &best
+		}
+	}
+	return false, &best
+}
+
+// dominantScript gives the script of most letters of the text, with its number of letters and the number of all letters.
+// Chinese characters count as Japanese when the text has kana
+func dominantScript(text string) (script string, count int, letters int) {
+	counts := map[string]int{}
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		switch {
+		case unicode.Is(unicode.Latin, r):
+			counts[latin]++
+		case unicode.Is(unicode.Cyrillic, r):
+			counts[cyrillic]++
+		case unicode.Is(unicode.Arabic, r):
+			counts[arabic]++
+		case unicode.Is(unicode.Greek, r):
+			counts[greek]++
+		case unicode.Is(unicode.Hebrew, r):
+			counts[hebrew]++
+		case unicode.Is(unicode.Thai, r):
+			counts[thai]++
+		case unicode.Is(unicode.Hangul, r):
+			counts[hangul]++
+		case unicode.In(r, unicode.Hiragana, unicode.Katakana):
+			counts[kana]++
+		case unicode.Is(unicode.Han, r):
+			counts[han]++
+		}
+	}
+	// Japanese mixes kana and Chinese characters
+	if counts[kana] > 0 && counts[kana]*10 >= counts[kana]+counts[han] {
+		counts[kana] += counts[han]
+		delete(counts, han)
+	}
+	for s, c := range counts {
+		if c > count || (c == count && s < script) {
+			script, count = s, c
+		}
+	}
+	return script, count, letters
+}
+
+// truncate keeps the start of long texts, which is enough to identify them
+func truncate(text string) string {
+	letters := 0
+	for i, r := range text {
+		if unicode.IsLetter(r) {
+			letters++
+		}
+		if letters > maxLetters {
+			return text[:i]
+		}
+	}
+	return text
+}
+
+// ranks ranks the most frequent n-grams of the words of a text, from 0.
+// Words are padded with an underscore, so that n-grams tell the start and end of words
+func ranks(text string) map[string]int {
+	counts := map[string]int{}
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool { return !unicode.IsLetter(r) && !unicode.Is(unicode.Mn, r) })
+	for _, w := range words {
+		runes := []rune("_" + w + "_")
+		for n := 1; n <= maxGram; n++ {
+			for i := 0; i+n <= len(runes); i++ {
+				if gram := string(runes[i : i+n]); gram != "_" {
+					counts[gram]++
+				}
+			}
+		}
+	}
+	grams := make([]string, 0, len(counts))
+	for g := range counts {
+		grams = append(grams, g)
+	}
+	sort.Slice(grams, func(i, j int) bool {
+		if counts[grams[i]] != counts[grams[j]] {
+			return counts[grams[i]] > counts[grams[j]]
+		}
+		return grams[i] < grams[j]
+	})
+	if len(grams) > profileSize {
+		grams = grams[:profileSize]
+	}
+	result := make(map[string]int, len(grams))
+	for i, g := range grams {
+		result[g] = i
+	}
+	return result
+}
+
+// similarity compares the ranks of the n-grams of a text to a profile, from 0 (nothing in common) to 1.
+// N-grams missing from the profile are out of place by the size of the profiles
+func similarity(text, profile map[string]int) float64 {
+	if len(text) == 0 {
+		return 0
+	}
+	distance := 0
+	for gram, rank := range text {
+		if r, ok := profile[gram]; ok {
+			if r > rank {
+				distance += r - rank
+			} else {
+				distance += rank - r
+			}
+		} else {
+			distance += profileSize
+		}
+	}
+	return 1 - float64(distance)/float64(len(text)*profileSize)
+}
@@ -0,0 +1,61 @@
+package langid
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var texts = map[string]string{
+	"en": "I don't know what you're talking about. She left the city last week and nobody has seen her since. We should call the police before it's too late.",
+	"fr": "Je ne sais pas de quoi tu parles. Elle a quitté la ville la semaine dernière et personne ne l'a revue depuis. On devrait appeler la police avant qu'il soit trop tard.",
+	"es": "No sé de qué estás hablando. Ella dejó la ciudad la semana pasada y nadie la ha visto desde entonces. Deberíamos llamar a la policía antes de que sea demasiado tarde.",
+	"pt": "Não sei do que você está falando. Ela deixou a cidade na semana passada e ninguém a viu desde então. Devíamos chamar a polícia antes que seja tarde demais.",
+	"cs": "Nevím, o čem mluvíš. Minulý týden odjela z města a od té doby ji nikdo neviděl. Měli bychom zavolat policii, než bude pozdě.",
+	"sk": "Neviem, o čom hovoríš. Minulý týždeň odišla z mesta a odvtedy ju nikto nevidel. Mali by sme zavolať políciu, kým nebude neskoro.",
+	"ru": "Я не знаю, о чём ты говоришь. Она уехала из города на прошлой неделе, и с тех пор её никто не видел. Нам нужно позвонить в полицию, пока не стало слишком поздно.",
+	"uk": "Я не знаю, про що ти говориш. Вона поїхала з міста минулого тижня, і відтоді її ніхто не бачив. Нам треба подзвонити в поліцію, поки не стало надто пізно.",
+	"hr": "Ne znam o čemu pričaš. Otišla je iz grada prošlog tjedna i otada je nitko nije vidio. Trebali bismo nazvati policiju prije nego što bude kasno.",
+	"fa": "نمی‌دانم درباره چه حرف می‌زنی. او هفته پیش شهر را ترک کرد و از آن موقع هیچ کس او را ندیده است. باید قبل از اینکه دیر شود به پلیس زنگ بزنیم.",
+	"ja": "何を言っているのか分からない。彼女は先週町を出て、それから誰も彼女を見ていない。手遅れになる前に警察に電話するべきだ。",
+	"zh": "我不知道你在说什么。她上周离开了这座城市，从那以后就没有人见过她。我们应该在太晚之前报警。",
+	"el": "Δεν ξέρω τι λες. Έφυγε από την πόλη την περασμένη εβδομάδα και από τότε κανείς δεν την έχει δει. Πρέπει να καλέσουμε την αστυνομία πριν είναι αργά.",
+}
+
+func TestDetectShouldIdentifyLanguages(t *testing.T) {
+	for lang, text := range texts {
+		results := Detect(text)
+		if assert.NotEmpty(t, results, lang) {
+			assert.Equal(t, lang, results[0].Language)
+		}
+	}
+	assert.Nil(t, Detect("Hello there!"), "Short texts should not be identified")
+}
+
+func TestCheckShouldRejectOtherLanguages(t *testing.T) {
+	ok, found := Check(texts["pt"], "en")
+	assert.False(t, ok)
+	assert.Equal(t, "pt", found.Language)
+	ok, _ = Check(texts["pt"], "pt-BR")
+	assert.True(t, ok, "Brazilian Portuguese is Portuguese")
+	ok, _ = Check(texts["hr"], "sr")
+	assert.True(t, ok, "Serbian in latin script can't be told apart from Croatian")
+	ok, _ = Check(texts["zh"], "zh-Hant")
+	assert.True(t, ok)
+	ok, _ = Check(texts["ru"], "uk")
+	assert.False(t, ok)
+	ok, found = Check("Hello there!", "fr")
+	assert.True(t, ok, "Short texts should be accepted")
+	assert.Nil(t, found)
+	ok, _ = Check(texts["en"], "xx")
+	assert.True(t, ok, "Languages which can't be identified should be accepted")
+	assert.False(t, Supported("xx"))
+	assert.True(t, Supported("zh-x-dual"))
+}
+
+func TestMembersShouldGiveTheLanguagesOfFamilies(t *testing.T) {
+	assert.Equal(t, []string{"hr", "bs", "cnr", "sh", "sr"}, Members("hr"))
+	assert.Equal(t, []string{"id", "ms"}, Members("id"))
+	assert.Nil(t, Members("sr"))
+	assert.Nil(t, Members("fr"))
+}
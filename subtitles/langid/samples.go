@@ -0,0 +1,178 @@
+package langid
+
+// samples are texts written like subtitle dialogues, from which the n-gram profiles of the languages are built.
+// Languages are given by ISO 639-1 code
+var samples = map[string]string{
+	"en": `What are you doing here? I told you to wait in the car. I know, but I couldn't stay there any longer.
+They're coming for us, and we don't have much time left. Listen to me, we have to leave right now.
+Where is your brother? He was with me a minute ago. I think he went back to the house to get something.
+That's not a good idea. Nobody should be there tonight. Come on, let's go. I'll call him when we're on the road.
+Thank you for everything you've done for this family. You would have done the same for me.
+Why didn't you tell me the truth? Because I was afraid of what you would think of me.`,
+	"fr": `Qu'est-ce que tu fais ici ? Je t'avais dit d'attendre dans la voiture. Je sais, mais je ne pouvais plus rester là.
+Ils viennent pour nous, et on n'a plus beaucoup de temps. Écoute-moi, il faut partir tout de suite.
+Où est ton frère ? Il était avec moi il y a une minute. Je crois qu'il est retourné à la maison chercher quelque chose.
+Ce n'est pas une bonne idée. Personne ne devrait être là-bas ce soir. Allez, on y va. Je l'appellerai quand on sera sur la route.
+Merci pour tout ce que vous avez fait pour cette famille. Vous auriez fait la même chose pour moi.
+Pourquoi tu ne m'as pas dit la vérité ? Parce que j'avais peur de ce que tu penserais de moi.`,
+	"de": `Was machst du hier? Ich habe dir gesagt, du sollst im Auto warten. Ich weiß, aber ich konnte nicht länger dort bleiben.
+Sie kommen uns holen, und wir haben nicht mehr viel Zeit. Hör mir zu, wir müssen sofort gehen.
+Wo ist dein Bruder? Er war vor einer Minute noch bei mir. Ich glaube, er ist zurück ins Haus gegangen, um etwas zu holen.
+Das ist keine gute Idee. Heute Nacht sollte niemand dort sein. Komm schon, gehen wir. Ich rufe ihn an, wenn wir unterwegs sind.
+Danke für alles, was Sie für diese Familie getan haben. Sie hätten dasselbe für mich getan.
+Warum hast du mir nicht die Wahrheit gesagt? Weil ich Angst hatte, was du von mir denken würdest.`,
+	"es": `¿Qué haces aquí? Te dije que esperaras en el coche. Lo sé, pero no podía quedarme allí más tiempo.
+Vienen a por nosotros y no nos queda mucho tiempo. Escúchame, tenemos que irnos ahora mismo.
+¿Dónde está tu hermano? Estaba conmigo hace un minuto. Creo que volvió a la casa a buscar algo.
+No es una buena idea. Nadie debería estar allí esta noche. Vamos, vámonos. Lo llamaré cuando estemos en la carretera.
+Gracias por todo lo que has hecho por esta familia. Tú habrías hecho lo mismo por mí.
+¿Por qué no me dijiste la verdad? Porque tenía miedo de lo que pensarías de mí.`,
+	"pt": `O que você está fazendo aqui? Eu disse para você esperar no carro. Eu sei, mas não conseguia ficar lá mais tempo.
+Eles estão vindo atrás de nós, e não temos muito tempo. Escute, precisamos sair agora mesmo.
+Onde está o seu irmão? Ele estava comigo há um minuto. Acho que ele voltou para casa para buscar alguma coisa.
+Não é uma boa ideia. Ninguém deveria estar lá esta noite. Vamos embora. Eu ligo para ele quando estivermos na estrada.
+Obrigado por tudo o que fez por esta família. Você teria feito o mesmo por mim.
+Por que não me contou a verdade? Porque eu tinha medo do que você pensaria de mim. Não faz isso comigo, por favor.`,
+	"it": `Che cosa ci fai qui? Ti avevo detto di aspettare in macchina. Lo so, ma non potevo più restare lì.
+Stanno venendo a prenderci e non abbiamo molto tempo. Ascoltami, dobbiamo andarcene subito.
+Dov'è tuo fratello? Era con me un minuto fa. Credo che sia tornato a casa a prendere qualcosa.
+Non è una buona idea. Nessuno dovrebbe essere lì stanotte. Andiamo, forza. Lo chiamerò quando saremo per strada.
+Grazie per tutto quello che hai fatto per questa famiglia. Avresti fatto lo stesso per me.
+Perché non mi hai detto la verità? Perché avevo paura di quello che avresti pensato di me.`,
+	"nl": `Wat doe jij hier? Ik zei toch dat je in de auto moest wachten. Ik weet het, maar ik kon daar niet langer blijven.
+Ze komen ons halen en we hebben niet veel tijd meer. Luister naar me, we moeten nu meteen weg.
+Waar is je broer? Hij was een minuut geleden nog bij mij. Ik denk dat hij terug naar het huis is gegaan om iets te halen.
+Dat is geen goed idee. Niemand zou daar vannacht moeten zijn. Kom op, we gaan. Ik bel hem wel als we onderweg zijn.
+Bedankt voor alles wat je voor deze familie hebt gedaan. Jij zou hetzelfde voor mij hebben gedaan.
+Waarom heb je me niet de waarheid verteld? Omdat ik bang was voor wat je van me zou denken.`,
+	"pl": `Co ty tu robisz? Mówiłem ci, żebyś zaczekała w samochodzie. Wiem, ale nie mogłam tam dłużej zostać.
+Idą po nas, a nie mamy już dużo czasu. Posłuchaj mnie, musimy natychmiast wyjść.
+Gdzie jest twój brat? Był ze mną minutę temu. Chyba wrócił do domu, żeby coś zabrać.
+To nie jest dobry pomysł. Nikt nie powinien tam być dzisiaj w nocy. Chodź, idziemy. Zadzwonię do niego, kiedy będziemy w drodze.
+Dziękuję za wszystko, co zrobiłeś dla tej rodziny. Zrobiłbyś to samo dla mnie.
+Dlaczego nie powiedziałeś mi prawdy? Bo bałem się, co sobie o mnie pomyślisz.`,
+	"cs": `Co tady děláš? Říkal jsem ti, ať počkáš v autě. Já vím, ale nemohla jsem tam zůstat déle.
+Jdou si pro nás a nemáme moc času. Poslouchej mě, musíme hned odejít.
+Kde je tvůj bratr? Byl se mnou před minutou. Myslím, že se vrátil do domu pro něco.
+To není dobrý nápad. Nikdo by tam dnes v noci neměl být. No tak, jdeme. Zavolám mu, až budeme na cestě.
+Děkuji za všechno, co jste pro tuhle rodinu udělal. Vy byste pro mě udělal totéž.
+Proč jsi mi neřekl pravdu? Protože jsem se bál, co si o mně pomyslíš.`,
+	"sk": `Čo tu robíš? Povedal som ti, aby si počkala v aute. Ja viem, ale nemohla som tam zostať dlhšie.
+Idú si po nás a nemáme veľa času. Počúvaj ma, musíme hneď odísť.
+Kde je tvoj brat? Bol so mnou pred minútou. Myslím, že sa vrátil do domu po niečo.
+To nie je dobrý nápad. Nikto by tam dnes v noci nemal byť. No tak, poďme. Zavolám mu, keď budeme na ceste.
+Ďakujem za všetko, čo ste pre túto rodinu urobili. Vy by ste pre mňa urobili to isté.
+Prečo si mi nepovedal pravdu? Pretože som sa bál, čo si o mne pomyslíš.`,
+	"ro": `Ce faci aici? Ți-am spus să aștepți în mașină. Știu, dar nu mai puteam să stau acolo.
+Vin după noi și nu mai avem mult timp. Ascultă-mă, trebuie să plecăm chiar acum.
+Unde e fratele tău? Era cu mine acum un minut. Cred că s-a întors în casă să ia ceva.
+Nu e o idee bună. Nimeni n-ar trebui să fie acolo în seara asta. Haide, să mergem. Îl sun când suntem pe drum.
+Mulțumesc pentru tot ce ați făcut pentru familia asta. Ați fi făcut același lucru pentru mine.
+De ce nu mi-ai spus adevărul? Pentru că îmi era frică de ce ai crede despre mine.`,
+	"hu": `Mit csinálsz itt? Mondtam, hogy várj a kocsiban. Tudom, de nem tudtam tovább ott maradni.
+Értünk jönnek, és nincs sok időnk. Figyelj rám, azonnal el kell mennünk.
+Hol van a bátyád? Egy perce még velem volt. Azt hiszem, visszament a házba valamiért.
+Ez nem jó ötlet. Ma éjjel senkinek sem kellene ott lennie. Gyere, menjünk. Felhívom, amikor már úton leszünk.
+Köszönök mindent, amit ezért a családért tett. Ön is ugyanezt tette volna értem.
+Miért nem mondtad el az igazat? Mert féltem, hogy mit gondolnál rólam.`,
+	"sv": `Vad gör du här? Jag sa ju att du skulle vänta i bilen. Jag vet, men jag kunde inte stanna där längre.
+De kommer efter oss och vi har inte mycket tid kvar. Lyssna på mig, vi måste gå härifrån nu.
+Var är din bror? Han var med mig för en minut sedan. Jag tror att han gick tillbaka till huset för att hämta något.
+Det är ingen bra idé. Ingen borde vara där i natt. Kom igen, vi går. Jag ringer honom när vi är på vägen.
+Tack för allt du har gjort för den här familjen. Du skulle ha gjort samma sak för mig.
+Varför sa du inte sanningen? För att jag var rädd för vad du skulle tycka om mig.`,
+	"da": `Hvad laver du her? Jeg sagde jo, at du skulle vente i bilen. Jeg ved det, men jeg kunne ikke blive der længere.
+De kommer efter os, og vi har ikke meget tid tilbage. Hør på mig, vi skal gå med det samme.
+Hvor er din bror? Han var sammen med mig for et minut siden. Jeg tror, han gik tilbage til huset for at hente noget.
+Det er ikke nogen god idé. Ingen burde være der i nat. Kom nu, lad os gå. Jeg ringer til ham, når vi er på vejen.
+Tak for alt, hvad du har gjort for denne familie. Du ville have gjort det samme for mig.
+Hvorfor fortalte du mig ikke sandheden? Fordi jeg var bange for, hvad du ville tænke om mig.`,
+	"no": `Hva gjør du her? Jeg sa jo at du skulle vente i bilen. Jeg vet det, men jeg kunne ikke bli der lenger.
+De kommer etter oss, og vi har ikke mye tid igjen. Hør på meg, vi må dra med en gang.
+Hvor er broren din? Han var sammen med meg for et minutt siden. Jeg tror han gikk tilbake til huset for å hente noe.
+Det er ikke noen god idé. Ingen burde være der i natt. Kom igjen, vi drar. Jeg ringer ham når vi er på veien.
+Takk for alt du har gjort for denne familien. Du ville ha gjort det samme for meg.
+Hvorfor fortalte du meg ikke sannheten? Fordi jeg var redd for hva du ville tenke om meg.`,
+	"fi": `Mitä sinä täällä teet? Sanoin, että odota autossa. Tiedän, mutta en voinut jäädä sinne enää.
+He tulevat hakemaan meitä, eikä meillä ole paljon aikaa. Kuuntele minua, meidän täytyy lähteä heti.
+Missä veljesi on? Hän oli kanssani minuutti sitten. Luulen, että hän meni takaisin taloon hakemaan jotain.
+Se ei ole hyvä ajatus. Kenenkään ei pitäisi olla siellä tänä yönä. Tule, mennään. Soitan hänelle, kun olemme matkalla.
+Kiitos kaikesta, mitä olet tehnyt tämän perheen hyväksi. Olisit tehnyt saman minulle.
+Miksi et kertonut minulle totuutta? Koska pelkäsin, mitä ajattelisit minusta.`,
+	"tr": `Burada ne yapıyorsun? Sana arabada beklemeni söylemiştim. Biliyorum ama orada daha fazla kalamazdım.
+Bizim için geliyorlar ve fazla vaktimiz yok. Beni dinle, hemen gitmemiz gerekiyor.
+Kardeşin nerede? Bir dakika önce benimleydi. Sanırım bir şey almak için eve geri döndü.
+Bu iyi bir fikir değil. Bu gece kimse orada olmamalı. Hadi, gidelim. Yola çıktığımızda onu ararım.
+Bu aile için yaptığın her şey için teşekkür ederim. Sen de benim için aynısını yapardın.
+Neden bana gerçeği söylemedin? Çünkü benim hakkımda ne düşüneceğinden korktum.`,
+	"hr": `Što radiš ovdje? Rekao sam ti da čekaš u autu. Znam, ali nisam mogla više ostati tamo.
+Dolaze po nas i nemamo puno vremena. Slušaj me, moramo odmah otići.
+Gdje je tvoj brat? Bio je sa mnom prije minutu. Mislim da se vratio u kuću po nešto.
+To nije dobra ideja. Nitko ne bi trebao biti tamo večeras. Hajde, idemo. Nazvat ću ga kad budemo na cesti.
+Hvala vam za sve što ste učinili za ovu obitelj. I vi biste isto učinili za mene.
+Zašto mi nisi rekao istinu? Zato što sam se bojao što ćeš misliti o meni.`,
+	"ca": `Què hi fas aquí? T'havia dit que esperessis al cotxe. Ho sé, però no podia quedar-me allà més temps.
+Vénen a buscar-nos i no ens queda gaire temps. Escolta'm, hem de marxar ara mateix.
+On és el teu germà? Era amb mi fa un minut. Crec que ha tornat a casa a buscar alguna cosa.
+No és una bona idea. Ningú no hauria de ser allà aquesta nit. Som-hi, anem. El trucaré quan siguem a la carretera.
+Gràcies per tot el que has fet per aquesta família. Tu hauries fet el mateix per mi.
+Per què no em vas dir la veritat? Perquè tenia por del que pensaries de mi.`,
+	"id": `Apa yang kamu lakukan di sini? Sudah kubilang tunggu di mobil. Aku tahu, tapi aku tidak bisa tinggal di sana lebih lama lagi.
+Mereka datang untuk kita, dan kita tidak punya banyak waktu. Dengarkan aku, kita harus pergi sekarang juga.
+Di mana saudaramu? Dia bersamaku semenit yang lalu. Kurasa dia kembali ke rumah untuk mengambil sesuatu.
+Itu bukan ide yang bagus. Tidak ada yang boleh berada di sana malam ini. Ayo, kita pergi. Aku akan meneleponnya saat kita di jalan.
+Terima kasih atas semua yang telah kamu lakukan untuk keluarga ini. Kamu juga akan melakukan hal yang sama untukku.
+Kenapa kamu tidak mengatakan yang sebenarnya? Karena aku takut dengan apa yang akan kamu pikirkan tentang aku.`,
+	"vi": `Anh đang làm gì ở đây? Tôi đã bảo anh đợi trong xe mà. Tôi biết, nhưng tôi không thể ở đó lâu hơn được nữa.
+Họ đang đến bắt chúng ta, và chúng ta không còn nhiều thời gian. Nghe tôi này, chúng ta phải đi ngay bây giờ.
+Em trai anh đâu rồi? Nó vừa ở với tôi một phút trước. Tôi nghĩ nó đã quay lại nhà để lấy thứ gì đó.
+Đó không phải là ý hay. Không ai nên ở đó tối nay. Thôi nào, đi thôi. Tôi sẽ gọi cho nó khi chúng ta đang trên đường.
+Cảm ơn vì tất cả những gì anh đã làm cho gia đình này. Anh cũng sẽ làm như vậy cho tôi.
+Tại sao anh không nói cho tôi sự thật? Vì tôi sợ anh sẽ nghĩ gì về tôi.`,
+	"ru": `Что ты здесь делаешь? Я же сказал тебе ждать в машине. Я знаю, но я больше не могла там оставаться.
+Они идут за нами, и у нас мало времени. Послушай меня, нам нужно уходить прямо сейчас.
+Где твой брат? Он был со мной минуту назад. Думаю, он вернулся в дом, чтобы что-то забрать.
+Это плохая идея. Никого не должно быть там сегодня ночью. Давай, пошли. Я позвоню ему, когда мы будем в дороге.
+Спасибо за всё, что вы сделали для этой семьи. Вы бы сделали то же самое для меня.
+Почему ты не сказал мне правду? Потому что я боялся того, что ты обо мне подумаешь.`,
+	"uk": `Що ти тут робиш? Я ж казав тобі чекати в машині. Я знаю, але я більше не могла там залишатися.
+Вони йдуть по нас, і в нас мало часу. Послухай мене, нам треба йти просто зараз.
+Де твій брат? Він був зі мною хвилину тому. Думаю, він повернувся до будинку, щоб щось забрати.
+Це погана ідея. Нікого не повинно бути там сьогодні вночі. Ходімо, швидше. Я подзвоню йому, коли ми будемо в дорозі.
+Дякую за все, що ви зробили для цієї родини. Ви б зробили те саме для мене.
+Чому ти не сказав мені правду? Тому що я боявся того, що ти про мене подумаєш.`,
+	"bg": `Какво правиш тук? Казах ти да чакаш в колата. Знам, но не можех да остана там повече.
+Идват за нас и нямаме много време. Слушай ме, трябва да тръгваме веднага.
+Къде е брат ти? Беше с мен преди минута. Мисля, че се върна в къщата да вземе нещо.
+Това не е добра идея. Никой не трябва да е там тази нощ. Хайде, да вървим. Ще му се обадя, когато сме на пътя.
+Благодаря за всичко, което направихте за това семейство. Вие бихте направили същото за мен.
+Защо не ми каза истината? Защото се страхувах какво ще си помислиш за мен.`,
+	"sr": `Шта радиш овде? Рекао сам ти да чекаш у колима. Знам, али нисам могла више да останем тамо.
+Долазе по нас и немамо много времена. Слушај ме, морамо одмах да идемо.
+Где је твој брат? Био је са мном пре минут. Мислим да се вратио у кућу да узме нешто.
+То није добра идеја. Нико не би требало да буде тамо вечерас. Хајде, идемо. Позваћу га кад будемо на путу.
+Хвала вам на свему што сте урадили за ову породицу. И ви бисте урадили исто за мене.
+Зашто ми ниси рекао истину? Зато што сам се плашио шта ћеш мислити о мени.`,
+	"mk": `Што правиш тука? Ти реков да чекаш во колата. Знам, но не можев повеќе да останам таму.
+Доаѓаат по нас и немаме многу време. Слушај ме, мораме веднаш да заминеме.
+Каде е брат ти? Беше со мене пред една минута. Мислам дека се врати во куќата да земе нешто.
+Тоа не е добра идеја. Никој не треба да биде таму вечерва. Ајде, одиме. Ќе му се јавам кога ќе бидеме на пат.
+Ви благодарам за сè што направивте за ова семејство. И вие ќе го направевте истото за мене.
+Зошто не ми ја кажа вистината? Затоа што се плашев што ќе помислиш за мене.`,
+	"ar": `ماذا تفعل هنا؟ قلت لك أن تنتظر في السيارة. أعرف، لكنني لم أستطع البقاء هناك أكثر.
+إنهم قادمون من أجلنا، وليس لدينا الكثير من الوقت. استمع إلي، يجب أن نغادر الآن.
+أين أخوك؟ كان معي قبل دقيقة. أظن أنه عاد إلى المنزل ليحضر شيئا.
+هذه ليست فكرة جيدة. لا ينبغي أن يكون أحد هناك الليلة. هيا بنا. سأتصل به عندما نكون على الطريق.
+شكرا على كل ما فعلته لهذه العائلة. كنت ستفعل الشيء نفسه من أجلي.
+لماذا لم تخبرني بالحقيقة؟ لأنني كنت خائفا مما ستظنه بي.`,
+	"fa": `اینجا چه کار می‌کنی؟ بهت گفتم توی ماشین منتظر بمانی. می‌دانم، ولی دیگر نمی‌توانستم آنجا بمانم.
+دارند برای ما می‌آیند و وقت زیادی نداریم. به من گوش کن، باید همین الان برویم.
+برادرت کجاست؟ یک دقیقه پیش با من بود. فکر می‌کنم برگشت به خانه تا چیزی بردارد.
+این فکر خوبی نیست. امشب هیچ کس نباید آنجا باشد. بیا، برویم. وقتی توی جاده بودیم به او زنگ می‌زنم.
+ممنون برای همه کارهایی که برای این خانواده کردی. تو هم همین کار را برای من می‌کردی.
+چرا حقیقت را به من نگفتی؟ چون می‌ترسیدم درباره من چه فکری بکنی.`,
+	"ur": `تم یہاں کیا کر رہے ہو؟ میں نے تمہیں گاڑی میں انتظار کرنے کو کہا تھا۔ مجھے پتہ ہے، لیکن میں وہاں مزید نہیں رہ سکتی تھی۔
+وہ ہمارے لیے آ رہے ہیں، اور ہمارے پاس زیادہ وقت نہیں ہے۔ میری بات سنو، ہمیں ابھی جانا ہوگا۔
+تمہارا بھائی کہاں ہے؟ وہ ایک منٹ پہلے میرے ساتھ تھا۔ میرا خیال ہے کہ وہ کچھ لینے گھر واپس گیا ہے۔
+یہ اچھا خیال نہیں ہے۔ آج رات وہاں کسی کو نہیں ہونا چاہیے۔ چلو، چلتے ہیں۔ جب ہم راستے میں ہوں گے تو میں اسے فون کروں گا۔`,
+}
@@ -16,29 +16,86 @@ func (a ByName) Len() int           { return len(a) }
 func (a ByName) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
 func (a ByName) Less(i, j int) bool { return a[i].Description < a[j].Description }
 
-// Language defines a recognized language for
+// Language defines a recognized language for Subify.
+// Languages are identified by their ISO 639-2/B code, and regional or script variants by their BCP 47 tag (pt-BR)
 type Language struct {
 	ID          string   // ID
-	Alias       []string // Available alias (as input for Subify)
+	Alias       []string // Other codes of the language, and codes of the previous versions of Subify (as input for Subify)
 	Description string   // Description of the language
+	Native      string   // Name of the language in the language, empty when unknown (as input for Subify)
+	ISO6391     string   // Two letters code, empty when the language has none
+	ISO6392B    string   // Bibliographic code, used by Matroska
+	ISO6392T    string   // Terminology code, used by MP4. Same as the bibliographic one, but for 20 languages
+	ISO6393     string   // Empty for the groups of languages (ISO 639-5)
+	Tag         string   // BCP 47 tag, used in the name of the subtitle files (en, pt-BR, zh-Hant)
+}
+
+// IsVariant tells if the language is a regional or script variant of another one (pt-BR of Portuguese)
+func (l Language) IsVariant() bool {
+	return l.ID != l.ISO6392B
+}
+
+// Covers tells if subtitles in the other language suit the language: variants are covered by their language
+// (a Brazilian Portuguese subtitle is a Portuguese one), but not the opposite
+func (l Language) Covers(other Language) bool {
+	return l.ID == other.ID || (!l.IsVariant() && l.ID == other.ISO6392B)
+}
+
+// codes gives the codes of the language, without duplicates
+func (l Language) codes() []string {
+	var codes []string
+	for _, c := range []string{l.Tag, l.ISO6391, l.ISO6392B, l.ISO6392T, l.ISO6393} {
+		if c != "" && !contains(codes, c) {
+			codes = append(codes, c)
+		}
+	}
+	return codes
+}
+
+// contains tells if the list has the value, ignoring case
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
 }
 
 // Langs is the type for a list of languages
 type Langs []Language
 
-// GetLanguage get the language from an id
+// GetLanguage get the language from an id: a code (ISO 639-1, ISO 639-2/B or /T, ISO 639-3, BCP 47 tag),
+// or the english or native name of the language (French, français)
 func (l Langs) GetLanguage(id string) (lang *Language) {
+	if lang = l.GetLanguageByCode(id); lang != nil {
+		return lang
+	}
+	id = strings.TrimSpace(id)
 	for _, v := range l {
-		if strings.EqualFold(strings.TrimSpace(v.ID), strings.TrimSpace(id)) {
+		if id != "" && (strings.EqualFold(v.Description, id) || strings.EqualFold(v.Native, id)) {
 			return &v
 		}
-		for _, a := range v.Alias {
-			if strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(id)) {
+	}
+	return nil
+}
+
+// GetLanguageByCode get the language from one of its codes only. Tags with an unknown region or script (en-US)
+// give the language (en)
+func (l Langs) GetLanguageByCode(code string) (lang *Language) {
+	code = strings.Replace(strings.TrimSpace(code), "_", "-", -1)
+	for code != "" {
+		for _, v := range l {
+			if strings.EqualFold(v.ID, code) || contains(v.Alias, code) {
 				return &v
 			}
 		}
+		i := strings.LastIndex(code, "-")
+		if i < 0 {
+			break
+		}
+		code = code[:i]
 	}
-
 	return nil
 }
 
@@ -62,39 +119,62 @@ func (l Langs) GetDescriptions() (descriptions []string) {
 	return
 }
 
-// Print prints the languages
-func (l Langs) Print(all bool) {
+// Available gives the languages supported by at least one of the APIs.
+// APIs accepting any language (like local directories) are not considered
+func (l Langs) Available(apis Clients) (available Langs) {
+	for _, lang := range l {
+		for _, api := range apis {
+			caps := api.GetCapabilities()
+			if !caps.AnyLanguage() && caps.Supports(lang) {
+				available = append(available, lang)
+				break
+			}
+		}
+	}
+	return
+}
+
+// Print prints the languages, with the code of each language in the given APIs.
+// APIs accepting any language (like local directories) are not printed
+func (l Langs) Print(all bool, apis Clients) {
 
 	table := tablewriter.NewWriter(os.Stdout)
 
+	var shown Clients
+	for _, api := range apis {
+		if !api.GetCapabilities().AnyLanguage() {
+			shown = append(shown, api)
+		}
+	}
+
+	header := []string{"Language", "Native name", "Id(s)"}
+	for _, api := range shown {
+		header = append(header, api.GetName())
+	}
 	if all {
-		table.SetHeader([]string{"Language", "Id(s)", "Available ?"})
-	} else {
-		table.SetHeader([]string{"Language", "Available Id(s)"})
+		header = append(header, "Available ?")
 	}
+	table.SetHeader(header)
 
-	langs := Languages
+	langs := append(Langs{}, l...)
 	sort.Sort(ByName(langs))
 
-	for _, l := range langs {
-		_, subOK := subdbLangs[l.ID]
-		_, osOK := osLangs[l.ID]
+	for _, lang := range langs {
+		values := []string{
+			lang.Description, // Language
+			lang.Native,      // Native name
+			strings.Join(append(append([]string{}, lang.Alias...), lang.ID), ", "), // Id(s)
+		}
+		available := false
+		for _, api := range shown {
+			code, ok := api.GetCapabilities().Languages[lang.ID]
+			available = available || ok
+			values = append(values, code) // Code of the language for this API
+		}
 		if all {
-			available := "No"
-			if subOK || osOK {
-				available = "Yes"
-			}
-			values := []string{
-				l.Description, // Language
-				strings.Join(append(l.Alias, l.ID), ", "), // Id(s)
-				available, // Available ?
-			}
-			table.Append(values)
-		} else if subOK || osOK {
-			values := []string{
-				l.Description, // Language
-				strings.Join(append(l.Alias, l.ID), ", "), // Available Id(s)
-			}
+			values = append(values, yesNo(available)) // Available ?
+		}
+		if all || available {
 			table.Append(values)
 		}
 	}
@@ -103,481 +183,560 @@ func (l Langs) Print(all bool) {
 	table.Render() // Send output
 }
 
+// isoLanguage is a language of ISO 639-2, with its codes in the other parts of ISO 639
+type isoLanguage struct {
+	part2B, part2T, part1, part3 string
+	name, native                 string
+}
+
+// isoLanguages are the languages of ISO 639-2
+var isoLanguages = []isoLanguage{
+	// ISO 639-2/B, ISO 639-2/T, ISO 639-1, ISO 639-3, name, native name
+	{"aar", "aar", "aa", "aar", "Afar, afar", ""},
+	{"abk", "abk", "ab", "abk", "Abkhazian", "аԥсуа бызшәа"},
+	{"ace", "ace", "", "ace", "Achinese", ""},
+	{"ach", "ach", "", "ach", "Acoli", ""},
+	{"ada", "ada", "", "ada", "Adangme", ""},
+	{"ady", "ady", "", "ady", "Adyghé", ""},
+	{"afa", "afa", "", "", "Afro-Asiatic (Other)", ""},
+	{"afh", "afh", "", "afh", "Afrihili", ""},
+	{"afr", "afr", "af", "afr", "Afrikaans", "Afrikaans"},
+	{"ain", "ain", "", "ain", "Ainu", ""},
+	{"aka", "aka", "ak", "aka", "Akan", "Akan"},
+	{"akk", "akk", "", "akk", "Akkadian", ""},
+	{"alb", "sqi", "sq", "sqi", "Albanian", "shqip"},
+	{"ale", "ale", "", "ale", "Aleut", ""},
+	{"alg", "alg", "", "", "Algonquian languages", ""},
+	{"alt", "alt", "", "alt", "Southern Altai", ""},
+	{"amh", "amh", "am", "amh", "Amharic", "አማርኛ"},
+	{"ang", "ang", "", "ang", "English, Old", "Ænglisc"},
+	{"apa", "apa", "", "", "Apache languages", ""},
+	{"ara", "ara", "ar", "ara", "Arabic", "العربية"},
+	{"arc", "arc", "", "arc", "Aramaic", "ܐܪܡܝܐ"},
+	{"arg", "arg", "an", "arg", "Aragonese", "aragonés"},
+	{"arm", "hye", "hy", "hye", "Armenian", "հայերեն"},
+	{"arn", "arn", "", "arn", "Araucanian", ""},
+	{"arp", "arp", "", "arp", "Arapaho", ""},
+	{"art", "art", "", "", "Artificial (Other)", ""},
+	{"arw", "arw", "", "arw", "Arawak", ""},
+	{"asm", "asm", "as", "asm", "Assamese", "অসমীয়া"},
+	{"ast", "ast", "", "ast", "Asturian, Bable", "asturianu"},
+	{"ath", "ath", "", "", "Athapascan languages", ""},
+	{"aus", "aus", "", "", "Australian languages", ""},
+	{"ava", "ava", "av", "ava", "Avaric", "авар мацӀ"},
+	{"ave", "ave", "ae", "ave", "Avestan", ""},
+	{"awa", "awa", "", "awa", "Awadhi", ""},
+	{"aym", "aym", "ay", "aym", "Aymara", "aymar aru"},
+	{"aze", "aze", "az", "aze", "Azerbaijani", "azərbaycan"},
+	{"bad", "bad", "", "", "Banda", ""},
+	{"bai", "bai", "", "", "Bamileke languages", ""},
+	{"bak", "bak", "ba", "bak", "Bashkir", "башҡорт теле"},
+	{"bal", "bal", "", "bal", "Baluchi", ""},
+	{"bam", "bam", "bm", "bam", "Bambara", "bamanakan"},
+	{"ban", "ban", "", "ban", "Balinese", ""},
+	{"baq", "eus", "eu", "eus", "Basque", "euskara"},
+	{"bas", "bas", "", "bas", "Basa", "Ɓàsàa"},
+	{"bat", "bat", "", "", "Baltic (Other)", ""},
+	{"bej", "bej", "", "bej", "Beja", ""},
+	{"bel", "bel", "be", "bel", "Belarusian", "беларуская"},
+	{"bem", "bem", "", "bem", "Bemba", "Ichibemba"},
+	{"ben", "ben", "bn", "ben", "Bengali", "বাংলা"},
+	{"ber", "ber", "", "", "Berber (Other)", ""},
+	{"bho", "bho", "", "bho", "Bhojpuri", ""},
+	{"bih", "bih", "bh", "", "Bihari", ""},
+	{"bik", "bik", "", "bik", "Bikol", ""},
+	{"bin", "bin", "", "bin", "Bini", ""},
+	{"bis", "bis", "bi", "bis", "Bislama", "Bislama"},
+	{"bla", "bla", "", "bla", "Siksika", ""},
+	{"bnt", "bnt", "", "", "Bantu (Other)", ""},
+	{"bos", "bos", "bs", "bos", "Bosnian", "bosanski"},
+	{"bra", "bra", "", "bra", "Braj", ""},
+	{"bre", "bre", "br", "bre", "Breton", "brezhoneg"},
+	{"btk", "btk", "", "", "Batak (Indonesia)", ""},
+	{"bua", "bua", "", "bua", "Buriat", ""},
+	{"bug", "bug", "", "bug", "Buginese", ""},
+	{"bul", "bul", "bg", "bul", "Bulgarian", "български"},
+	{"bur", "mya", "my", "mya", "Burmese", "မြန်မာ"},
+	{"byn", "byn", "", "byn", "Blin", ""},
+	{"cad", "cad", "", "cad", "Caddo", ""},
+	{"cai", "cai", "", "", "Central American Indian", ""},
+	{"car", "car", "", "car", "Carib", ""},
+	{"cat", "cat", "ca", "cat", "Catalan", "català"},
+	{"cau", "cau", "", "", "Caucasian (Other)", ""},
+	{"ceb", "ceb", "", "ceb", "Cebuano", "Binisaya"},
+	{"cel", "cel", "", "", "Celtic (Other)", ""},
+	{"cha", "cha", "ch", "cha", "Chamorro", "Chamoru"},
+	{"chb", "chb", "", "chb", "Chibcha", ""},
+	{"che", "che", "ce", "che", "Chechen", "нохчийн"},
+	{"chg", "chg", "", "chg", "Chagatai", ""},
+	{"chi", "zho", "zh", "zho", "Chinese", "中文"},
+	{"chk", "chk", "", "chk", "Chuukese", ""},
+	{"chm", "chm", "", "chm", "Mari", ""},
+	{"chn", "chn", "", "chn", "Chinook jargon", ""},
+	{"cho", "cho", "", "cho", "Choctaw", ""},
+	{"chp", "chp", "", "chp", "Chipewyan", ""},
+	{"chr", "chr", "", "chr", "Cherokee", "ᏣᎳᎩ"},
+	{"chu", "chu", "cu", "chu", "Church Slavic", "ѩзыкъ словѣньскъ"},
+	{"chv", "chv", "cv", "chv", "Chuvash", "чӑваш чӗлхи"},
+	{"chy", "chy", "", "chy", "Cheyenne", ""},
+	{"cmc", "cmc", "", "", "Chamic languages", ""},
+	{"cnr", "cnr", "", "cnr", "Montenegrin", "crnogorski"},
+	{"cop", "cop", "", "cop", "Coptic", ""},
+	{"cor", "cor", "kw", "cor", "Cornish", "kernewek"},
+	{"cos", "cos", "co", "cos", "Corsican", "corsu"},
+	{"cpe", "cpe", "", "", "Creoles and pidgins, English", ""},
+	{"cpf", "cpf", "", "", "Creoles and pidgins, French", ""},
+	{"cpp", "cpp", "", "", "Creoles and pidgins, Portug", ""},
+	{"cre", "cre", "cr", "cre", "Cree", "ᓀᐦᐃᔭᐍᐏᐣ"},
+	{"crh", "crh", "", "crh", "Crimean Tatar", ""},
+	{"crp", "crp", "", "", "Creoles and pidgins (Other)", ""},
+	{"csb", "csb", "", "csb", "Kashubian", "kaszëbsczi"},
+	{"cus", "cus", "", "", "Cushitic (Other)", ""},
+	{"cze", "ces", "cs", "ces", "Czech", "čeština"},
+	{"dak", "dak", "", "dak", "Dakota", ""},
+	{"dan", "dan", "da", "dan", "Danish", "dansk"},
+	{"dar", "dar", "", "dar", "Dargwa", ""},
+	{"day", "day", "", "", "Dayak", ""},
+	{"del", "del", "", "del", "Delaware", ""},
+	{"den", "den", "", "den", "Slave (Athapascan)", ""},
+	{"dgr", "dgr", "", "dgr", "Dogrib", ""},
+	{"din", "din", "", "din", "Dinka", ""},
+	{"div", "div", "dv", "div", "Divehi", "ދިވެހި"},
+	{"doi", "doi", "", "doi", "Dogri", ""},
+	{"dra", "dra", "", "", "Dravidian (Other)", ""},
+	{"dua", "dua", "", "dua", "Duala", "duálá"},
+	{"dum", "dum", "", "dum", "Dutch, Middle", ""},
+	{"dut", "nld", "nl", "nld", "Dutch", "Nederlands"},
+	{"dyu", "dyu", "", "dyu", "Dyula", ""},
+	{"dzo", "dzo", "dz", "dzo", "Dzongkha", "རྫོང་ཁ"},
+	{"efi", "efi", "", "efi", "Efik", ""},
+	{"egy", "egy", "", "egy", "Egyptian (Ancient)", ""},
+	{"eka", "eka", "", "eka", "Ekajuk", ""},
+	{"elx", "elx", "", "elx", "Elamite", ""},
+	{"eng", "eng", "en", "eng", "English", "English"},
+	{"enm", "enm", "", "enm", "English, Middle", ""},
+	{"epo", "epo", "eo", "epo", "Esperanto", "esperanto"},
+	{"est", "est", "et", "est", "Estonian", "eesti"},
+	{"ewe", "ewe", "ee", "ewe", "Ewe", "Eʋegbe"},
+	{"ewo", "ewo", "", "ewo", "Ewondo", "ewondo"},
+	{"fan", "fan", "", "fan", "Fang", ""},
+	{"fao", "fao", "fo", "fao", "Faroese", "føroyskt"},
+	{"fat", "fat", "", "fat", "Fanti", ""},
+	{"fij", "fij", "fj", "fij", "Fijian", "vosa Vakaviti"},
+	{"fil", "fil", "", "fil", "Filipino", ""},
+	{"fin", "fin", "fi", "fin", "Finnish", "suomi"},
+	{"fiu", "fiu", "", "", "Finno-Ugrian (Other)", ""},
+	{"fon", "fon", "", "fon", "Fon", ""},
+	{"fre", "fra", "fr", "fra", "French", "français"},
+	{"frm", "frm", "", "frm", "French, Middle", ""},
+	{"fro", "fro", "", "fro", "French, Old", ""},
+	{"fry", "fry", "fy", "fry", "Frisian", "Frysk"},
+	{"ful", "ful", "ff", "ful", "Fulah", "Pulaar"},
+	{"fur", "fur", "", "fur", "Friulian", "furlan"},
+	{"gaa", "gaa", "", "gaa", "Ga", ""},
+	{"gay", "gay", "", "gay", "Gayo", ""},
+	{"gba", "gba", "", "gba", "Gbaya", ""},
+	{"gem", "gem", "", "", "Germanic (Other)", ""},
+	{"geo", "kat", "ka", "kat", "Georgian", "ქართული"},
+	{"ger", "deu", "de", "deu", "German", "Deutsch"},
+	{"gez", "gez", "", "gez", "Geez", ""},
+	{"gil", "gil", "", "gil", "Gilbertese", ""},
+	{"gla", "gla", "gd", "gla", "Gaelic", "Gàidhlig"},
+	{"gle", "gle", "ga", "gle", "Irish", "Gaeilge"},
+	{"glg", "glg", "gl", "glg", "Galician", "galego"},
+	{"glv", "glv", "gv", "glv", "Manx", "Gaelg"},
+	{"gmh", "gmh", "", "gmh", "German, Middle High)", ""},
+	{"goh", "goh", "", "goh", "German, Old High", ""},
+	{"gon", "gon", "", "gon", "Gondi", ""},
+	{"gor", "gor", "", "gor", "Gorontalo", ""},
+	{"got", "got", "", "got", "Gothic", ""},
+	{"grb", "grb", "", "grb", "Grebo", ""},
+	{"grc", "grc", "", "grc", "Greek, Ancient (to 1453)", "Ἑλληνική"},
+	{"gre", "ell", "el", "ell", "Greek", "Ελληνικά"},
+	{"grn", "grn", "gn", "grn", "Guarani", "avañe'ẽ"},
+	{"guj", "guj", "gu", "guj", "Gujarati", "ગુજરાતી"},
+	{"gwi", "gwi", "", "gwi", "Gwich´in", ""},
+	{"hai", "hai", "", "hai", "Haida", ""},
+	{"hat", "hat", "ht", "hat", "Haitian", "kreyòl ayisyen"},
+	{"hau", "hau", "ha", "hau", "Hausa", "Hausa"},
+	{"haw", "haw", "", "haw", "Hawaiian", "ʻŌlelo Hawaiʻi"},
+	{"heb", "heb", "he", "heb", "Hebrew", "עברית"},
+	{"her", "her", "hz", "her", "Herero", ""},
+	{"hil", "hil", "", "hil", "Hiligaynon", ""},
+	{"him", "him", "", "", "Himachali", ""},
+	{"hin", "hin", "hi", "hin", "Hindi", "हिन्दी"},
+	{"hit", "hit", "", "hit", "Hittite", ""},
+	{"hmn", "hmn", "", "hmn", "Hmong", "Hmoob"},
+	{"hmo", "hmo", "ho", "hmo", "Hiri Motu", ""},
+	{"hrv", "hrv", "hr", "hrv", "Croatian", "hrvatski"},
+	{"hun", "hun", "hu", "hun", "Hungarian", "magyar"},
+	{"hup", "hup", "", "hup", "Hupa", ""},
+	{"iba", "iba", "", "iba", "Iban", ""},
+	{"ibo", "ibo", "ig", "ibo", "Igbo", "Igbo"},
+	{"ice", "isl", "is", "isl", "Icelandic", "íslenska"},
+	{"ido", "ido", "io", "ido", "Ido", "Ido"},
+	{"iii", "iii", "ii", "iii", "Sichuan Yi", "ꆈꌠꉙ"},
+	{"ijo", "ijo", "", "", "Ijo", ""},
+	{"iku", "iku", "iu", "iku", "Inuktitut", "ᐃᓄᒃᑎᑐᑦ"},
+	{"ile", "ile", "ie", "ile", "Interlingue", ""},
+	{"ilo", "ilo", "", "ilo", "Iloko", ""},
+	{"ina", "ina", "ia", "ina", "Interlingua", "interlingua"},
+	{"inc", "inc", "", "", "Indic (Other)", ""},
+	{"ind", "ind", "id", "ind", "Indonesian", "Bahasa Indonesia"},
+	{"ine", "ine", "", "", "Indo-European (Other)", ""},
+	{"inh", "inh", "", "inh", "Ingush", ""},
+	{"ipk", "ipk", "ik", "ipk", "Inupiaq", ""},
+	{"ira", "ira", "", "", "Iranian (Other)", ""},
+	{"iro", "iro", "", "", "Iroquoian languages", ""},
+	{"ita", "ita", "it", "ita", "Italian", "italiano"},
+	{"jav", "jav", "jv", "jav", "Javanese", "Basa Jawa"},
+	{"jpn", "jpn", "ja", "jpn", "Japanese", "日本語"},
+	{"jpr", "jpr", "", "jpr", "Judeo-Persian", ""},
+	{"jrb", "jrb", "", "jrb", "Judeo-Arabic", ""},
+	{"kaa", "kaa", "", "kaa", "Kara-Kalpak", ""},
+	{"kab", "kab", "", "kab", "Kabyle", "Taqbaylit"},
+	{"kac", "kac", "", "kac", "Kachin", ""},
+	{"kal", "kal", "kl", "kal", "Kalaallisut", "kalaallisut"},
+	{"kam", "kam", "", "kam", "Kamba", "Kikamba"},
+	{"kan", "kan", "kn", "kan", "Kannada", "ಕನ್ನಡ"},
+	{"kar", "kar", "", "", "Karen", ""},
+	{"kas", "kas", "ks", "kas", "Kashmiri", "کٲشُر"},
+	{"kau", "kau", "kr", "kau", "Kanuri", ""},
+	{"kaw", "kaw", "", "kaw", "Kawi", ""},
+	{"kaz", "kaz", "kk", "kaz", "Kazakh", "қазақ тілі"},
+	{"kbd", "kbd", "", "kbd", "Kabardian", ""},
+	{"kha", "kha", "", "kha", "Khasi", ""},
+	{"khi", "khi", "", "", "Khoisan (Other)", ""},
+	{"khm", "khm", "km", "khm", "Khmer", "ខ្មែរ"},
+	{"kho", "kho", "", "kho", "Khotanese", ""},
+	{"kik", "kik", "ki", "kik", "Kikuyu", "Gikuyu"},
+	{"kin", "kin", "rw", "kin", "Kinyarwanda", "Kinyarwanda"},
+	{"kir", "kir", "ky", "kir", "Kirghiz", "кыргызча"},
+	{"kmb", "kmb", "", "kmb", "Kimbundu", ""},
+	{"kok", "kok", "", "kok", "Konkani", "कोंकणी"},
+	{"kom", "kom", "kv", "kom", "Komi", "коми кыв"},
+	{"kon", "kon", "kg", "kon", "Kongo", ""},
+	{"kor", "kor", "ko", "kor", "Korean", "한국어"},
+	{"kos", "kos", "", "kos", "Kosraean", ""},
+	{"kpe", "kpe", "", "kpe", "Kpelle", ""},
+	{"krc", "krc", "", "krc", "Karachay-Balkar", ""},
+	{"kro", "kro", "", "", "Kru", ""},
+	{"kru", "kru", "", "kru", "Kurukh", ""},
+	{"kua", "kua", "kj", "kua", "Kuanyama", ""},
+	{"kum", "kum", "", "kum", "Kumyk", ""},
+	{"kur", "kur", "ku", "kur", "Kurdish", "Kurdî"},
+	{"kut", "kut", "", "kut", "Kutenai", ""},
+	{"lad", "lad", "", "lad", "Ladino", "ladino"},
+	{"lah", "lah", "", "lah", "Lahnda", ""},
+	{"lam", "lam", "", "lam", "Lamba", ""},
+	{"lao", "lao", "lo", "lao", "Lao", "ລາວ"},
+	{"lat", "lat", "la", "lat", "Latin", "latina"},
+	{"lav", "lav", "lv", "lav", "Latvian", "latviešu"},
+	{"lez", "lez", "", "lez", "Lezghian", ""},
+	{"lim", "lim", "li", "lim", "Limburgan", "Limburgs"},
+	{"lin", "lin", "ln", "lin", "Lingala", "lingála"},
+	{"lit", "lit", "lt", "lit", "Lithuanian", "lietuvių"},
+	{"lol", "lol", "", "lol", "Mongo", ""},
+	{"loz", "loz", "", "loz", "Lozi", ""},
+	{"ltz", "ltz", "lb", "ltz", "Luxembourgish", "Lëtzebuergesch"},
+	{"lua", "lua", "", "lua", "Luba-Lulua", ""},
+	{"lub", "lub", "lu", "lub", "Luba-Katanga", "Tshiluba"},
+	{"lug", "lug", "lg", "lug", "Ganda", "Luganda"},
+	{"lui", "lui", "", "lui", "Luiseno", ""},
+	{"lun", "lun", "", "lun", "Lunda", ""},
+	{"luo", "luo", "", "luo", "Luo (Kenya and Tanzania)", "Dholuo"},
+	{"lus", "lus", "", "lus", "Lushai", ""},
+	{"mac", "mkd", "mk", "mkd", "Macedonian", "македонски"},
+	{"mad", "mad", "", "mad", "Madurese", ""},
+	{"mag", "mag", "", "mag", "Magahi", ""},
+	{"mah", "mah", "mh", "mah", "Marshallese", "Kajin M̧ajeļ"},
+	{"mai", "mai", "", "mai", "Maithili", ""},
+	{"mak", "mak", "", "mak", "Makasar", ""},
+	{"mal", "mal", "ml", "mal", "Malayalam", "മലയാളം"},
+	{"man", "man", "", "man", "Mandingo", ""},
+	{"mao", "mri", "mi", "mri", "Maori", "Māori"},
+	{"map", "map", "", "", "Austronesian (Other)", ""},
+	{"mar", "mar", "mr", "mar", "Marathi", "मराठी"},
+	{"mas", "mas", "", "mas", "Masai", "Maa"},
+	{"may", "msa", "ms", "msa", "Malay", "Bahasa Melayu"},
+	{"mdf", "mdf", "", "mdf", "Moksha", ""},
+	{"mdr", "mdr", "", "mdr", "Mandar", ""},
+	{"men", "men", "", "men", "Mende", ""},
+	{"mga", "mga", "", "mga", "Irish, Middle", ""},
+	{"mic", "mic", "", "mic", "Mi'kmaq", ""},
+	{"min", "min", "", "min", "Minangkabau", ""},
+	{"mis", "mis", "", "mis", "Miscellaneous languages", ""},
+	{"mkh", "mkh", "", "", "Mon-Khmer (Other)", ""},
+	{"mlg", "mlg", "mg", "mlg", "Malagasy", "Malagasy"},
+	{"mlt", "mlt", "mt", "mlt", "Maltese", "Malti"},
+	{"mnc", "mnc", "", "mnc", "Manchu", ""},
+	{"mni", "mni", "", "mni", "Manipuri", ""},
+	{"mno", "mno", "", "", "Manobo languages", ""},
+	{"moh", "moh", "", "moh", "Mohawk", ""},
+	{"mol", "mol", "", "", "Moldavian", ""},
+	{"mon", "mon", "mn", "mon", "Mongolian", "монгол"},
+	{"mos", "mos", "", "mos", "Mossi", ""},
+	{"mul", "mul", "", "mul", "Multiple languages", ""},
+	{"mun", "mun", "", "", "Munda languages", ""},
+	{"mus", "mus", "", "mus", "Creek", ""},
+	{"mwl", "mwl", "", "mwl", "Mirandese", ""},
+	{"mwr", "mwr", "", "mwr", "Marwari", ""},
+	{"myn", "myn", "", "", "Mayan languages", ""},
+	{"myv", "myv", "", "myv", "Erzya", ""},
+	{"nah", "nah", "", "", "Nahuatl", ""},
+	{"nai", "nai", "", "", "North American Indian", ""},
+	{"nap", "nap", "", "nap", "Neapolitan", "napulitano"},
+	{"nau", "nau", "na", "nau", "Nauru", "Dorerin Naoero"},
+	{"nav", "nav", "nv", "nav", "Navajo", "Diné bizaad"},
+	{"nbl", "nbl", "nr", "nbl", "Ndebele, South", ""},
+	{"nde", "nde", "nd", "nde", "Ndebele, North", "isiNdebele"},
+	{"ndo", "ndo", "ng", "ndo", "Ndonga", ""},
+	{"nds", "nds", "", "nds", "Low German", "Plattdüütsch"},
+	{"nep", "nep", "ne", "nep", "Nepali", "नेपाली"},
+	{"new", "new", "", "new", "Nepal Bhasa", ""},
+	{"nia", "nia", "", "nia", "Nias", ""},
+	{"nic", "nic", "", "", "Niger-Kordofanian (Other)", ""},
+	{"niu", "niu", "", "niu", "Niuean", ""},
+	{"nno", "nno", "nn", "nno", "Norwegian Nynorsk", "nynorsk"},
+	{"nob", "nob", "nb", "nob", "Norwegian Bokmal", "norsk bokmål"},
+	{"nog", "nog", "", "nog", "Nogai", ""},
+	{"non", "non", "", "non", "Norse, Old", ""},
+	{"nor", "nor", "no", "nor", "Norwegian", "norsk"},
+	{"nso", "nso", "", "nso", "Northern Sotho", ""},
+	{"nub", "nub", "", "", "Nubian languages", ""},
+	{"nwc", "nwc", "", "nwc", "Classical Newari", ""},
+	{"nya", "nya", "ny", "nya", "Chichewa", ""},
+	{"nym", "nym", "", "nym", "Nyamwezi", ""},
+	{"nyn", "nyn", "", "nyn", "Nyankole", "Runyankore"},
+	{"nyo", "nyo", "", "nyo", "Nyoro", ""},
+	{"nzi", "nzi", "", "nzi", "Nzima", ""},
+	{"oci", "oci", "oc", "oci", "Occitan", "occitan"},
+	{"oji", "oji", "oj", "oji", "Ojibwa", "ᐊᓂᔑᓈᐯᒧᐎᓐ"},
+	{"ori", "ori", "or", "ori", "Oriya", "ଓଡ଼ିଆ"},
+	{"orm", "orm", "om", "orm", "Oromo", "Oromoo"},
+	{"osa", "osa", "", "osa", "Osage", ""},
+	{"oss", "oss", "os", "oss", "Ossetian", "ирон"},
+	{"ota", "ota", "", "ota", "Turkish, Ottoman", ""},
+	{"oto", "oto", "", "", "Otomian languages", ""},
+	{"paa", "paa", "", "", "Papuan (Other)", ""},
+	{"pag", "pag", "", "pag", "Pangasinan", ""},
+	{"pal", "pal", "", "pal", "Pahlavi", ""},
+	{"pam", "pam", "", "pam", "Pampanga", ""},
+	{"pan", "pan", "pa", "pan", "Panjabi", "ਪੰਜਾਬੀ"},
+	{"pap", "pap", "", "pap", "Papiamento", ""},
+	{"pau", "pau", "", "pau", "Palauan", ""},
+	{"peo", "peo", "", "peo", "Persian, Old", ""},
+	{"per", "fas", "fa", "fas", "Persian", "فارسی"},
+	{"phi", "phi", "", "", "Philippine (Other)", ""},
+	{"phn", "phn", "", "phn", "Phoenician", ""},
+	{"pli", "pli", "pi", "pli", "Pali", "पाऴि"},
+	{"pol", "pol", "pl", "pol", "Polish", "polski"},
+	{"pon", "pon", "", "pon", "Pohnpeian", ""},
+	{"por", "por", "pt", "por", "Portuguese", "português"},
+	{"pra", "pra", "", "", "Prakrit languages", ""},
+	{"pro", "pro", "", "pro", "Provençal, Old", ""},
+	{"pus", "pus", "ps", "pus", "Pushto", "پښتو"},
+	{"que", "que", "qu", "que", "Quechua", "Runasimi"},
+	{"raj", "raj", "", "raj", "Rajasthani", ""},
+	{"rap", "rap", "", "rap", "Rapanui", ""},
+	{"rar", "rar", "", "rar", "Rarotongan", ""},
+	{"roa", "roa", "", "", "Romance (Other)", ""},
+	{"roh", "roh", "rm", "roh", "Raeto-Romance", "rumantsch"},
+	{"rom", "rom", "", "rom", "Romany", "romani čhib"},
+	{"rum", "ron", "ro", "ron", "Romanian", "română"},
+	{"run", "run", "rn", "run", "Rundi", "Ikirundi"},
+	{"rup", "rup", "", "rup", "Aromanian", ""},
+	{"rus", "rus", "ru", "rus", "Russian", "русский"},
+	{"sad", "sad", "", "sad", "Sandawe", ""},
+	{"sag", "sag", "sg", "sag", "Sango", "Sängö"},
+	{"sah", "sah", "", "sah", "Yakut", "саха тыла"},
+	{"sai", "sai", "", "", "South American Indian (Other)", ""},
+	{"sal", "sal", "", "", "Salishan languages", ""},
+	{"sam", "sam", "", "sam", "Samaritan Aramaic", ""},
+	{"san", "san", "sa", "san", "Sanskrit", "संस्कृतम्"},
+	{"sas", "sas", "", "sas", "Sasak", ""},
+	{"sat", "sat", "", "sat", "Santali", ""},
+	{"scn", "scn", "", "scn", "Sicilian", "sicilianu"},
+	{"sco", "sco", "", "sco", "Scots", "Scots"},
+	{"sel", "sel", "", "sel", "Selkup", ""},
+	{"sem", "sem", "", "", "Semitic (Other)", ""},
+	{"sga", "sga", "", "sga", "Irish, Old", ""},
+	{"sgn", "sgn", "", "", "Sign Languages", ""},
+	{"shn", "shn", "", "shn", "Shan", ""},
+	{"sid", "sid", "", "sid", "Sidamo", ""},
+	{"sin", "sin", "si", "sin", "Sinhalese", "සිංහල"},
+	{"sio", "sio", "", "", "Siouan languages", ""},
+	{"sit", "sit", "", "", "Sino-Tibetan (Other)", ""},
+	{"sla", "sla", "", "", "Slavic (Other)", ""},
+	{"slo", "slk", "sk", "slk", "Slovak", "slovenčina"},
+	{"slv", "slv", "sl", "slv", "Slovenian", "slovenščina"},
+	{"sma", "sma", "", "sma", "Southern Sami", ""},
+	{"sme", "sme", "se", "sme", "Northern Sami", "davvisámegiella"},
+	{"smi", "smi", "", "", "Sami languages (Other)", ""},
+	{"smj", "smj", "", "smj", "Lule Sami", ""},
+	{"smn", "smn", "", "smn", "Inari Sami", "anarâškielâ"},
+	{"smo", "smo", "sm", "smo", "Samoan", "Gagana Samoa"},
+	{"sms", "sms", "", "sms", "Skolt Sami", ""},
+	{"sna", "sna", "sn", "sna", "Shona", "chiShona"},
+	{"snd", "snd", "sd", "snd", "Sindhi", "سنڌي"},
+	{"snk", "snk", "", "snk", "Soninke", ""},
+	{"sog", "sog", "", "sog", "Sogdian", ""},
+	{"som", "som", "so", "som", "Somali", "Soomaali"},
+	{"son", "son", "", "", "Songhai", ""},
+	{"sot", "sot", "st", "sot", "Sotho, Southern", "Sesotho"},
+	{"spa", "spa", "es", "spa", "Spanish", "español"},
+	{"srd", "srd", "sc", "srd", "Sardinian", "sardu"},
+	{"srp", "srp", "sr", "srp", "Serbian", "српски"},
+	{"srr", "srr", "", "srr", "Serer", ""},
+	{"ssa", "ssa", "", "", "Nilo-Saharan (Other)", ""},
+	{"ssw", "ssw", "ss", "ssw", "Swati", "siSwati"},
+	{"suk", "suk", "", "suk", "Sukuma", ""},
+	{"sun", "sun", "su", "sun", "Sundanese", "Basa Sunda"},
+	{"sus", "sus", "", "sus", "Susu", ""},
+	{"sux", "sux", "", "sux", "Sumerian", ""},
+	{"swa", "swa", "sw", "swa", "Swahili", "Kiswahili"},
+	{"swe", "swe", "sv", "swe", "Swedish", "svenska"},
+	{"syr", "syr", "", "syr", "Syriac", "ܠܫܢܐ ܣܘܪܝܝܐ"},
+	{"tah", "tah", "ty", "tah", "Tahitian", "reo Tahiti"},
+	{"tai", "tai", "", "", "Tai (Other)", ""},
+	{"tam", "tam", "ta", "tam", "Tamil", "தமிழ்"},
+	{"tat", "tat", "tt", "tat", "Tatar", "татар"},
+	{"tel", "tel", "te", "tel", "Telugu", "తెలుగు"},
+	{"ter", "ter", "", "ter", "Tereno", ""},
+	{"tet", "tet", "", "tet", "Tetum", ""},
+	{"tgk", "tgk", "tg", "tgk", "Tajik", "тоҷикӣ"},
+	{"tgl", "tgl", "tl", "tgl", "Tagalog", "Tagalog"},
+	{"tha", "tha", "th", "tha", "Thai", "ไทย"},
+	{"tib", "bod", "bo", "bod", "Tibetan", "བོད་སྐད་"},
+	{"tig", "tig", "", "tig", "Tigre", ""},
+	{"tir", "tir", "ti", "tir", "Tigrinya", "ትግርኛ"},
+	{"tiv", "tiv", "", "tiv", "Tiv", ""},
+	{"tkl", "tkl", "", "tkl", "Tokelau", ""},
+	{"tlh", "tlh", "", "tlh", "Klingon", "tlhIngan Hol"},
+	{"tli", "tli", "", "tli", "Tlingit", ""},
+	{"tmh", "tmh", "", "tmh", "Tamashek", ""},
+	{"tog", "tog", "", "tog", "Tonga (Nyasa)", ""},
+	{"ton", "ton", "to", "ton", "Tonga (Tonga Islands)", "lea fakatonga"},
+	{"tpi", "tpi", "", "tpi", "Tok Pisin", ""},
+	{"tsi", "tsi", "", "tsi", "Tsimshian", ""},
+	{"tsn", "tsn", "tn", "tsn", "Tswana", "Setswana"},
+	{"tso", "tso", "ts", "tso", "Tsonga", "Xitsonga"},
+	{"tuk", "tuk", "tk", "tuk", "Turkmen", "Türkmen dili"},
+	{"tum", "tum", "", "tum", "Tumbuka", ""},
+	{"tup", "tup", "", "", "Tupi languages", ""},
+	{"tur", "tur", "tr", "tur", "Turkish", "Türkçe"},
+	{"tut", "tut", "", "", "Altaic (Other)", ""},
+	{"tvl", "tvl", "", "tvl", "Tuvalu", ""},
+	{"twi", "twi", "tw", "twi", "Twi", ""},
+	{"tyv", "tyv", "", "tyv", "Tuvinian", ""},
+	{"udm", "udm", "", "udm", "Udmurt", ""},
+	{"uga", "uga", "", "uga", "Ugaritic", ""},
+	{"uig", "uig", "ug", "uig", "Uighur", "ئۇيغۇرچە"},
+	{"ukr", "ukr", "uk", "ukr", "Ukrainian", "українська"},
+	{"umb", "umb", "", "umb", "Umbundu", ""},
+	{"und", "und", "", "und", "Undetermined", ""},
+	{"urd", "urd", "ur", "urd", "Urdu", "اردو"},
+	{"uzb", "uzb", "uz", "uzb", "Uzbek", "o‘zbek"},
+	{"vai", "vai", "", "vai", "Vai", "ꕙꔤ"},
+	{"ven", "ven", "ve", "ven", "Venda", ""},
+	{"vie", "vie", "vi", "vie", "Vietnamese", "Tiếng Việt"},
+	{"vol", "vol", "vo", "vol", "Volapük", "Volapük"},
+	{"vot", "vot", "", "vot", "Votic", ""},
+	{"wak", "wak", "", "", "Wakashan languages", ""},
+	{"wal", "wal", "", "wal", "Walamo", ""},
+	{"war", "war", "", "war", "Waray", ""},
+	{"was", "was", "", "was", "Washo", ""},
+	{"wel", "cym", "cy", "cym", "Welsh", "Cymraeg"},
+	{"wen", "wen", "", "", "Sorbian languages", ""},
+	{"wln", "wln", "wa", "wln", "Walloon", "walon"},
+	{"wol", "wol", "wo", "wol", "Wolof", "Wolof"},
+	{"xal", "xal", "", "xal", "Kalmyk", ""},
+	{"xho", "xho", "xh", "xho", "Xhosa", "isiXhosa"},
+	{"yao", "yao", "", "yao", "Yao", ""},
+	{"yap", "yap", "", "yap", "Yapese", ""},
+	{"yid", "yid", "yi", "yid", "Yiddish", "ייִדיש"},
+	{"yor", "yor", "yo", "yor", "Yoruba", "Èdè Yorùbá"},
+	{"ypk", "ypk", "", "", "Yupik languages", ""},
+	{"zap", "zap", "", "zap", "Zapotec", ""},
+	{"zen", "zen", "", "zen", "Zenaga", ""},
+	{"zha", "zha", "za", "zha", "Zhuang", ""},
+	{"znd", "znd", "", "", "Zande", ""},
+	{"zul", "zul", "zu", "zul", "Zulu", "isiZulu"},
+	{"zun", "zun", "", "zun", "Zuni", ""},
+}
+
+// variant is a regional or script variant of a language
+type variant struct {
+	tag          string // BCP 47 tag
+	language     string // ISO 639-2/B code of the language
+	name, native string
+}
+
+// variants are the variants of languages having their own subtitles
+var variants = []variant{
+	{"es-419", "spa", "Spanish (Latin America)", "español (Latinoamérica)"},
+	{"fr-CA", "fre", "French (Canada)", "français (Canada)"},
+	{"pt-BR", "por", "Portuguese (Brazil)", "português (Brasil)"},
+	{"zh-Hans", "chi", "Chinese (simplified)", "简体中文"},
+	{"zh-Hant", "chi", "Chinese (traditional)", "繁體中文"},
+	{"zh-x-dual", "chi", "Chinese bilingual", "中英双语"},
+}
+
+// legacyCodes are the codes of the previous versions of Subify which are not ISO 639 codes, with the ID of their language
+var legacyCodes = map[string]string{
+	"frc": "fr-CA",
+	"ma":  "mni",
+	"me":  "cnr",
+	"mne": "cnr",
+	"mo":  "mol",
+	"pb":  "pt-BR",
+	"pob": "pt-BR",
+	"scc": "srp",
+	"sy":  "syr",
+	"ze":  "zh-x-dual",
+	"zhe": "zh-x-dual",
+	"zht": "zh-Hant",
+	"zt":  "zh-Hant",
+}
+
 //Languages is the list of all languages
-var Languages = Langs{
-	{"aar", []string{"aa"}, "Afar, afar"},
-	{"abk", []string{"ab"}, "Abkhazian"},
-	{"ace", []string{}, "Achinese"},
-	{"ach", []string{}, "Acoli"},
-	{"ada", []string{}, "Adangme"},
-	{"ady", []string{}, "Adyghé"},
-	{"afa", []string{}, "Afro-Asiatic (Other)"},
-	{"afh", []string{}, "Afrihili"},
-	{"afr", []string{"af"}, "Afrikaans"},
-	{"ain", []string{}, "Ainu"},
-	{"aka", []string{"ak"}, "Akan"},
-	{"akk", []string{}, "Akkadian"},
-	{"alb", []string{"sq"}, "Albanian"},
-	{"ale", []string{}, "Aleut"},
-	{"alg", []string{}, "Algonquian languages"},
-	{"alt", []string{}, "Southern Altai"},
-	{"amh", []string{"am"}, "Amharic"},
-	{"ang", []string{}, "English, Old"},
-	{"apa", []string{}, "Apache languages"},
-	{"ara", []string{"ar"}, "Arabic"},
-	{"arc", []string{}, "Aramaic"},
-	{"arg", []string{"an"}, "Aragonese"},
-	{"arm", []string{"hy"}, "Armenian"},
-	{"arn", []string{}, "Araucanian"},
-	{"arp", []string{}, "Arapaho"},
-	{"art", []string{}, "Artificial (Other)"},
-	{"arw", []string{}, "Arawak"},
-	{"asm", []string{"as"}, "Assamese"},
-	{"ast", []string{}, "Asturian, Bable"},
-	{"ath", []string{}, "Athapascan languages"},
-	{"aus", []string{}, "Australian languages"},
-	{"ava", []string{"av"}, "Avaric"},
-	{"ave", []string{"ae"}, "Avestan"},
-	{"awa", []string{}, "Awadhi"},
-	{"aym", []string{"ay"}, "Aymara"},
-	{"aze", []string{"az"}, "Azerbaijani"},
-	{"bad", []string{}, "Banda"},
-	{"bai", []string{}, "Bamileke languages"},
-	{"bak", []string{"ba"}, "Bashkir"},
-	{"bal", []string{}, "Baluchi"},
-	{"bam", []string{"bm"}, "Bambara"},
-	{"ban", []string{}, "Balinese"},
-	{"baq", []string{"eu"}, "Basque"},
-	{"bas", []string{}, "Basa"},
-	{"bat", []string{}, "Baltic (Other)"},
-	{"bej", []string{}, "Beja"},
-	{"bel", []string{"be"}, "Belarusian"},
-	{"bem", []string{}, "Bemba"},
-	{"ben", []string{"bn"}, "Bengali"},
-	{"ber", []string{}, "Berber (Other)"},
-	{"bho", []string{}, "Bhojpuri"},
-	{"bih", []string{"bh"}, "Bihari"},
-	{"bik", []string{}, "Bikol"},
-	{"bin", []string{}, "Bini"},
-	{"bis", []string{"bi"}, "Bislama"},
-	{"bla", []string{}, "Siksika"},
-	{"bnt", []string{}, "Bantu (Other)"},
-	{"bos", []string{"bs"}, "Bosnian"},
-	{"bra", []string{}, "Braj"},
-	{"bre", []string{"br"}, "Breton"},
-	{"btk", []string{}, "Batak (Indonesia)"},
-	{"bua", []string{}, "Buriat"},
-	{"bug", []string{}, "Buginese"},
-	{"bul", []string{"bg"}, "Bulgarian"},
-	{"bur", []string{"my"}, "Burmese"},
-	{"byn", []string{}, "Blin"},
-	{"cad", []string{}, "Caddo"},
-	{"cai", []string{}, "Central American Indian"},
-	{"car", []string{}, "Carib"},
-	{"cat", []string{"ca"}, "Catalan"},
-	{"cau", []string{}, "Caucasian (Other)"},
-	{"ceb", []string{}, "Cebuano"},
-	{"cel", []string{}, "Celtic (Other)"},
-	{"cha", []string{"ch"}, "Chamorro"},
-	{"chb", []string{}, "Chibcha"},
-	{"che", []string{"ce"}, "Chechen"},
-	{"chg", []string{}, "Chagatai"},
-	{"chi", []string{"zh"}, "Chinese (simplified)"},
-	{"chk", []string{}, "Chuukese"},
-	{"chm", []string{}, "Mari"},
-	{"chn", []string{}, "Chinook jargon"},
-	{"cho", []string{}, "Choctaw"},
-	{"chp", []string{}, "Chipewyan"},
-	{"chr", []string{}, "Cherokee"},
-	{"chu", []string{"cu"}, "Church Slavic"},
-	{"chv", []string{"cv"}, "Chuvash"},
-	{"chy", []string{}, "Cheyenne"},
-	{"cmc", []string{}, "Chamic languages"},
-	{"cop", []string{}, "Coptic"},
-	{"cor", []string{"kw"}, "Cornish"},
-	{"cos", []string{"co"}, "Corsican"},
-	{"cpe", []string{}, "Creoles and pidgins, English"},
-	{"cpf", []string{}, "Creoles and pidgins, French"},
-	{"cpp", []string{}, "Creoles and pidgins, Portug"},
-	{"cre", []string{"cr"}, "Cree"},
-	{"crh", []string{}, "Crimean Tatar"},
-	{"crp", []string{}, "Creoles and pidgins (Other)"},
-	{"csb", []string{}, "Kashubian"},
-	{"cus", []string{}, "Cushitic (Other)"},
-	{"cze", []string{"cs"}, "Czech"},
-	{"dak", []string{}, "Dakota"},
-	{"dan", []string{"da"}, "Danish"},
-	{"dar", []string{}, "Dargwa"},
-	{"day", []string{}, "Dayak"},
-	{"del", []string{}, "Delaware"},
-	{"den", []string{}, "Slave (Athapascan)"},
-	{"dgr", []string{}, "Dogrib"},
-	{"din", []string{}, "Dinka"},
-	{"div", []string{"dv"}, "Divehi"},
-	{"doi", []string{}, "Dogri"},
-	{"dra", []string{}, "Dravidian (Other)"},
-	{"dua", []string{}, "Duala"},
-	{"dum", []string{}, "Dutch, Middle"},
-	{"dut", []string{"nl"}, "Dutch"},
-	{"dyu", []string{}, "Dyula"},
-	{"dzo", []string{"dz"}, "Dzongkha"},
-	{"efi", []string{}, "Efik"},
-	{"egy", []string{}, "Egyptian (Ancient)"},
-	{"eka", []string{}, "Ekajuk"},
-	{"elx", []string{}, "Elamite"},
-	{"eng", []string{"en"}, "English"},
-	{"enm", []string{}, "English, Middle"},
-	{"epo", []string{"eo"}, "Esperanto"},
-	{"est", []string{"et"}, "Estonian"},
-	{"ewe", []string{"ee"}, "Ewe"},
-	{"ewo", []string{}, "Ewondo"},
-	{"fan", []string{}, "Fang"},
-	{"fao", []string{"fo"}, "Faroese"},
-	{"fat", []string{}, "Fanti"},
-	{"fij", []string{"fj"}, "Fijian"},
-	{"fil", []string{}, "Filipino"},
-	{"fin", []string{"fi"}, "Finnish"},
-	{"fiu", []string{}, "Finno-Ugrian (Other)"},
-	{"fon", []string{}, "Fon"},
-	{"fre", []string{"fr"}, "French"},
-	{"frm", []string{}, "French, Middle"},
-	{"fro", []string{}, "French, Old"},
-	{"frc", []string{}, "French (Canadian)"},
-	{"fry", []string{"fy"}, "Frisian"},
-	{"ful", []string{"ff"}, "Fulah"},
-	{"fur", []string{}, "Friulian"},
-	{"gaa", []string{}, "Ga"},
-	{"gay", []string{}, "Gayo"},
-	{"gba", []string{}, "Gbaya"},
-	{"gem", []string{}, "Germanic (Other)"},
-	{"geo", []string{"ka"}, "Georgian"},
-	{"ger", []string{"de"}, "German"},
-	{"gez", []string{}, "Geez"},
-	{"gil", []string{}, "Gilbertese"},
-	{"gla", []string{"gd"}, "Gaelic"},
-	{"gle", []string{"ga"}, "Irish"},
-	{"glg", []string{"gl"}, "Galician"},
-	{"glv", []string{"gv"}, "Manx"},
-	{"gmh", []string{}, "German, Middle High)"},
-	{"goh", []string{}, "German, Old High"},
-	{"gon", []string{}, "Gondi"},
-	{"gor", []string{}, "Gorontalo"},
-	{"got", []string{}, "Gothic"},
-	{"grb", []string{}, "Grebo"},
-	{"grc", []string{}, "Greek, Ancient (to 1453)"},
-	{"ell", []string{"el"}, "Greek"},
-	{"grn", []string{"gn"}, "Guarani"},
-	{"guj", []string{"gu"}, "Gujarati"},
-	{"gwi", []string{}, "Gwich´in"},
-	{"hai", []string{}, "Haida"},
-	{"hat", []string{"ht"}, "Haitian"},
-	{"hau", []string{"ha"}, "Hausa"},
-	{"haw", []string{}, "Hawaiian"},
-	{"heb", []string{"he"}, "Hebrew"},
-	{"her", []string{"hz"}, "Herero"},
-	{"hil", []string{}, "Hiligaynon"},
-	{"him", []string{}, "Himachali"},
-	{"hin", []string{"hi"}, "Hindi"},
-	{"hit", []string{}, "Hittite"},
-	{"hmn", []string{}, "Hmong"},
-	{"hmo", []string{"ho"}, "Hiri Motu"},
-	{"hrv", []string{"hr"}, "Croatian"},
-	{"hun", []string{"hu"}, "Hungarian"},
-	{"hup", []string{}, "Hupa"},
-	{"iba", []string{}, "Iban"},
-	{"ibo", []string{"ig"}, "Igbo"},
-	{"ice", []string{"is"}, "Icelandic"},
-	{"ido", []string{"io"}, "Ido"},
-	{"iii", []string{"ii"}, "Sichuan Yi"},
-	{"ijo", []string{}, "Ijo"},
-	{"iku", []string{"iu"}, "Inuktitut"},
-	{"ile", []string{"ie"}, "Interlingue"},
-	{"ilo", []string{}, "Iloko"},
-	{"ina", []string{"ia"}, "Interlingua"},
-	{"inc", []string{}, "Indic (Other)"},
-	{"ind", []string{"id"}, "Indonesian"},
-	{"ine", []string{}, "Indo-European (Other)"},
-	{"inh", []string{}, "Ingush"},
-	{"ipk", []string{"ik"}, "Inupiaq"},
-	{"ira", []string{}, "Iranian (Other)"},
-	{"iro", []string{}, "Iroquoian languages"},
-	{"ita", []string{"it"}, "Italian"},
-	{"jav", []string{"jv"}, "Javanese"},
-	{"jpn", []string{"ja"}, "Japanese"},
-	{"jpr", []string{}, "Judeo-Persian"},
-	{"jrb", []string{}, "Judeo-Arabic"},
-	{"kaa", []string{}, "Kara-Kalpak"},
-	{"kab", []string{}, "Kabyle"},
-	{"kac", []string{}, "Kachin"},
-	{"kal", []string{"kl"}, "Kalaallisut"},
-	{"kam", []string{}, "Kamba"},
-	{"kan", []string{"kn"}, "Kannada"},
-	{"kar", []string{}, "Karen"},
-	{"kas", []string{"ks"}, "Kashmiri"},
-	{"kau", []string{"kr"}, "Kanuri"},
-	{"kaw", []string{}, "Kawi"},
-	{"kaz", []string{"kk"}, "Kazakh"},
-	{"kbd", []string{}, "Kabardian"},
-	{"kha", []string{}, "Khasi"},
-	{"khi", []string{}, "Khoisan (Other)"},
-	{"khm", []string{"km"}, "Khmer"},
-	{"kho", []string{}, "Khotanese"},
-	{"kik", []string{"ki"}, "Kikuyu"},
-	{"kin", []string{"rw"}, "Kinyarwanda"},
-	{"kir", []string{"ky"}, "Kirghiz"},
-	{"kmb", []string{}, "Kimbundu"},
-	{"kok", []string{}, "Konkani"},
-	{"kom", []string{"kv"}, "Komi"},
-	{"kon", []string{"kg"}, "Kongo"},
-	{"kor", []string{"ko"}, "Korean"},
-	{"kos", []string{}, "Kosraean"},
-	{"kpe", []string{}, "Kpelle"},
-	{"krc", []string{}, "Karachay-Balkar"},
-	{"kro", []string{}, "Kru"},
-	{"kru", []string{}, "Kurukh"},
-	{"kua", []string{"kj"}, "Kuanyama"},
-	{"kum", []string{}, "Kumyk"},
-	{"kur", []string{"ku"}, "Kurdish"},
-	{"kut", []string{}, "Kutenai"},
-	{"lad", []string{}, "Ladino"},
-	{"lah", []string{}, "Lahnda"},
-	{"lam", []string{}, "Lamba"},
-	{"lao", []string{"lo"}, "Lao"},
-	{"lat", []string{"la"}, "Latin"},
-	{"lav", []string{"lv"}, "Latvian"},
-	{"lez", []string{}, "Lezghian"},
-	{"lim", []string{"li"}, "Limburgan"},
-	{"lin", []string{"ln"}, "Lingala"},
-	{"lit", []string{"lt"}, "Lithuanian"},
-	{"lol", []string{}, "Mongo"},
-	{"loz", []string{}, "Lozi"},
-	{"ltz", []string{"lb"}, "Luxembourgish"},
-	{"lua", []string{}, "Luba-Lulua"},
-	{"lub", []string{"lu"}, "Luba-Katanga"},
-	{"lug", []string{"lg"}, "Ganda"},
-	{"lui", []string{}, "Luiseno"},
-	{"lun", []string{}, "Lunda"},
-	{"luo", []string{}, "Luo (Kenya and Tanzania)"},
-	{"lus", []string{}, "Lushai"},
-	{"mac", []string{"mk"}, "Macedonian"},
-	{"mad", []string{}, "Madurese"},
-	{"mag", []string{}, "Magahi"},
-	{"mah", []string{"mh"}, "Marshallese"},
-	{"mai", []string{}, "Maithili"},
-	{"mak", []string{}, "Makasar"},
-	{"mal", []string{"ml"}, "Malayalam"},
-	{"man", []string{}, "Mandingo"},
-	{"mao", []string{"mi"}, "Maori"},
-	{"map", []string{}, "Austronesian (Other)"},
-	{"mar", []string{"mr"}, "Marathi"},
-	{"mas", []string{}, "Masai"},
-	{"may", []string{"ms"}, "Malay"},
-	{"mdf", []string{}, "Moksha"},
-	{"mdr", []string{}, "Mandar"},
-	{"men", []string{}, "Mende"},
-	{"mga", []string{}, "Irish, Middle"},
-	{"mic", []string{}, "Mi'kmaq"},
-	{"min", []string{}, "Minangkabau"},
-	{"mis", []string{}, "Miscellaneous languages"},
-	{"mkh", []string{}, "Mon-Khmer (Other)"},
-	{"mlg", []string{"mg"}, "Malagasy"},
-	{"mlt", []string{"mt"}, "Maltese"},
-	{"mnc", []string{}, "Manchu"},
-	{"mni", []string{"ma"}, "Manipuri"},
-	{"mno", []string{}, "Manobo languages"},
-	{"moh", []string{}, "Mohawk"},
-	{"mol", []string{"mo"}, "Moldavian"},
-	{"mon", []string{"mn"}, "Mongolian"},
-	{"mos", []string{}, "Mossi"},
-	{"mwl", []string{}, "Mirandese"},
-	{"mul", []string{}, "Multiple languages"},
-	{"mun", []string{}, "Munda languages"},
-	{"mus", []string{}, "Creek"},
-	{"mwr", []string{}, "Marwari"},
-	{"myn", []string{}, "Mayan languages"},
-	{"myv", []string{}, "Erzya"},
-	{"nah", []string{}, "Nahuatl"},
-	{"nai", []string{}, "North American Indian"},
-	{"nap", []string{}, "Neapolitan"},
-	{"nau", []string{"na"}, "Nauru"},
-	{"nav", []string{"nv"}, "Navajo"},
-	{"nbl", []string{"nr"}, "Ndebele, South"},
-	{"nde", []string{"nd"}, "Ndebele, North"},
-	{"ndo", []string{"ng"}, "Ndonga"},
-	{"nds", []string{}, "Low German"},
-	{"nep", []string{"ne"}, "Nepali"},
-	{"new", []string{}, "Nepal Bhasa"},
-	{"nia", []string{}, "Nias"},
-	{"nic", []string{}, "Niger-Kordofanian (Other)"},
-	{"niu", []string{}, "Niuean"},
-	{"nno", []string{"nn"}, "Norwegian Nynorsk"},
-	{"nob", []string{"nb"}, "Norwegian Bokmal"},
-	{"nog", []string{}, "Nogai"},
-	{"non", []string{}, "Norse, Old"},
-	{"nor", []string{"no"}, "Norwegian"},
-	{"nso", []string{}, "Northern Sotho"},
-	{"nub", []string{}, "Nubian languages"},
-	{"nwc", []string{}, "Classical Newari"},
-	{"nya", []string{"ny"}, "Chichewa"},
-	{"nym", []string{}, "Nyamwezi"},
-	{"nyn", []string{}, "Nyankole"},
-	{"nyo", []string{}, "Nyoro"},
-	{"nzi", []string{}, "Nzima"},
-	{"oci", []string{"oc"}, "Occitan"},
-	{"oji", []string{"oj"}, "Ojibwa"},
-	{"ori", []string{"or"}, "Oriya"},
-	{"orm", []string{"om"}, "Oromo"},
-	{"osa", []string{}, "Osage"},
-	{"oss", []string{"os"}, "Ossetian"},
-	{"ota", []string{}, "Turkish, Ottoman"},
-	{"oto", []string{}, "Otomian languages"},
-	{"paa", []string{}, "Papuan (Other)"},
-	{"pag", []string{}, "Pangasinan"},
-	{"pal", []string{}, "Pahlavi"},
-	{"pam", []string{}, "Pampanga"},
-	{"pan", []string{"pa"}, "Panjabi"},
-	{"pap", []string{}, "Papiamento"},
-	{"pau", []string{}, "Palauan"},
-	{"peo", []string{}, "Persian, Old"},
-	{"per", []string{"fa"}, "Persian"},
-	{"phi", []string{}, "Philippine (Other)"},
-	{"phn", []string{}, "Phoenician"},
-	{"pli", []string{"pi"}, "Pali"},
-	{"pol", []string{"pl"}, "Polish"},
-	{"pon", []string{}, "Pohnpeian"},
-	{"por", []string{"pt"}, "Portuguese"},
-	{"pra", []string{}, "Prakrit languages"},
-	{"pro", []string{}, "Provençal, Old"},
-	{"pus", []string{"ps"}, "Pushto"},
-	{"que", []string{"qu"}, "Quechua"},
-	{"raj", []string{}, "Rajasthani"},
-	{"rap", []string{}, "Rapanui"},
-	{"rar", []string{}, "Rarotongan"},
-	{"roa", []string{}, "Romance (Other)"},
-	{"roh", []string{"rm"}, "Raeto-Romance"},
-	{"rom", []string{}, "Romany"},
-	{"run", []string{"rn"}, "Rundi"},
-	{"rup", []string{}, "Aromanian"},
-	{"rus", []string{"ru"}, "Russian"},
-	{"sad", []string{}, "Sandawe"},
-	{"sag", []string{"sg"}, "Sango"},
-	{"sah", []string{}, "Yakut"},
-	{"sai", []string{}, "South American Indian (Other)"},
-	{"sal", []string{}, "Salishan languages"},
-	{"sam", []string{}, "Samaritan Aramaic"},
-	{"san", []string{"sa"}, "Sanskrit"},
-	{"sas", []string{}, "Sasak"},
-	{"sat", []string{}, "Santali"},
-	{"scc", []string{"sr"}, "Serbian"},
-	{"scn", []string{}, "Sicilian"},
-	{"sco", []string{}, "Scots"},
-	{"sel", []string{}, "Selkup"},
-	{"sem", []string{}, "Semitic (Other)"},
-	{"sga", []string{}, "Irish, Old"},
-	{"sgn", []string{}, "Sign Languages"},
-	{"shn", []string{}, "Shan"},
-	{"sid", []string{}, "Sidamo"},
-	{"sin", []string{"si"}, "Sinhalese"},
-	{"sio", []string{}, "Siouan languages"},
-	{"sit", []string{}, "Sino-Tibetan (Other)"},
-	{"sla", []string{}, "Slavic (Other)"},
-	{"slo", []string{"sk"}, "Slovak"},
-	{"slv", []string{"sl"}, "Slovenian"},
-	{"sma", []string{}, "Southern Sami"},
-	{"sme", []string{"se"}, "Northern Sami"},
-	{"smi", []string{}, "Sami languages (Other)"},
-	{"smj", []string{}, "Lule Sami"},
-	{"smn", []string{}, "Inari Sami"},
-	{"smo", []string{"sm"}, "Samoan"},
-	{"sms", []string{}, "Skolt Sami"},
-	{"sna", []string{"sn"}, "Shona"},
-	{"snd", []string{"sd"}, "Sindhi"},
-	{"snk", []string{}, "Soninke"},
-	{"sog", []string{}, "Sogdian"},
-	{"som", []string{"so"}, "Somali"},
-	{"son", []string{}, "Songhai"},
-	{"sot", []string{"st"}, "Sotho, Southern"},
-	{"spa", []string{"es"}, "Spanish"},
-	{"srd", []string{"sc"}, "Sardinian"},
-	{"srr", []string{}, "Serer"},
-	{"ssa", []string{}, "Nilo-Saharan (Other)"},
-	{"ssw", []string{"ss"}, "Swati"},
-	{"suk", []string{}, "Sukuma"},
-	{"sun", []string{"su"}, "Sundanese"},
-	{"sus", []string{}, "Susu"},
-	{"sux", []string{}, "Sumerian"},
-	{"swa", []string{"sw"}, "Swahili"},
-	{"swe", []string{"sv"}, "Swedish"},
-	{"syr", []string{"sy"}, "Syriac"},
-	{"tah", []string{"ty"}, "Tahitian"},
-	{"tai", []string{}, "Tai (Other)"},
-	{"tam", []string{"ta"}, "Tamil"},
-	{"tat", []string{"tt"}, "Tatar"},
-	{"tel", []string{"te"}, "Telugu"},
-	{"ter", []string{}, "Tereno"},
-	{"tet", []string{}, "Tetum"},
-	{"tgk", []string{"tg"}, "Tajik"},
-	{"tgl", []string{"tl"}, "Tagalog"},
-	{"tha", []string{"th"}, "Thai"},
-	{"tib", []string{"bo"}, "Tibetan"},
-	{"tig", []string{}, "Tigre"},
-	{"tir", []string{"ti"}, "Tigrinya"},
-	{"tiv", []string{}, "Tiv"},
-	{"tkl", []string{}, "Tokelau"},
-	{"tlh", []string{}, "Klingon"},
-	{"tli", []string{}, "Tlingit"},
-	{"tmh", []string{}, "Tamashek"},
-	{"tog", []string{}, "Tonga (Nyasa)"},
-	{"ton", []string{"to"}, "Tonga (Tonga Islands)"},
-	{"tpi", []string{}, "Tok Pisin"},
-	{"tsi", []string{}, "Tsimshian"},
-	{"tsn", []string{"tn"}, "Tswana"},
-	{"tso", []string{"ts"}, "Tsonga"},
-	{"tuk", []string{"tk"}, "Turkmen"},
-	{"tum", []string{}, "Tumbuka"},
-	{"tup", []string{}, "Tupi languages"},
-	{"tur", []string{"tr"}, "Turkish"},
-	{"tut", []string{}, "Altaic (Other)"},
-	{"tvl", []string{}, "Tuvalu"},
-	{"twi", []string{"tw"}, "Twi"},
-	{"tyv", []string{}, "Tuvinian"},
-	{"udm", []string{}, "Udmurt"},
-	{"uga", []string{}, "Ugaritic"},
-	{"uig", []string{"ug"}, "Uighur"},
-	{"ukr", []string{"uk"}, "Ukrainian"},
-	{"umb", []string{}, "Umbundu"},
-	{"und", []string{}, "Undetermined"},
-	{"urd", []string{"ur"}, "Urdu"},
-	{"uzb", []string{"uz"}, "Uzbek"},
-	{"vai", []string{}, "Vai"},
-	{"ven", []string{"ve"}, "Venda"},
-	{"vie", []string{"vi"}, "Vietnamese"},
-	{"vol", []string{"vo"}, "Volapük"},
-	{"vot", []string{}, "Votic"},
-	{"wak", []string{}, "Wakashan languages"},
-	{"wal", []string{}, "Walamo"},
-	{"war", []string{}, "Waray"},
-	{"was", []string{}, "Washo"},
-	{"wel", []string{"cy"}, "Welsh"},
-	{"wen", []string{}, "Sorbian languages"},
-	{"wln", []string{"wa"}, "Walloon"},
-	{"wol", []string{"wo"}, "Wolof"},
-	{"xal", []string{}, "Kalmyk"},
-	{"xho", []string{"xh"}, "Xhosa"},
-	{"yao", []string{}, "Yao"},
-	{"yap", []string{}, "Yapese"},
-	{"yid", []string{"yi"}, "Yiddish"},
-	{"yor", []string{"yo"}, "Yoruba"},
-	{"ypk", []string{}, "Yupik languages"},
-	{"zap", []string{}, "Zapotec"},
-	{"zen", []string{}, "Zenaga"},
-	{"zha", []string{"za"}, "Zhuang"},
-	{"znd", []string{}, "Zande"},
-	{"zul", []string{"zu"}, "Zulu"},
-	{"zun", []string{}, "Zuni"},
-	{"rum", []string{"ro"}, "Romanian"},
-	{"pob", []string{"pb"}, "Portuguese (BR)"},
-	{"mne", []string{"me"}, "Montenegrin"},
-	{"zht", []string{"zt"}, "Chinese (traditional)"},
-	{"zhe", []string{"ze"}, "Chinese bilingual"},
+var Languages = newLanguages()
+
+// newLanguages builds the languages of ISO 639-2 and their variants
+func newLanguages() Langs {
+	langs := make(Langs, 0, len(isoLanguages)+len(variants))
+	for _, i := range isoLanguages {
+		tag := i.part1
+		if tag == "" {
+			tag = i.part3
+		}
+		if tag == "" {
+			tag = i.part2B
+		}
+		langs = append(langs, Language{ID: i.part2B, Description: i.name, Native: i.native,
+			ISO6391: i.part1, ISO6392B: i.part2B, ISO6392T: i.part2T, ISO6393: i.part3, Tag: tag})
+	}
+	for _, v := range variants {
+		lang := *langs.GetLanguageByCode(v.language)
+		lang.ID, lang.Tag, lang.Description, lang.Native = v.tag, v.tag, v.name, v.native
+		langs = append(langs, lang)
+	}
+	for n := range langs {
+		l := &langs[n]
+		// Variants are only found by their tag
+		if !l.IsVariant() {
+			for _, c := range l.codes() {
+				if !strings.EqualFold(c, l.ID) {
+					l.Alias = append(l.Alias, c)
+				}
+			}
+		}
+		for code, id := range legacyCodes {
+			if id == l.ID {
+				l.Alias = append(l.Alias, code)
+			}
+		}
+		sort.Strings(l.Alias)
+	}
+	return langs
 }
@@ -1,9 +1,11 @@
 package subtitles
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
 )
 
 func TestGetLanguageShouldExists(t *testing.T) {
@@ -46,3 +48,123 @@ func TestGetLanguagesShouldPartiallyExists(t *testing.T) {
 	assert.Equal(t, len(languages), 1, "Should have one language")
 	assert.Equal(t, languages[0].Description, "English", "Should be english")
 }
+
+func TestAvailableShouldUseCapabilitiesOfAllAPIs(t *testing.T) {
+	azerbaijani := Langs{*Languages.GetLanguage("az")}
+	assert.Equal(t, 0, len(azerbaijani.Available(Clients{SubDB(), OpenSubtitles()})))
+	assert.Equal(t, 1, len(azerbaijani.Available(Clients{SubDB(), Addic7ed()})))
+	assert.Equal(t, 0, len(azerbaijani.Available(Clients{Local()})), "Any language APIs should not be considered")
+}
+
+func TestGetLanguageShouldAcceptAllCodesAndNames(t *testing.T) {
+	for _, id := range []string{"fr", "fre", "fra", "French", "français", "FRANÇAIS", "fr-FR"} {
+		language := Languages.GetLanguage(id)
+		if assert.NotNil(t, language, id) {
+			assert.Equal(t, "fre", language.ID, id)
+		}
+	}
+	for id, expected := range map[string]string{
+		"pt-BR": "pt-BR", "pt_br": "pt-BR", "pob": "pt-BR", "pb": "pt-BR", "português (Brasil)": "pt-BR",
+		"es-419": "es-419", "es-MX": "spa", "zh-Hant": "zh-Hant", "zh-Hant-TW": "zh-Hant", "zt": "zh-Hant",
+		"zh": "chi", "zh-Hans": "zh-Hans", "ze": "zh-x-dual",
+		"el": "gre", "ell": "gre", "Ελληνικά": "gre", "scc": "srp", "sr": "srp", "me": "cnr",
+		"ms": "may", "msa": "may", "ml": "mal", "Malayalam": "mal",
+	} {
+		language := Languages.GetLanguage(id)
+		if assert.NotNil(t, language, id) {
+			assert.Equal(t, expected, language.ID, id)
+		}
+	}
+	assert.Nil(t, Languages.GetLanguageByCode("French"), "Names are not codes")
+}
+
+func TestLanguagesShouldHaveConsistentCodes(t *testing.T) {
+	codes, names := map[string]string{}, map[string]string{}
+	unique := func(seen map[string]string, value, id string) {
+		value = strings.ToLower(value)
+		if other, ok := seen[value]; ok && other != id && value != "" {
+			t.Errorf("%v is used by %v and %v", value, other, id)
+		}
+		seen[value] = id
+	}
+	for _, l := range Languages {
+		for _, c := range append(append([]string{}, l.Alias...), l.ID) {
+			unique(codes, c, l.ID)
+		}
+		unique(names, l.Description, l.ID)
+		unique(names, l.Native, l.ID)
+
+		assert.True(t, l.ISO6391 == "" || len(l.ISO6391) == 2, l.ID)
+		assert.Len(t, l.ISO6392B, 3, l.ID)
+		assert.Len(t, l.ISO6392T, 3, l.ID)
+		assert.True(t, l.ISO6393 == "" || len(l.ISO6393) == 3, l.ID)
+		tag, err := language.Parse(l.Tag)
+		if _, unknown := err.(language.ValueError); err != nil && !unknown {
+			t.Errorf("%v has a malformed tag %v", l.ID, l.Tag)
+		}
+		// All the codes known by x/text give the same language as the tag
+		base, _ := tag.Base()
+		for _, c := range []string{l.ISO6391, l.ISO6392B, l.ISO6392T, l.ISO6393} {
+			if code, err := language.Parse(c); c != "" && err == nil {
+				other, _ := code.Base()
+				assert.Equal(t, base.String(), other.String(), l.ID+" "+c)
+			}
+		}
+	}
+}
+
+func TestProviderLanguagesShouldBeKnown(t *testing.T) {
+	for _, api := range APIs.BuiltIn() {
+		for id := range api.GetCapabilities().Languages {
+			l := Languages.GetLanguageByCode(id)
+			if assert.NotNil(t, l, api.GetName()+" "+id) {
+				assert.Equal(t, id, l.ID, api.GetName()+" should use the IDs of the languages")
+			}
+		}
+	}
+	assert.Equal(t, "Malay", addic7edLangs["may"])
+	assert.Equal(t, "Spanish", addic7edLangs["spa"])
+	assert.NotContains(t, addic7edLangs, "mal", "Addic7ed has no Malayalam subtitles")
+	assert.Equal(t, "pob", osLangs["pt-BR"])
+	assert.Equal(t, "zht", osLangs["zh-Hant"])
+	assert.Equal(t, "scc", osLangs["srp"])
+	assert.Equal(t, "ell", osLangs["gre"])
+	assert.Equal(t, "BR_PT", subdlLangs["pt-BR"])
+	assert.Equal(t, "ML", subdlLangs["mal"])
+	assert.Equal(t, "MS", subdlLangs["may"])
+	assert.Equal(t, "pt", subdbLangs["pt-BR"])
+	assert.Equal(t, "ja", jimakuLangs["jpn"])
+}
+
+func TestCoversShouldAcceptVariants(t *testing.T) {
+	portuguese, brazilian := *Languages.GetLanguage("pt"), *Languages.GetLanguage("pt-BR")
+	assert.True(t, portuguese.Covers(brazilian))
+	assert.False(t, brazilian.Covers(portuguese))
+	assert.True(t, brazilian.Covers(brazilian))
+	assert.True(t, brazilian.IsVariant())
+	assert.False(t, portuguese.IsVariant())
+	assert.Equal(t, "pt-BR", brazilian.Tag)
+	assert.Equal(t, "por", brazilian.ISO6392B)
+}
+
+func TestLanguageFromPathShouldNotTakeTitleWordsForLanguages(t *testing.T) {
+	for path, expected := range map[string]string{
+		"/videos/Movie.fr.srt":              "fr",
+		"/videos/Movie.fre.srt":             "fr",
+		"/videos/Movie.pt-BR.srt":           "pt-BR",
+		"/videos/Movie.en.forced.sdh.srt":   "en",
+		"/videos/Movie.en.track4.srt":       "en",
+		"/videos/Her.srt":                   "",
+		"/videos/Iron.Man.srt":              "",
+		"/videos/The.Day.srt":               "",
+		"/videos/Star.War.forced.srt":       "",
+		"/videos/Movie.2019.1080p.x264.srt": "",
+	} {
+		l := LanguageFromPath(path)
+		if expected == "" {
+			assert.Nil(t, l, path)
+		} else if assert.NotNil(t, l, path) {
+			assert.Equal(t, expected, l.Tag, path)
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package subtitles
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/matcornic/subify/common/config"
+	logger "github.com/spf13/jwalterweatherman"
+)
+
+const (
+	subifyFolder   = ".subify"
+	localIndexName = "local-index.json"
+	// localIndexVersion changes with the content of the index, so that older indexes are rebuilt
+	localIndexVersion = 1
+)
+
+// videoExtensions are the extensions of the videos that may sit next to the indexed subtitles
+var videoExtensions = []string{".mkv", ".mp4", ".avi", ".m4v", ".mov", ".wmv", ".mpg", ".mpeg", ".ts", ".webm"}
+
+var hashRegexp = regexp.MustCompile(`^[0-9a-fA-F]{32}$`)
+
+// LocalAPI serves subtitles from local (or network mounted) directories.
+// The directories are indexed by video hash, normalized release name and season/episode
+type LocalAPI struct {
+	Name    string
+	Aliases []string
+}
+
+// Local creates a new API for local directories
+func Local() LocalAPI {
+	return LocalAPI{
+		Name:    "Local",
+		Aliases: []string{"local", "fs", "dir"},
+	}
+}
+
+// localEntry is an indexed subtitle
+type localEntry struct {
+	Path     string `json:"path"`
+	Language string `json:"language"` // ID of the language
+	Tag      string `json:"tag"`      // Language as written in the file name
+	Forced   bool   `json:"forced,omitempty"`
+	Hash     string `json:"hash,omitempty"`
+	Release  string `json:"release"`
+	Title    string `json:"title"`
+	Year     int    `json:"year,omitempty"`
+	Season   int    `json:"season,omitempty"`
+	Episode  int    `json:"episode,omitempty"`
+}
+
+// localIndex is the index of all the subtitles found in the configured directories
+type localIndex struct {
+	Version int          `json:"version"`
+	Dirs    []string     `json:"dirs"`
+	Entries []localEntry `json:"entries"`
+}
+
+// Download copies the subtitle found in the local directories next to the video
+func (s LocalAPI) Download(videoPath string, language Language) (subtitlePath string, err error) {
+	return s.download(videoPath, language, false)
+}
+
+// DownloadForced copies the forced subtitle found in the local directories (Movie.en.forced.srt) next to the video
+func (s LocalAPI) DownloadForced(videoPath string, language Language) (subtitlePath string, err error) {
+	return s.download(videoPath, language, true)
+}
+
+// download copies the full or forced subtitle found in the local directories next to the video
+func (s LocalAPI) download(videoPath string, language Language, forced bool) (subtitlePath string, err error) {
+	if len(config.LocalDirs) == 0 {
+		return "", errors.New("No local directory configured. Set 'local.dirs' in your configuration file")
+	}
+	index, err := loadLocalIndex(config.LocalDirs)
+	if err != nil {
+		return "", err
+	}
+
+	entry := index.find(videoPath, language, forced)
+	if entry == nil {
+		return "", errors.New("Subtitle not stored in local directories")
+	}
+
+	content, err := ioutil.ReadFile(entry.Path)
+	if err != nil {
+		return "", fmt.Errorf("Can't read the file %v because of : %v", entry.Path, err)
+	}
+	subtitlePath = subtitlePathFor(videoPath, subtitleTag(language.Tag, forced), strings.ToLower(filepath.Ext(entry.Path)))
+	if err = ioutil.WriteFile(subtitlePath, content, 0644); err != nil {
+		return "", fmt.Errorf("Can't save the file %v because of : %v", subtitlePath, err)
+	}
+	logger.INFO.Println("Original subtitle :", entry.Path)
+
+	return subtitlePath, nil
+}
+
+// Upload uploads the subtitle to local directories, for the given video
+func (s LocalAPI) Upload(subtitlePath string, language Language, videoPath string) error {
+	return errors.New("Not yet implemented")
+}
+
+// GetName returns the name of the api
+func (s LocalAPI) GetName() string {
+	return s.Name
+}
+
+// GetAliases returns aliases to identify this API
+func (s LocalAPI) GetAliases() []string {
+	return s.Aliases
+}
+
+// GetCapabilities returns what this API is able to do
+func (s LocalAPI) GetCapabilities() Capabilities {
+	return Capabilities{
+		SearchModes: []SearchMode{SearchByHash, SearchByName},
+		Forced:      true,
+	}
+}
+
+// subifyPath gives the path of a file in the Subify folder of the user ($HOME/.subify)
+func subifyPath(path ...string) (string, error) {
+	usr, err := user.Current()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(append([]string{usr.HomeDir, subifyFolder}, path...)...), nil
+}
+
+// IndexLocal rebuilds the index of the configured local directories
+func IndexLocal() (count int, err error) {
+	if len(config.LocalDirs) == 0 {
+		return 0, errors.New("No local directory configured. Set 'local.dirs' in your configuration file")
+	}
+	index, err := buildLocalIndex(config.LocalDirs)
+	if err != nil {
+		return 0, err
+	}
+	return len(index.Entries), index.save()
+}
+
+// loadLocalIndex reads the index from the disk. It is rebuilt when missing, outdated or when directories changed
+func loadLocalIndex(dirs []string) (*localIndex, error) {
+	indexPath, err := subifyPath(localIndexName)
+	if err != nil {
+		return nil, err
+	}
+	index := &localIndex{}
+	if content, err := ioutil.ReadFile(indexPath); err == nil && json.Unmarshal(content, index) == nil &&
+		index.Version == localIndexVersion && strings.Join(index.Dirs, ",") == strings.Join(dirs, ",") {
+		return index, nil
+	}
+
+	logger.INFO.Println("Indexing local directories", dirs)
+	index, err = buildLocalIndex(dirs)
+	if err != nil {
+		return nil, err
+	}
+	return index, index.save()
+}
+
+// save writes the index to the disk
+func (i *localIndex) save() error {
+	indexPath, err := subifyPath(localIndexName)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(indexPath), 0755); err != nil {
+		return err
+	}
+	content, err := json.MarshalIndent(i, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(indexPath, content, 0644)
+}
+
+// buildLocalIndex walks through the directories to index every subtitle having a language in its name
+// (ex: Movie.en.srt, Show.S01E02.fre.ass)
+func buildLocalIndex(dirs []string) (*localIndex, error) {
+	index := &localIndex{Version: localIndexVersion, Dirs: dirs}
+	for _, dir := range dirs {
+		err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				logger.WARN.Println("Can't index", path, ":", err)
+				return nil
+			}
+			if info.IsDir() || !isSubtitleFile(path) {
+				return nil
+			}
+			if entry, ok := newLocalEntry(path); ok {
+				index.Entries = append(index.Entries, entry)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Can't index directory %v because of : %v", dir, err)
+		}
+	}
+	return index, nil
+}
+
+// newLocalEntry describes a subtitle file. Files without a known language in their name are ignored.
+// Forced subtitles are named like Movie.en.forced.srt
+func newLocalEntry(path string) (localEntry, bool) {
+	base := strings.TrimSuffix(path, filepath.Ext(path))
+	forced := strings.EqualFold(filepath.Ext(base), ".forced")
+	if forced {
+		base = strings.TrimSuffix(base, filepath.Ext(base))
+	}
+	tag := strings.TrimPrefix(filepath.Ext(base), ".")
+	lang := Languages.GetLanguage(tag)
+	if tag == "" || lang == nil {
+		return localEntry{}, false
+	}
+	base = strings.TrimSuffix(base, "."+tag)
+
+	release := ParseRelease(filepath.Base(base))
+	entry := localEntry{
+		Path:     path,
+		Language: lang.ID,
+		Tag:      tag,
+		Forced:   forced,
+		Release:  NormalizeName(release.Name),
+		Title:    NormalizeName(release.Title),
+		Year:     release.Year,
+		Season:   release.Season,
+		Episode:  release.Episode,
+	}
+
+	// The hash is either the name of the subtitle or the hash of the video next to it
+	if hashRegexp.MatchString(filepath.Base(base)) {
+		entry.Hash = strings.ToLower(filepath.Base(base))
+	} else {
+		for _, ext := range videoExtensions {
+			if hash, err := getHashOfVideo(base + ext); err == nil {
+				entry.Hash = hash
+				break
+			}
+		}
+	}
+	return entry, true
+}
+
+// find gives the best indexed full or forced subtitle for the video: same hash first, then same release name,
+// then same title and episode (or year for movies)
+func (i *localIndex) find(videoPath string, language Language, forced bool) *localEntry {
+	hash, _ := getHashOfVideo(videoPath)
+	video := ParseRelease(videoPath)
+	release := NormalizeName(video.Name)
+	title := NormalizeName(video.Title)
+
+	var byRelease, byTitle *localEntry
+	for n := range i.Entries {
+		e := &i.Entries[n]
+		if e.Language != language.ID || e.Forced != forced {
+			continue
+		}
+		if _, err := os.Stat(e.Path); err != nil {
+			continue
+		}
+		switch {
+		case hash != "" && e.Hash == hash:
+			return e
+		case byRelease == nil && e.Release == release:
+			byRelease = e
+		case byTitle == nil && title != "" && e.Title == title &&
+			e.Season == video.Season && e.Episode == video.Episode &&
+			(video.IsEpisode() || video.Year == 0 || e.Year == 0 || e.Year == video.Year):
+			byTitle = e
+		}
+	}
+	if byRelease != nil {
+		return byRelease
+	}
+	return byTitle
+}
@@ -0,0 +1,60 @@
+package subtitles
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func createFiles(t *testing.T, dir string, names ...string) {
+	for _, name := range names {
+		assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, name), make([]byte, 128*1024), 0644))
+	}
+}
+
+func TestLocalIndexShouldFindByReleaseNameAndEpisode(t *testing.T) {
+	dir, err := ioutil.TempDir("", "subify")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	createFiles(t, dir, "Show.S01E02.720p.HDTV-GRP.en.srt", "Show.S01E03.1080p.WEB-OTHER.en.srt", "Movie.1999.fr.srt", "Movie.1999.en.forced.srt", "notes.srt")
+
+	index, err := buildLocalIndex([]string{dir})
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(index.Entries))
+
+	english := *Languages.GetLanguage("en")
+	entry := index.find("/videos/Show.S01E02.720p.HDTV-GRP.mkv", english, false)
+	assert.NotNil(t, entry)
+	assert.Equal(t, "Show.S01E02.720p.HDTV-GRP.en.srt", filepath.Base(entry.Path))
+
+	entry = index.find("/videos/Show.S01E03.720p.HDTV-GRP.mkv", english, false)
+	assert.NotNil(t, entry)
+	assert.Equal(t, "Show.S01E03.1080p.WEB-OTHER.en.srt", filepath.Base(entry.Path))
+
+	assert.Nil(t, index.find("/videos/Show.S01E04.720p.HDTV-GRP.mkv", english, false))
+	assert.Nil(t, index.find("/videos/Movie.1999.mkv", english, false))
+	assert.NotNil(t, index.find("/videos/Movie.1999.mkv", *Languages.GetLanguage("fr"), false))
+
+	entry = index.find("/videos/Movie.1999.mkv", english, true)
+	assert.NotNil(t, entry)
+	assert.Equal(t, "Movie.1999.en.forced.srt", filepath.Base(entry.Path))
+}
+
+func TestLocalIndexShouldFindByHash(t *testing.T) {
+	dir, err := ioutil.TempDir("", "subify")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	createFiles(t, dir, "Renamed.mkv", "Renamed.en.srt")
+
+	index, err := buildLocalIndex([]string{dir})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(index.Entries))
+
+	createFiles(t, dir, "Totally different name.avi")
+	entry := index.find(filepath.Join(dir, "Totally different name.avi"), *Languages.GetLanguage("en"), false)
+	assert.NotNil(t, entry)
+	assert.Equal(t, "Renamed.en.srt", filepath.Base(entry.Path))
+}
@@ -0,0 +1,110 @@
+package subtitles
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/matcornic/subify/subtitles/container"
+	"github.com/matcornic/subify/subtitles/format"
+)
+
+// MuxSubtitle is a subtitle file to add to a video as a new track
+type MuxSubtitle struct {
+	Path            string
+	Language        *Language // Undetermined when nil
+	Default         bool      // Selected by players when nothing else is asked
+	Forced          bool      // Only for the foreign parts of the video
+	HearingImpaired bool
+}
+
+// NewMuxSubtitle describes a subtitle file from its name: Movie.en.forced.srt is a forced English subtitle,
+// Movie.en.sdh.srt an English subtitle for the hearing impaired
+func NewMuxSubtitle(subtitlePath string) MuxSubtitle {
+	s := MuxSubtitle{Path: subtitlePath, Language: LanguageFromPath(subtitlePath)}
+	name := strings.TrimSuffix(filepath.Base(subtitlePath), filepath.Ext(subtitlePath))
+	for _, part := range strings.Split(strings.ToLower(name), ".")[1:] {
+		switch part {
+		case "forced":
+			s.Forced = true
+		case "sdh", "cc":
+			s.HearingImpaired = true
+		}
+	}
+	return s
+}
+
+// matroskaLanguage gives the ISO 639-2/B code and the BCP 47 tag of a language for Matroska tracks
+func matroskaLanguage(l *Language) (string, string) {
+	if l == nil {
+		return "und", ""
+	}
+	return l.ISO6392B, l.Tag
+}
+
+// MuxedPath gives the path of the video with the added subtitles, next to the original one (Movie.muxed.mkv)
+func MuxedPath(videoPath string) string {
+	return strings.TrimSuffix(videoPath, filepath.Ext(videoPath)) + ".muxed.mkv"
+}
+
+// Mux adds subtitles to a Matroska video as new tracks, and gives the path of the new video.
+// The video is written next to the original one unless an output is given. It is written aside, then renamed,
+// so that the output is never left half written, even when it replaces the original video
+func Mux(videoPath string, subs []MuxSubtitle, output string) (string, error) {
+	if output == "" {
+		output = MuxedPath(videoPath)
+	}
+	var tracks []container.NewTrack
+	for _, sub := range subs {
+		s, f, err := format.ReadFile(sub.Path, format.Options{})
+		if err != nil {
+			return "", err
+		}
+		t, err := container.SubtitleTrack(s, f)
+		if err != nil {
+			return "", fmt.Errorf("Can't add the subtitle %v because of : %v", sub.Path, err)
+		}
+		t.Language, t.LanguageBCP47 = matroskaLanguage(sub.Language)
+		t.Default, t.Forced, t.HearingImpaired = sub.Default, sub.Forced, sub.HearingImpaired
+		tracks = append(tracks, t)
+	}
+
+	video, err := os.Open(videoPath)
+	if err != nil {
+		return "", fmt.Errorf("Can't open the file %v because of : %v", videoPath, err)
+	}
+	defer video.Close()
+	info, err := video.Stat()
+	if err != nil {
+		return "", fmt.Errorf("Can't open the file %v because of : %v", videoPath, err)
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(output), "."+filepath.Base(output)+".*.tmp")
+	if err != nil {
+		return "", fmt.Errorf("Can't create the file %v because of : %v", output, err)
+	}
+	defer os.Remove(tmp.Name())
+	err = container.Mux(video, info.Size(), tmp, tracks)
+	if err == container.ErrUnknownContainer {
+		err = errors.New("Only Matroska videos (MKV, WebM) can get new subtitle tracks")
+	}
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", fmt.Errorf("Can't write the video %v because of : %v", output, err)
+	}
+	if err := os.Chmod(tmp.Name(), info.Mode().Perm()); err != nil {
+		return "", fmt.Errorf("Can't write the video %v because of : %v", output, err)
+	}
+	if err := os.Rename(tmp.Name(), output); err != nil {
+		return "", fmt.Errorf("Can't write the video %v because of : %v", output, err)
+	}
+	return output, nil
+}
@@ -2,7 +2,7 @@ package subtitles
 
 import (
 	"errors"
-	"path"
+	"sort"
 
 	"github.com/oz/osdb"
 	logger "github.com/spf13/jwalterweatherman"
@@ -34,8 +34,8 @@ var osLangs = map[string]string{
 	"fre": "fre",
 	"geo": "geo",
 	"ger": "ger",
+	"gre": "ell",
 	"glg": "glg",
-	"ell": "ell",
 	"heb": "heb",
 	"hin": "hin",
 	"hrv": "hrv",
@@ -57,7 +57,6 @@ var osLangs = map[string]string{
 	"pol": "pol",
 	"por": "por",
 	"rus": "rus",
-	"scc": "scc",
 	"sin": "sin",
 	"slo": "slo",
 	"slv": "slv",
@@ -71,9 +70,13 @@ var osLangs = map[string]string{
 	"ukr": "ukr",
 	"vie": "vie",
 	"rum": "rum",
-	"pob": "pob",
-	"zht": "zht",
-	"zhe": "zhe",
+	"srp": "scc",
+
+	// Regional and script variants
+	"pt-BR":     "pob",
+	"zh-Hans":   "chi",
+	"zh-Hant":   "zht",
+	"zh-x-dual": "zhe",
 }
 
 // OSDBAPI entry point
@@ -92,6 +95,16 @@ func OpenSubtitles() OSDBAPI {
 
 // Download downloads the OpenSubtitles subtitle from a video
 func (s OSDBAPI) Download(videoPath string, language Language) (subtitlePath string, err error) {
+	return s.download(videoPath, language, false)
+}
+
+// DownloadForced downloads the OpenSubtitles subtitle translating the foreign parts of a video only
+func (s OSDBAPI) DownloadForced(videoPath string, language Language) (subtitlePath string, err error) {
+	return s.download(videoPath, language, true)
+}
+
+// download downloads the best full or forced subtitle of a video
+func (s OSDBAPI) download(videoPath string, language Language, forced bool) (subtitlePath string, err error) {
 	c, err := osdb.NewClient()
 	if err != nil {
 		return "", err
@@ -115,13 +128,13 @@ func (s OSDBAPI) Download(videoPath string, language Language) (subtitlePath str
 	}
 
 	// Keep best one
-	best := subs.Best()
+	best := bestOSDBSubtitle(subs, configuredHearingImpaired(), forced)
 	if best == nil {
 		return "", errors.New("Did not find best subtitle for this video")
 	}
 
 	// Saving to disk
-	subtitlePath = videoPath[0:len(videoPath)-len(path.Ext(videoPath))] + "." + lang + ".srt"
+	subtitlePath = subtitlePathFor(videoPath, subtitleTag(language.Tag, forced), ".srt")
 	if err := c.DownloadTo(best, subtitlePath); err != nil {
 		return "", err
 	}
@@ -131,6 +144,22 @@ func (s OSDBAPI) Download(videoPath string, language Language) (subtitlePath str
 	return subtitlePath, nil
 }
 
+// bestOSDBSubtitle finds the most downloaded full or forced (foreign parts only) subtitle matching the hearing impaired preference
+func bestOSDBSubtitle(subs osdb.Subtitles, hi HearingImpaired, forced bool) *osdb.Subtitle {
+	sort.Stable(osdb.ByDownloads(subs))
+	var best *osdb.Subtitle
+	for i := range subs {
+		flagged := subs[i].SubHearingImpaired == "1"
+		if !hi.Accepts(flagged) || (subs[i].SubForeignPartsOnly == "1") != forced {
+			continue
+		}
+		if best == nil || hi.Score(flagged) > hi.Score(best.SubHearingImpaired == "1") {
+			best = &subs[i]
+		}
+	}
+	return best
+}
+
 // Upload uploads the subtitle to OpenSubtitles, for the given video
 func (s OSDBAPI) Upload(subtitlePath string, language Language, videoPath string) error {
 	return errors.New("Not yet implemented")
@@ -145,3 +174,13 @@ func (s OSDBAPI) GetName() string {
 func (s OSDBAPI) GetAliases() []string {
 	return s.Aliases
 }
+
+// GetCapabilities returns what this API is able to do
+func (s OSDBAPI) GetCapabilities() Capabilities {
+	return Capabilities{
+		Languages:       osLangs,
+		SearchModes:     []SearchMode{SearchByHash},
+		HearingImpaired: true,
+		Forced:          true,
+	}
+}
@@ -0,0 +1,402 @@
+package subtitles
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/matcornic/subify/subtitles/format"
+	"github.com/oz/osdb"
+	logger "github.com/spf13/jwalterweatherman"
+)
+
+// Provider plugins are executables named subify-provider-<name>, found in $HOME/.subify/plugins or in the PATH.
+//
+// Subify runs the executable once per call, writes one JSON request on its standard input and reads one
+// JSON response on its standard output. The standard error is only used in error messages.
+//
+//	Request:  {"protocol": 1, "method": "<method>", "params": {...}}
+//	Response: {"result": {...}} or {"error": {"code": "<code>", "message": "<message>"}}
+//
+// Methods:
+//   - capabilities: no params. Result is {"name", "aliases", "languages", "search_modes", "upload",
+//     "hearing_impaired", "forced", "auth"}
+//   - search: params are {"video", "language", "forced"}. Result is {"subtitles": [{"id", "name", "format", "score",
+//     "hearing_impaired", "forced"}]}. Forced is only asked to the plugins declaring it, and subtitles named
+//     like Movie.forced.srt are forced too
+//   - fetch: params are {"id", "video", "language"}. Result is {"name", "format", "content"} (content in base64)
+//   - upload: params are {"name", "format", "content", "video", "language"}. Result is {}
+//
+// Error codes are not_found, unsupported, auth, unavailable and internal.
+const (
+	pluginPrefix          = "subify-provider-"
+	pluginFolder          = "plugins"
+	pluginProtocolVersion = 1
+)
+
+// Timeouts of the plugin calls
+var (
+	pluginCapabilitiesTimeout = 5 * time.Second
+	pluginSearchTimeout       = 30 * time.Second
+	pluginFetchTimeout        = 60 * time.Second
+)
+
+// PluginAPI wraps an external provider executable as a Client
+type PluginAPI struct {
+	Name         string
+	Aliases      []string
+	Path         string // Path of the executable
+	Capabilities Capabilities
+}
+
+// pluginRequest is the JSON written to the standard input of the plugin
+type pluginRequest struct {
+	Protocol int         `json:"protocol"`
+	Method   string      `json:"method"`
+	Params   interface{} `json:"params"`
+}
+
+// pluginResponse is the JSON read from the standard output of the plugin
+type pluginResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *pluginError    `json:"error"`
+}
+
+// pluginError is an error reported by the plugin
+type pluginError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// pluginCapabilities describes what the plugin can do
+type pluginCapabilities struct {
+	Name            string   `json:"name"`
+	Aliases         []string `json:"aliases"`
+	Languages       []string `json:"languages"`
+	SearchModes     []string `json:"search_modes"`
+	Upload          bool     `json:"upload"`
+	HearingImpaired bool     `json:"hearing_impaired"`
+	Forced          bool     `json:"forced"`
+	Auth            bool     `json:"auth"`
+}
+
+// pluginVideo describes the video to the plugin
+type pluginVideo struct {
+	Path     string `json:"path"`
+	Name     string `json:"name"`
+	Size     int64  `json:"size"`
+	Hash     string `json:"hash,omitempty"`      // SubDB hash
+	OSDBHash string `json:"osdb_hash,omitempty"` // OpenSubtitles hash
+	Title    string `json:"title,omitempty"`
+	Year     int    `json:"year,omitempty"`
+	Season   int    `json:"season,omitempty"`
+	Episode  int    `json:"episode,omitempty"`
+}
+
+// pluginLanguage describes the language to the plugin
+type pluginLanguage struct {
+	ID          string   `json:"id"`
+	Alias       []string `json:"alias"`
+	Description string   `json:"description"`
+}
+
+// pluginSubtitle is a subtitle found by the plugin
+type pluginSubtitle struct {
+	ID      string  `json:"id"`
+	Name    string  `json:"name"`
+	Format  string  `json:"format"`
+	Score   float64 `json:"score"`
+	Content string  `json:"content,omitempty"`
+
+	HearingImpaired bool `json:"hearing_impaired,omitempty"`
+	Forced          bool `json:"forced,omitempty"` // Only translates the foreign parts of the video
+}
+
+// DiscoverPlugins finds the provider plugins in $HOME/.subify/plugins and in the PATH.
+// When several plugins have the same file name, the first found is used.
+func DiscoverPlugins() (plugins Clients) {
+	var dirs []string
+	if dir, err := subifyPath(pluginFolder); err == nil {
+		dirs = append(dirs, dir)
+	}
+	dirs = append(dirs, filepath.SplitList(os.Getenv("PATH"))...)
+
+	seen := make(map[string]bool)
+	for _, dir := range dirs {
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, f := range files {
+			name := strings.TrimSuffix(f.Name(), ".exe")
+			if !strings.HasPrefix(name, pluginPrefix) || seen[name] || !isExecutable(f) {
+				continue
+			}
+			seen[name] = true
+			plugins = append(plugins, NewPlugin(filepath.Join(dir, f.Name())))
+		}
+	}
+	return
+}
+
+// NewPlugin creates the Client of a plugin executable, asking for its capabilities
+func NewPlugin(path string) PluginAPI {
+	name := strings.TrimPrefix(strings.TrimSuffix(filepath.Base(path), ".exe"), pluginPrefix)
+	p := PluginAPI{Name: name, Aliases: []string{name}, Path: path}
+
+	var caps pluginCapabilities
+	if err := p.call("capabilities", struct{}{}, &caps, pluginCapabilitiesTimeout); err != nil {
+		logger.WARN.Println("Can't get capabilities of plugin", path, ":", err)
+		return p
+	}
+	if caps.Name != "" {
+		p.Name = caps.Name
+	}
+	for _, alias := range caps.Aliases {
+		if !strings.EqualFold(alias, name) {
+			p.Aliases = append(p.Aliases, alias)
+		}
+	}
+	p.Capabilities = Capabilities{
+		Upload:          caps.Upload,
+		HearingImpaired: caps.HearingImpaired,
+		Forced:          caps.Forced,
+		NeedsAuth:       caps.Auth,
+	}
+	for _, m := range caps.SearchModes {
+		p.Capabilities.SearchModes = append(p.Capabilities.SearchModes, SearchMode(m))
+	}
+	// Declared languages can be any id or alias known by Subify
+	if len(caps.Languages) > 0 {
+		p.Capabilities.Languages = make(map[string]string)
+		for _, code := range caps.Languages {
+			if lang := Languages.GetLanguage(code); lang != nil {
+				p.Capabilities.Languages[lang.ID] = code
+			}
+		}
+	}
+	return p
+}
+
+// isExecutable tells if the file can be run
+func isExecutable(f os.FileInfo) bool {
+	if f.IsDir() {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		return strings.EqualFold(filepath.Ext(f.Name()), ".exe")
+	}
+	return f.Mode()&0111 != 0
+}
+
+// Download downloads the subtitle found by the plugin
+func (p PluginAPI) Download(videoPath string, language Language) (subtitlePath string, err error) {
+	return p.download(videoPath, language, false)
+}
+
+// DownloadForced downloads the forced subtitle found by the plugin, when it declares to flag them
+func (p PluginAPI) DownloadForced(videoPath string, language Language) (subtitlePath string, err error) {
+	if !p.Capabilities.Forced {
+		return "", fmt.Errorf("%v does not search forced subtitles", p.Name)
+	}
+	return p.download(videoPath, language, true)
+}
+
+// download downloads the best full or forced subtitle found by the plugin
+func (p PluginAPI) download(videoPath string, language Language, forced bool) (subtitlePath string, err error) {
+	if !p.Capabilities.Supports(language) {
+		return "", fmt.Errorf("Language exists but is not available for %v", p.Name)
+	}
+	video, err := newPluginVideo(videoPath)
+	if err != nil {
+		return "", err
+	}
+	lang := newPluginLanguage(language)
+
+	var found struct {
+		Subtitles []pluginSubtitle `json:"subtitles"`
+	}
+	params := map[string]interface{}{"video": video, "language": lang}
+	if forced {
+		params["forced"] = true
+	}
+	if err := p.call("search", params, &found, pluginSearchTimeout); err != nil {
+		return "", err
+	}
+	hi := configuredHearingImpaired()
+	var best *pluginSubtitle
+	for i, s := range found.Subtitles {
+		if !hi.Accepts(s.HearingImpaired) || (s.Forced || isForcedName(s.Name)) != forced {
+			continue
+		}
+		if best == nil || s.Score+hi.Score(s.HearingImpaired) > best.Score+hi.Score(best.HearingImpaired) {
+			best = &found.Subtitles[i]
+		}
+	}
+	if best == nil {
+		return "", fmt.Errorf("Subtitle not stored by %v", p.Name)
+	}
+
+	var fetched pluginSubtitle
+	params = map[string]interface{}{"id": best.ID, "video": video, "language": lang}
+	if err := p.call("fetch", params, &fetched, pluginFetchTimeout); err != nil {
+		return "", err
+	}
+	content, err := base64.StdEncoding.DecodeString(fetched.Content)
+	if err != nil {
+		return "", fmt.Errorf("%v sent a subtitle which is not encoded in base64: %v", p.Name, err)
+	}
+
+	// The format is part of the path, only known ones are kept
+	f, err := format.ParseFormat(fetched.Format)
+	if err != nil {
+		f = format.SRT
+	}
+	subtitlePath = subtitlePathFor(videoPath, subtitleTag(language.Tag, forced), f.Extension())
+	if err = ioutil.WriteFile(subtitlePath, content, 0644); err != nil {
+		return "", fmt.Errorf("Can't save the file %v because of : %v", subtitlePath, err)
+	}
+	logger.INFO.Println("Original name of subtitle :", best.Name)
+
+	return subtitlePath, nil
+}
+
+// Upload uploads the subtitle with the plugin, for the given video
+func (p PluginAPI) Upload(subtitlePath string, language Language, videoPath string) error {
+	if !p.Capabilities.Upload {
+		return fmt.Errorf("Upload is not supported by %v", p.Name)
+	}
+	video, err := newPluginVideo(videoPath)
+	if err != nil {
+		return err
+	}
+	content, err := ioutil.ReadFile(subtitlePath)
+	if err != nil {
+		return fmt.Errorf("Can't read the file %v because of : %v", subtitlePath, err)
+	}
+	params := map[string]interface{}{
+		"name":     filepath.Base(subtitlePath),
+		"format":   strings.TrimPrefix(strings.ToLower(filepath.Ext(subtitlePath)), "."),
+		"content":  base64.StdEncoding.EncodeToString(content),
+		"video":    video,
+		"language": newPluginLanguage(language),
+	}
+	return p.call("upload", params, nil, pluginFetchTimeout)
+}
+
+// GetName returns the name of the api
+func (p PluginAPI) GetName() string {
+	return p.Name
+}
+
+// GetAliases returns aliases to identify this API
+func (p PluginAPI) GetAliases() []string {
+	return p.Aliases
+}
+
+// GetCapabilities returns what this API is able to do, as declared by the plugin
+func (p PluginAPI) GetCapabilities() Capabilities {
+	return p.Capabilities
+}
+
+// call runs the plugin with one request and decodes its result
+func (p PluginAPI) call(method string, params interface{}, result interface{}, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	request, err := json.Marshal(pluginRequest{Protocol: pluginProtocolVersion, Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, p.Path)
+	cmd.Stdin = bytes.NewReader(request)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	runErr := cmd.Run()
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("%v did not answer within %v", p.Name, timeout)
+	}
+	var response pluginResponse
+	if err := json.Unmarshal(stdout.Bytes(), &response); err != nil {
+		if runErr != nil {
+			return fmt.Errorf("%v failed (%v): %v", p.Name, runErr, strings.TrimSpace(stderr.String()))
+		}
+		return fmt.Errorf("%v answered something which is not JSON: %v", p.Name, err)
+	}
+	if response.Error != nil {
+		return response.Error.toError(p.Name)
+	}
+	if runErr != nil {
+		return fmt.Errorf("%v failed (%v): %v", p.Name, runErr, strings.TrimSpace(stderr.String()))
+	}
+	if result != nil {
+		if err := json.Unmarshal(response.Result, result); err != nil {
+			return fmt.Errorf("%v answered an unexpected result: %v", p.Name, err)
+		}
+	}
+	return nil
+}
+
+// toError maps the error codes of the protocol to the messages used by the other APIs
+func (e pluginError) toError(name string) error {
+	message := e.Message
+	if message == "" {
+		message = "no details"
+	}
+	switch e.Code {
+	case "not_found":
+		return fmt.Errorf("Subtitle not stored by %v", name)
+	case "unsupported":
+		return fmt.Errorf("Not supported by %v: %v", name, message)
+	case "auth":
+		return fmt.Errorf("%v refused the credentials: %v", name, message)
+	case "unavailable":
+		return fmt.Errorf("Can't reach %v. Are you connected to the Internet ? %v", name, message)
+	default:
+		return errors.New(name + " failed: " + message)
+	}
+}
+
+// newPluginVideo describes the video for plugins
+func newPluginVideo(videoPath string) (pluginVideo, error) {
+	info, err := os.Stat(videoPath)
+	if err != nil {
+		return pluginVideo{}, fmt.Errorf("Can't open file %v because of : %v ", videoPath, err)
+	}
+	release := ParseRelease(videoPath)
+	video := pluginVideo{
+		Path:    videoPath,
+		Name:    filepath.Base(videoPath),
+		Size:    info.Size(),
+		Title:   release.Title,
+		Year:    release.Year,
+		Season:  release.Season,
+		Episode: release.Episode,
+	}
+	video.Hash, _ = getHashOfVideo(videoPath)
+	if hash, err := osdb.Hash(videoPath); err == nil {
+		video.OSDBHash = fmt.Sprintf("%016x", hash)
+	}
+	return video, nil
+}
+
+// newPluginLanguage describes the language for plugins
+func newPluginLanguage(language Language) pluginLanguage {
+	alias := language.Alias
+	if alias == nil {
+		alias = []string{}
+	}
+	return pluginLanguage{ID: language.ID, Alias: alias, Description: language.Description}
+}
@@ -0,0 +1,70 @@
+package subtitles
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakePlugin answers like a provider knowing one English subtitle ("Hello" in base64)
+const fakePlugin = `#!/bin/sh
+request=$(cat)
+case "$request" in
+*'"capabilities"'*) echo '{"result": {"name": "Fake", "aliases": ["fk"], "languages": ["eng"]}}' ;;
+*'"search"'*) echo '{"result": {"subtitles": [{"id": "1", "name": "low", "score": 0.1}, {"id": "2", "name": "high", "score": 0.9}]}}' ;;
+*'"fetch"'*'"id":"2"'*) echo '{"result": {"name": "high.vtt", "format": "vtt", "content": "SGVsbG8="}}' ;;
+*) echo '{"error": {"code": "not_found", "message": "nothing"}}' ;;
+esac
+`
+
+func TestPluginShouldDownloadBestSubtitle(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Shell plugins are not supported on Windows")
+	}
+	dir, err := ioutil.TempDir("", "subify")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "subify-provider-fake"), []byte(fakePlugin), 0755))
+	createFiles(t, dir, "Movie.mkv")
+
+	plugin := NewPlugin(filepath.Join(dir, "subify-provider-fake"))
+	assert.Equal(t, "Fake", plugin.GetName())
+	assert.Equal(t, []string{"fake", "fk"}, plugin.GetAliases())
+
+	_, err = plugin.Download(filepath.Join(dir, "Movie.mkv"), *Languages.GetLanguage("fr"))
+	assert.NotNil(t, err)
+
+	_, err = plugin.DownloadForced(filepath.Join(dir, "Movie.mkv"), *Languages.GetLanguage("en"))
+	assert.EqualError(t, err, "Fake does not search forced subtitles")
+
+	subtitlePath, err := plugin.Download(filepath.Join(dir, "Movie.mkv"), *Languages.GetLanguage("en"))
+	assert.Nil(t, err)
+	assert.Equal(t, filepath.Join(dir, "Movie.en.vtt"), subtitlePath)
+	content, err := ioutil.ReadFile(subtitlePath)
+	assert.Nil(t, err)
+	assert.Equal(t, "Hello", string(content))
+
+	err = plugin.Upload(subtitlePath, *Languages.GetLanguage("en"), filepath.Join(dir, "Movie.mkv"))
+	assert.NotNil(t, err)
+}
+
+func TestPluginShouldNotTakeUnknownFormatsInPaths(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Shell plugins are not supported on Windows")
+	}
+	dir, err := ioutil.TempDir("", "subify")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	plugin := strings.Replace(fakePlugin, `"format": "vtt"`, `"format": "/../../evil"`, 1)
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "subify-provider-fake"), []byte(plugin), 0755))
+	createFiles(t, dir, "Movie.mkv")
+
+	subtitlePath, err := NewPlugin(filepath.Join(dir, "subify-provider-fake")).Download(filepath.Join(dir, "Movie.mkv"), *Languages.GetLanguage("en"))
+	assert.Nil(t, err)
+	assert.Equal(t, filepath.Join(dir, "Movie.en.srt"), subtitlePath)
+}
@@ -0,0 +1,109 @@
+package subtitles
+
+import (
+	"os"
+	"strings"
+
+	"github.com/matcornic/subify/common/config"
+	"github.com/matcornic/subify/subtitles/charset"
+	"github.com/matcornic/subify/subtitles/clean"
+	"github.com/matcornic/subify/subtitles/format"
+	"github.com/matcornic/subify/subtitles/translit"
+	"github.com/matcornic/subify/subtitles/zhconv"
+	logger "github.com/spf13/jwalterweatherman"
+)
+
+// postProcess applies the options to a freshly downloaded subtitle and gives its final path.
+// A failing step is only a warning: the downloaded subtitle is still better than nothing.
+func postProcess(subtitlePath string, language Language, opts Options) string {
+	if detected, err := NormalizeEncoding(subtitlePath, &language, false); err != nil {
+		logger.WARN.Println("Subtitle encoding not normalized:", err)
+	} else if detected.Name != charset.UTF8 {
+		logger.INFO.Println("Subtitle transcoded to UTF-8 from", detected)
+	}
+	if opts.Clean {
+		if err := cleanFile(subtitlePath); err != nil {
+			logger.WARN.Println("Subtitle not cleaned:", err)
+		}
+	}
+	if opts.Script != "" && translit.Supports(language.Tag) {
+		if err := transliterateFile(subtitlePath, language, opts.Script); err != nil {
+			logger.WARN.Println("Subtitle not transliterated:", err)
+		}
+	}
+	// Chinese subtitles are sometimes labeled with the wrong script, or downloaded in the other one
+	if _, ok := zhconv.ScriptOf(language.Tag); ok {
+		if err := convertChineseFile(subtitlePath, language); err != nil {
+			logger.WARN.Println("Subtitle not converted to", language.Description+":", err)
+		}
+	}
+	if opts.Format != "" {
+		to, err := format.ParseFormat(opts.Format)
+		if err != nil {
+			logger.WARN.Println("Subtitle not converted:", err)
+			return subtitlePath
+		}
+		converted := format.ConvertedPath(subtitlePath, to)
+		// MicroDVD and SubViewer subtitles share the .sub extension, so the subtitle may have the path of the converted one
+		if converted == subtitlePath {
+			if from, err := format.DetectFile(subtitlePath); err != nil || from == to {
+				return subtitlePath
+			}
+		}
+		if err := format.Convert(subtitlePath, converted, to, format.Options{}); err != nil {
+			logger.WARN.Println("Subtitle not converted to", to, "because of :", err)
+			return subtitlePath
+		}
+		if converted != subtitlePath {
+			if err := os.Remove(subtitlePath); err != nil {
+				logger.WARN.Println("Can't remove the original subtitle", subtitlePath, "because of :", err)
+			}
+		}
+		logger.INFO.Println("Subtitle converted to", to)
+		subtitlePath = converted
+	}
+	if opts.BOM {
+		if _, err := NormalizeEncoding(subtitlePath, &language, true); err != nil {
+			logger.WARN.Println("Byte order mark not added:", err)
+		}
+	}
+	return subtitlePath
+}
+
+// cleanFile removes the ads and credits of a subtitle, with the patterns of the configuration
+func cleanFile(subtitlePath string) error {
+	s, f, err := format.ReadFile(subtitlePath, format.Options{})
+	if err != nil {
+		return err
+	}
+	removed, err := clean.Clean(s, clean.Options{Patterns: config.CleanPatterns, Cues: config.CleanCues})
+	if err != nil || len(removed) == 0 {
+		return err
+	}
+	if err := format.PatchFile(subtitlePath, subtitlePath, s, f, format.Options{}); err != nil {
+		return err
+	}
+	logger.INFO.Println("Subtitle cleaned of:", strings.Join(removed, " | "))
+	return nil
+}
+
+// transliterateFile converts a subtitle to the script of its name (latin or cyrillic), for languages written in both
+func transliterateFile(subtitlePath string, language Language, script string) error {
+	to, err := translit.ParseScript(script)
+	if err != nil {
+		return err
+	}
+	s, f, err := format.ReadFile(subtitlePath, format.Options{})
+	if err != nil {
+		return err
+	}
+	changed, err := translit.Transliterate(s, language.Tag, to)
+	if err != nil || changed == 0 {
+		return err
+	}
+	if err := format.PatchFile(subtitlePath, subtitlePath, s, f, format.Options{}); err != nil {
+		return err
+	}
+	logger.INFO.Println("Subtitle transliterated to the", to, "script")
+	return nil
+}
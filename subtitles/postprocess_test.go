@@ -0,0 +1,41 @@
+package subtitles
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCleanFileShouldOnlyTakeOutTheRemovedCues(t *testing.T) {
+	dir, err := ioutil.TempDir("", "subify")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	subtitlePath := filepath.Join(dir, "Movie.en.srt")
+	content := "1\n00:00:01,000 --> 00:00:02,000\nSubtitles by someone\n\n2\n00:00:03,000 --> 00:00:04,000\n<font face=\"Arial\">Hello</font>\n\n" +
+		"3\n00:00:05,000 --> 00:00:06,000\n{\\an8}<ruby>漢<rt>kan</rt></ruby>\n"
+	assert.Nil(t, ioutil.WriteFile(subtitlePath, []byte(content), 0644))
+
+	assert.Nil(t, cleanFile(subtitlePath))
+	data, err := ioutil.ReadFile(subtitlePath)
+	assert.Nil(t, err)
+	assert.Equal(t, "1\n00:00:03,000 --> 00:00:04,000\n<font face=\"Arial\">Hello</font>\n\n2\n00:00:05,000 --> 00:00:06,000\n{\\an8}<ruby>漢<rt>kan</rt></ruby>\n", string(data))
+}
+
+func TestTransliterateFileShouldKeepTheMarkup(t *testing.T) {
+	dir, err := ioutil.TempDir("", "subify")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	subtitlePath := filepath.Join(dir, "Movie.sr.srt")
+	content := "1\r\n00:00:01,000 --> 00:00:02,000\r\n<font face=\"Arial\">Zdravo</font> &amp; dobro jutro\r\n\r\n" +
+		"2\r\n00:00:03,000 --> 00:00:04,000\r\n{\\an8}<ruby>Ljubav<rt>ljubav</rt></ruby>\r\n"
+	assert.Nil(t, ioutil.WriteFile(subtitlePath, []byte(content), 0644))
+
+	assert.Nil(t, transliterateFile(subtitlePath, *Languages.GetLanguage("sr"), "cyrillic"))
+	data, err := ioutil.ReadFile(subtitlePath)
+	assert.Nil(t, err)
+	assert.Equal(t, "1\r\n00:00:01,000 --> 00:00:02,000\r\n<font face=\"Arial\">Здраво</font> &amp; добро јутро\r\n\r\n"+
+		"2\r\n00:00:03,000 --> 00:00:04,000\r\n{\\an8}<ruby>Љубав<rt>љубав</rt></ruby>\r\n", string(data))
+}
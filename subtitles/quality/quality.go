@@ -0,0 +1,263 @@
+// Package quality checks the usual defects of subtitles (overlaps, unreadable cues, long lines...) and fixes the safe ones
+package quality
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/matcornic/subify/subtitles/format"
+)
+
+// Rules are the limits of a readable subtitle
+type Rules struct {
+	MaxCPS        float64       // Maximum reading speed, in characters per second
+	MaxLineLength int           // Maximum number of characters in a line
+	MaxLines      int           // Maximum number of lines in a cue
+	MinDuration   time.Duration // Minimum display duration of a cue
+	MinGap        time.Duration // Gap kept between two cues when fixing overlaps
+}
+
+// DefaultRules are the usual limits of professional subtitles
+var DefaultRules = Rules{
+	MaxCPS:        21,
+	MaxLineLength: 42,
+	MaxLines:      2,
+	MinDuration:   700 * time.Millisecond,
+	MinGap:        40 * time.Millisecond,
+}
+
+// Kind is a kind of issue
+type Kind string
+
+// Kinds of issues
+const (
+	Overlap      Kind = "overlap"
+	ZeroLength   Kind = "zero-length"
+	TooFast      Kind = "too-fast"
+	TooShort     Kind = "too-short"
+	LineTooLong  Kind = "line-too-long"
+	TooManyLines Kind = "too-many-lines"
+	Numbering    Kind = "numbering"
+	Empty        Kind = "empty"
+)
+
+// Issue is a defect of a cue
+type Issue struct {
+	Cue     int           `json:"cue"` // Position of the cue, from 1
+	Start   time.Duration `json:"start"`
+	Kind    Kind          `json:"kind"`
+	Message string        `json:"message"`
+}
+
+// Stats are the reading speed statistics of a subtitle
+type Stats struct {
+	Cues      int     `json:"cues"`
+	MeanCPS   float64 `json:"mean_cps"`
+	MedianCPS float64 `json:"median_cps"`
+	MaxCPS    float64 `json:"max_cps"`
+	TooFast   int     `json:"too_fast"` // Number of cues faster than the maximum reading speed
+}
+
+// Report is the result of a check
+type Report struct {
+	Issues []Issue `json:"issues"`
+	Stats  Stats   `json:"stats"`
+}
+
+// CPS gives the reading speed of a cue, in characters per second. Zero length cues are infinitely fast
+func CPS(c *format.Cue) float64 {
+	characters := utf8.RuneCountInString(strings.Replace(c.PlainText(), "\n", "", -1))
+	if c.Duration() <= 0 {
+		if characters == 0 {
+			return 0
+		}
+		return math.Inf(1)
+	}
+	return float64(characters) / c.Duration().Seconds()
+}
+
+// Check finds the issues of a subtitle
+func Check(s *format.Subtitle, rules Rules) Report {
+	var r Report
+	add := func(i int, c *format.Cue, kind Kind, message string, args ...interface{}) {
+		r.Issues = append(r.Issues, Issue{Cue: i + 1, Start: c.Start, Kind: kind, Message: fmt.Sprintf(message, args...)})
+	}
+
+	var speeds []float64
+	for i, c := range s.Cues {
+		if c.Index != i+1 {
+			add(i, c, Numbering, "Numbered %v instead of %v", c.Index, i+1)
+		}
+		if strings.TrimSpace(c.PlainText()) == "" && !c.Drawing {
+			add(i, c, Empty, "No text")
+		}
+		switch {
+		case c.End <= c.Start:
+			add(i, c, ZeroLength, "Ends at %v, before or when it starts", format.FormatTimestamp(c.End, ","))
+		case c.Duration() < rules.MinDuration:
+			add(i, c, TooShort, "Displayed %v, less than %v", c.Duration(), rules.MinDuration)
+		}
+		// Cues starting together are displayed both when they are not at the same place
+		if i+1 < len(s.Cues) && c.End > s.Cues[i+1].Start && (c.Start != s.Cues[i+1].Start || mergeable(c, s.Cues[i+1])) {
+			add(i, c, Overlap, "Overlaps the next cue by %v", c.End-s.Cues[i+1].Start)
+		}
+		if cps := CPS(c); c.Duration() > 0 {
+			speeds = append(speeds, cps)
+			if cps > rules.MaxCPS {
+				add(i, c, TooFast, "%.1f characters per second, more than %v", cps, rules.MaxCPS)
+				r.Stats.TooFast++
+			}
+		}
+		if len(c.Lines) > rules.MaxLines {
+			add(i, c, TooManyLines, "%v lines, more than %v", len(c.Lines), rules.MaxLines)
+		}
+		for _, l := range c.Lines {
+			if length := format.LineLength(l); length > rules.MaxLineLength {
+				add(i, c, LineTooLong, "Line of %v characters, more than %v: %v", length, rules.MaxLineLength, format.StripTags(l))
+			}
+		}
+	}
+
+	r.Stats.Cues = len(s.Cues)
+	if len(speeds) > 0 {
+		sort.Float64s(speeds)
+		var sum float64
+		for _, cps := range speeds {
+			sum += cps
+		}
+		r.Stats.MeanCPS = sum / float64(len(speeds))
+		r.Stats.MedianCPS = speeds[len(speeds)/2]
+		r.Stats.MaxCPS = speeds[len(speeds)-1]
+	}
+	return r
+}
+
+// Fixes counts the corrections applied by Fix
+type Fixes struct {
+	Removed    int // Empty cues
+	Merged     int // Cues merged into the cue starting with them
+	Overlaps   int
+	Durations  int // Cues displayed longer
+	Rewrapped  int
+	Renumbered bool
+}
+
+// Total gives the number of corrections
+func (f Fixes) Total() int {
+	total := f.Removed + f.Merged + f.Overlaps + f.Durations + f.Rewrapped
+	if f.Renumbered {
+		total++
+	}
+	return total
+}
+
+// Fix applies the safe corrections: empty cues are removed, cues are sorted and renumbered,
+// overlaps are resolved, too short cues are displayed longer when there is room, and long lines are rewrapped.
+// Cues starting together are merged when they would be displayed at the same place, see mergeable.
+// The others are left overlapping, their style or alignment keeping them apart
+func Fix(s *format.Subtitle, rules Rules) Fixes {
+	var f Fixes
+
+	var kept []*format.Cue
+	for _, c := range s.Cues {
+		if strings.TrimSpace(c.PlainText()) == "" && !c.Drawing {
+			f.Removed++
+			continue
+		}
+		kept = append(kept, c)
+	}
+	s.Cues = kept
+	s.Sort()
+
+	var merged []*format.Cue
+	for _, c := range s.Cues {
+		if n := len(merged); n > 0 && merged[n-1].Start == c.Start && mergeable(merged[n-1], c) {
+			previous := merged[n-1]
+			previous.Lines = append(previous.Lines, c.Lines...)
+			if c.End > previous.End {
+				previous.End = c.End
+			}
+			f.Merged++
+			continue
+		}
+		merged = append(merged, c)
+	}
+	s.Cues = merged
+
+	for i, c := range s.Cues {
+		// The cues starting with this one are not next to it
+		next := time.Duration(math.MaxInt64)
+		for _, n := range s.Cues[i+1:] {
+			if n.Start > c.Start {
+				next = n.Start - rules.MinGap
+				if next <= c.Start {
+					next = n.Start
+				}
+				break
+			}
+		}
+		if c.End > next {
+			c.End = next
+			f.Overlaps++
+		}
+
+		// Long enough to be read, without hiding the next cue
+		wanted := rules.MinDuration
+		if rules.MaxCPS > 0 {
+			characters := utf8.RuneCountInString(strings.Replace(c.PlainText(), "\n", "", -1))
+			if reading := time.Duration(float64(characters) / rules.MaxCPS * float64(time.Second)); reading > wanted {
+				wanted = reading
+			}
+		}
+		if c.Duration() < wanted {
+			end := c.Start + wanted
+			if end > next {
+				end = next
+			}
+			if end > c.End {
+				c.End = end
+				f.Durations++
+			}
+		}
+
+		if needsWrap(c, rules) {
+			wrapped := format.Wrap(c.Lines, rules.MaxLineLength)
+			if strings.Join(wrapped, "\n") != strings.Join(c.Lines, "\n") {
+				c.Lines = wrapped
+				f.Rewrapped++
+			}
+		}
+	}
+
+	for i, c := range s.Cues {
+		if c.Index != i+1 {
+			f.Renumbered = true
+		}
+	}
+	s.Renumber()
+	return f
+}
+
+// mergeable tells if two cues starting together can be merged: they have the same style and alignment,
+// and no ASS positioning nor drawing, so that players would display them at the same place
+func mergeable(a, b *format.Cue) bool {
+	return a.Style == b.Style && a.Align == b.Align && a.Layer == b.Layer &&
+		a.Overrides == "" && b.Overrides == "" && !a.Drawing && !b.Drawing
+}
+
+// needsWrap tells if the lines of a cue are too long or too many
+func needsWrap(c *format.Cue, rules Rules) bool {
+	if len(c.Lines) > rules.MaxLines {
+		return true
+	}
+	for _, l := range c.Lines {
+		if format.LineLength(l) > rules.MaxLineLength {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package quality
+
+import (
+	"testing"
+	"time"
+
+	"github.com/matcornic/subify/subtitles/format"
+	"github.com/stretchr/testify/assert"
+)
+
+func brokenSubtitle() *format.Subtitle {
+	return &format.Subtitle{Cues: []*format.Cue{
+		{Index: 1, Start: 1 * time.Second, End: 3 * time.Second, Lines: []string{"Hello"}},
+		{Index: 3, Start: 2 * time.Second, End: 2 * time.Second, Lines: []string{"Overlapped and zero length"}},
+		{Index: 4, Start: 5 * time.Second, End: 5200 * time.Millisecond, Lines: []string{"This one flashes far too fast to be read"}},
+		{Index: 5, Start: 10 * time.Second, End: 14 * time.Second, Lines: []string{"<i>This line is really much longer than forty two characters</i>", "and", "too many lines"}},
+		{Index: 6, Start: 20 * time.Second, End: 21 * time.Second, Lines: []string{" "}},
+	}}
+}
+
+func kinds(r Report) map[Kind]int {
+	k := map[Kind]int{}
+	for _, i := range r.Issues {
+		k[i.Kind]++
+	}
+	return k
+}
+
+func TestCheckShouldFindIssues(t *testing.T) {
+	r := Check(brokenSubtitle(), DefaultRules)
+	k := kinds(r)
+	assert.Equal(t, 1, k[Overlap])
+	assert.Equal(t, 1, k[ZeroLength])
+	assert.Equal(t, 1, k[TooShort])
+	assert.Equal(t, 1, k[TooFast])
+	assert.Equal(t, 1, k[LineTooLong])
+	assert.Equal(t, 1, k[TooManyLines])
+	assert.Equal(t, 4, k[Numbering])
+	assert.Equal(t, 1, k[Empty])
+	assert.Equal(t, 5, r.Stats.Cues)
+	assert.InDelta(t, 200, r.Stats.MaxCPS, 1)
+}
+
+func TestFixShouldApplySafeCorrections(t *testing.T) {
+	s := brokenSubtitle()
+	f := Fix(s, DefaultRules)
+	assert.Equal(t, 1, f.Removed)
+	assert.True(t, f.Renumbered)
+
+	r := Check(s, DefaultRules)
+	k := kinds(r)
+	assert.Equal(t, 0, k[Overlap])
+	assert.Equal(t, 0, k[ZeroLength])
+	assert.Equal(t, 0, k[Numbering])
+	assert.Equal(t, 0, k[LineTooLong])
+	assert.Equal(t, 0, k[TooManyLines])
+	assert.Equal(t, 0, k[Empty])
+	assert.Equal(t, 4, len(s.Cues))
+
+	// The overlapped cue starts before the end of the first one
+	assert.Equal(t, 2*time.Second-DefaultRules.MinGap, s.Cues[0].End)
+	// The too fast cue is displayed longer
+	assert.True(t, s.Cues[2].Duration() >= 1900*time.Millisecond)
+	assert.Equal(t, []string{"<i>This line is really much longer than</i>", "<i>forty two characters</i> and too many lines"}, s.Cues[3].Lines)
+}
+
+func TestFixShouldOnlyMergeCuesDisplayedAtTheSamePlace(t *testing.T) {
+	s := &format.Subtitle{Cues: []*format.Cue{
+		{Index: 1, Start: time.Second, End: 3 * time.Second, Lines: []string{"- Hello"}},
+		{Index: 2, Start: time.Second, End: 4 * time.Second, Lines: []string{"- Hi"}},
+		{Index: 3, Start: 5 * time.Second, End: 8 * time.Second, Lines: []string{"Dialogue"}},
+		{Index: 4, Start: 5 * time.Second, End: 7 * time.Second, Lines: []string{"SIGN"}, Align: format.AlignTopCenter},
+		{Index: 5, Start: 7500 * time.Millisecond, End: 9 * time.Second, Lines: []string{"Next"}},
+	}}
+	f := Fix(s, DefaultRules)
+	assert.Equal(t, 1, f.Merged)
+	assert.Equal(t, 4, len(s.Cues))
+	assert.Equal(t, []string{"- Hello", "- Hi"}, s.Cues[0].Lines)
+	assert.Equal(t, 4*time.Second, s.Cues[0].End)
+
+	// The sign is kept at the top, and both cues end before the next one
+	assert.Equal(t, format.AlignTopCenter, s.Cues[2].Align)
+	assert.Equal(t, 7500*time.Millisecond-DefaultRules.MinGap, s.Cues[1].End)
+	assert.Equal(t, 7*time.Second, s.Cues[2].End)
+	assert.Equal(t, 1, f.Overlaps)
+	assert.Equal(t, 0, kinds(Check(s, DefaultRules))[Overlap])
+}
@@ -0,0 +1,138 @@
+package subtitles
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// SearchMode is a way for an API to identify the subtitles of a video
+type SearchMode string
+
+const (
+	// SearchByHash identifies the video with a hash of its content
+	SearchByHash SearchMode = "hash"
+	// SearchByName identifies the video with the title, season and episode found in its name
+	SearchByName SearchMode = "name"
+	// SearchByID identifies the video with an id (IMDb, TMDb, AniList...)
+	SearchByID SearchMode = "id"
+)
+
+// Capabilities declares what an API is able to do
+type Capabilities struct {
+	Languages       map[string]string // Subify language IDs to the codes of the API. Nil means any language
+	SearchModes     []SearchMode      // Ways to identify the video
+	Upload          bool              // Subtitles can be uploaded
+	HearingImpaired bool              // Hearing impaired subtitles are flagged
+	Forced          bool              // Forced (foreign parts only) subtitles are flagged
+	NeedsAuth       bool              // An API key or an account is needed
+}
+
+// Supports tells if the language is available in the API
+func (c Capabilities) Supports(language Language) bool {
+	if c.Languages == nil {
+		return true
+	}
+	_, ok := c.Languages[language.ID]
+	return ok
+}
+
+// AnyLanguage tells if the API does not restrict languages (local directories, plugins without declaration)
+func (c Capabilities) AnyLanguage() bool {
+	return c.Languages == nil
+}
+
+// Registry holds the APIs known by Subify, in the default searching order
+type Registry struct {
+	mu      sync.Mutex
+	clients Clients
+	plugins Clients
+	loaded  bool
+}
+
+// APIs is the registry of all APIs. Built-in APIs are registered below, plugins are discovered on first use
+var APIs = &Registry{}
+
+func init() {
+	APIs.Register(Local(), SubDB(), OpenSubtitles(), Addic7ed(), SubDL(), Jimaku())
+}
+
+// Register adds APIs to the registry
+func (r *Registry) Register(clients ...Client) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.clients = append(r.clients, clients...)
+}
+
+// BuiltIn gives the APIs compiled in Subify
+func (r *Registry) BuiltIn() Clients {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append(Clients{}, r.clients...)
+}
+
+// All gives the built-in APIs followed by the provider plugins
+func (r *Registry) All() Clients {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.loaded {
+		r.plugins = DiscoverPlugins()
+		r.loaded = true
+	}
+	return append(append(Clients{}, r.clients...), r.plugins...)
+}
+
+// Find gives the API identified by one of its aliases, nil if not found
+func (r *Registry) Find(alias string) Client {
+	for _, c := range r.All() {
+		for _, a := range c.GetAliases() {
+			if strings.EqualFold(strings.TrimSpace(alias), strings.TrimSpace(a)) {
+				return c
+			}
+		}
+	}
+	return nil
+}
+
+// Print prints the clients and their capabilities as nice table
+func (c Clients) Print() {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Name", "Aliases", "Search by", "Languages", "Upload", "Hearing impaired", "Forced", "Needs auth"})
+	for _, l := range c {
+		caps := l.GetCapabilities()
+		var modes []string
+		for _, m := range caps.SearchModes {
+			modes = append(modes, string(m))
+		}
+		languages := "Any"
+		if !caps.AnyLanguage() {
+			languages = strconv.Itoa(len(caps.Languages))
+		}
+		values := []string{
+			l.GetName(),                        // Name
+			strings.Join(l.GetAliases(), ", "), // Aliases
+			strings.Join(modes, ", "),          // Search by
+			languages,                          // Languages
+			yesNo(caps.Upload),                 // Upload
+			yesNo(caps.HearingImpaired),        // Hearing impaired
+			yesNo(caps.Forced),                 // Forced
+			yesNo(caps.NeedsAuth),              // Needs auth
+		}
+		table.Append(values)
+	}
+	table.SetAutoWrapText(false)
+	table.SetColWidth(50)
+	table.SetRowLine(true)
+	table.Render() // Send output
+}
+
+// yesNo prints a boolean for the user
+func yesNo(b bool) string {
+	if b {
+		return "Yes"
+	}
+	return "No"
+}
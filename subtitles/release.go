@@ -0,0 +1,159 @@
+package subtitles
+
+import (
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Release describes what can be guessed from the file name of a video or a subtitle
+type Release struct {
+	Name    string   // Original file name, without extension
+	Title   string   // Title of the movie or the TV show
+	Year    int      // Year of the movie, 0 if unknown
+	Season  int      // Season of the TV show, 0 if unknown
+	Episode int      // Episode of the TV show (absolute number for animes), 0 if unknown
+	Group   string   // Release group (or fansub group)
+	Tokens  []string // Normalized words of the name, used to compare releases
+}
+
+var (
+	seasonEpisodeRegexp   = regexp.MustCompile(`(?i)\bs(\d{1,2})[ ._-]?e(\d{1,3})\b`)
+	crossEpisodeRegexp    = regexp.MustCompile(`(?i)\b(\d{1,2})x(\d{2,3})\b`)
+	absoluteEpisodeRegexp = regexp.MustCompile(`(?i)(?:\s-\s|\b(?:ep|episode)\s?)(\d{1,4})(?:v\d)?\b`)
+	fansubGroupRegexp     = regexp.MustCompile(`^\[([^\]]+)\]`)
+	bracketsRegexp        = regexp.MustCompile(`\[[^\]]*\]|\([^)]*\)`)
+	yearRegexp            = regexp.MustCompile(`\b(19\d{2}|20\d{2})\b`)
+	extensionRegexp       = regexp.MustCompile(`^\.[A-Za-z][A-Za-z0-9]{1,3}$`)
+	groupRegexp           = regexp.MustCompile(`-([A-Za-z0-9]+)$`)
+	tokenRegexp           = regexp.MustCompile(`[\p{L}\p{N}]+`)
+)
+
+// qualityTokens are words that mark the end of the title in a release name
+var qualityTokens = map[string]bool{
+	"480p": true, "576p": true, "720p": true, "1080p": true, "1080i": true, "2160p": true, "4k": true,
+	"bluray": true, "bdrip": true, "brrip": true, "dvdrip": true, "hdrip": true, "webrip": true,
+	"web": true, "webdl": true, "hdtv": true, "pdtv": true, "x264": true, "x265": true, "h264": true,
+	"h265": true, "hevc": true, "xvid": true, "divx": true, "proper": true, "repack": true,
+	"internal": true, "limited": true, "extended": true, "unrated": true, "remastered": true,
+	"multi": true, "french": true, "vostfr": true, "subbed": true, "dubbed": true, "hdr": true,
+	"aac": true, "ac3": true, "dts": true, "10bit": true,
+}
+
+// ParseRelease guesses the title, year, season, episode and group from a file name
+func ParseRelease(fileName string) Release {
+	name := path.Base(strings.Replace(fileName, "\\", "/", -1))
+	if ext := path.Ext(name); extensionRegexp.MatchString(ext) && !qualityTokens[strings.ToLower(ext[1:])] {
+		name = strings.TrimSuffix(name, ext)
+	}
+	r := Release{Name: name}
+
+	// Fansub releases start with the group between brackets: [Group] Show - 137 [1080p]
+	if m := fansubGroupRegexp.FindStringSubmatch(name); m != nil {
+		r.Group = m[1]
+	}
+
+	clean := strings.NewReplacer(".", " ", "_", " ").Replace(name)
+	clean = bracketsRegexp.ReplaceAllStringFunc(clean, func(s string) string {
+		// Keep years like (1999) but forget tags like [1080p] or [ABCD1234]
+		if yearRegexp.MatchString(s) && len(s) == 6 {
+			return " " + s[1:5] + " "
+		}
+		return strings.Repeat(" ", len(s))
+	})
+	titleEnd := len(clean)
+	cut := func(i int) {
+		if i >= 0 && i < titleEnd {
+			titleEnd = i
+		}
+	}
+
+	if m := seasonEpisodeRegexp.FindStringSubmatchIndex(clean); m != nil {
+		r.Season, _ = strconv.Atoi(clean[m[2]:m[3]])
+		r.Episode, _ = strconv.Atoi(clean[m[4]:m[5]])
+		cut(m[0])
+	} else if m := crossEpisodeRegexp.FindStringSubmatchIndex(clean); m != nil {
+		r.Season, _ = strconv.Atoi(clean[m[2]:m[3]])
+		r.Episode, _ = strconv.Atoi(clean[m[4]:m[5]])
+		cut(m[0])
+	} else if m := absoluteEpisodeRegexp.FindStringSubmatchIndex(clean); m != nil && !yearRegexp.MatchString(clean[m[2]:m[3]]) {
+		r.Episode, _ = strconv.Atoi(clean[m[2]:m[3]])
+		cut(m[0])
+	}
+
+	// The year is the last one found, so that titles like "2001 A Space Odyssey 1968" work
+	if years := yearRegexp.FindAllStringSubmatchIndex(clean, -1); years != nil {
+		m := years[len(years)-1]
+		if m[0] > 0 {
+			r.Year, _ = strconv.Atoi(clean[m[2]:m[3]])
+			cut(m[0])
+		}
+	}
+
+	for _, loc := range tokenRegexp.FindAllStringIndex(clean, -1) {
+		if qualityTokens[strings.ToLower(clean[loc[0]:loc[1]])] {
+			cut(loc[0])
+			break
+		}
+	}
+
+	if m := groupRegexp.FindStringSubmatch(name); m != nil && r.Group == "" && !qualityTokens[strings.ToLower(m[1])] {
+		r.Group = m[1]
+	}
+
+	r.Title = strings.Join(strings.Fields(strings.Trim(clean[:titleEnd], " -([")), " ")
+	r.Tokens = normalizeTokens(name)
+	return r
+}
+
+// normalizeTokens splits a name into lower case words
+func normalizeTokens(name string) []string {
+	return tokenRegexp.FindAllString(strings.ToLower(name), -1)
+}
+
+// NormalizeName gives a comparable version of a release name (lower case words separated by a space)
+func NormalizeName(name string) string {
+	return strings.Join(normalizeTokens(name), " ")
+}
+
+// Similarity compares two releases with the Dice coefficient of their words.
+// 1 means the releases have exactly the same words, 0 means nothing in common.
+func (r Release) Similarity(other Release) float64 {
+	if len(r.Tokens) == 0 || len(other.Tokens) == 0 {
+		return 0
+	}
+	words := make(map[string]int)
+	for _, t := range r.Tokens {
+		words[t]++
+	}
+	common := 0
+	for _, t := range other.Tokens {
+		if words[t] > 0 {
+			words[t]--
+			common++
+		}
+	}
+	score := 2 * float64(common) / float64(len(r.Tokens)+len(other.Tokens))
+	if r.Group != "" && strings.EqualFold(r.Group, other.Group) {
+		score = (score + 1) / 2
+	}
+	return score
+}
+
+// MatchesEpisode tells if the other release can be the same episode as this release.
+// Unknown season or episode numbers on either side are considered compatible.
+func (r Release) MatchesEpisode(other Release) bool {
+	if r.Episode != 0 && other.Episode != 0 && r.Episode != other.Episode {
+		return false
+	}
+	if r.Season != 0 && other.Season != 0 && r.Season != other.Season {
+		return false
+	}
+	return true
+}
+
+// IsEpisode tells if the release is an episode of a TV show
+func (r Release) IsEpisode() bool {
+	return r.Episode != 0
+}
@@ -0,0 +1,80 @@
+package subtitles
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseReleaseShouldFindMovieTitleAndYear(t *testing.T) {
+	r := ParseRelease("/videos/The.Matrix.1999.1080p.BluRay.x264-GROUP.mkv")
+	assert.Equal(t, "The Matrix", r.Title)
+	assert.Equal(t, 1999, r.Year)
+	assert.Equal(t, "GROUP", r.Group)
+	assert.False(t, r.IsEpisode())
+}
+
+func TestParseReleaseShouldKeepYearInTitle(t *testing.T) {
+	r := ParseRelease("2001.A.Space.Odyssey.1968.720p.mkv")
+	assert.Equal(t, "2001 A Space Odyssey", r.Title)
+	assert.Equal(t, 1968, r.Year)
+}
+
+func TestParseReleaseShouldFindSeasonAndEpisode(t *testing.T) {
+	r := ParseRelease("Game.of.Thrones.S03E09.720p.HDTV.x264-EVOLVE.mkv")
+	assert.Equal(t, "Game of Thrones", r.Title)
+	assert.Equal(t, 3, r.Season)
+	assert.Equal(t, 9, r.Episode)
+
+	r = ParseRelease("Friends 2x10 The One With Russ.avi")
+	assert.Equal(t, "Friends", r.Title)
+	assert.Equal(t, 2, r.Season)
+	assert.Equal(t, 10, r.Episode)
+}
+
+func TestSimilarityShouldPreferSameRelease(t *testing.T) {
+	video := ParseRelease("Game.of.Thrones.S03E09.720p.HDTV.x264-EVOLVE.mkv")
+	same := ParseRelease("Game.of.Thrones.S03E09.720p.HDTV.x264-EVOLVE")
+	other := ParseRelease("Game.of.Thrones.S03E09.1080p.WEB-DL.DD5.1.H.264-NTb")
+	assert.InDelta(t, 1.0, video.Similarity(same), 0.001)
+	assert.True(t, video.Similarity(same) > video.Similarity(other))
+}
+
+func TestExtractFromZipShouldChooseEpisode(t *testing.T) {
+	buf := new(bytes.Buffer)
+	w := zip.NewWriter(buf)
+	for _, name := range []string{"Show.S01E01.srt", "Show.S01E02.srt", "Show.S01E03.srt", "readme.txt"} {
+		f, err := w.Create(name)
+		assert.Nil(t, err)
+		_, _ = f.Write([]byte(name))
+	}
+	assert.Nil(t, w.Close())
+
+	name, content, err := extractFromZip(buf.Bytes(), ParseRelease("Show.S01E02.720p.mkv"))
+	assert.Nil(t, err)
+	assert.Equal(t, "Show.S01E02.srt", name)
+	assert.Equal(t, "Show.S01E02.srt", string(content))
+
+	_, _, err = extractFromZip(buf.Bytes(), ParseRelease("Show.S01E04.720p.mkv"))
+	assert.NotNil(t, err)
+}
+
+func TestParseReleaseShouldFindAbsoluteEpisodeAndFansubGroup(t *testing.T) {
+	r := ParseRelease("[SubsPlease] One Piece - 1071 (1080p) [4C1D8E2A].mkv")
+	assert.Equal(t, "One Piece", r.Title)
+	assert.Equal(t, "SubsPlease", r.Group)
+	assert.Equal(t, 0, r.Season)
+	assert.Equal(t, 1071, r.Episode)
+
+	r = ParseRelease("[Group] Show - 137v2 [1080p].mkv")
+	assert.Equal(t, "Show", r.Title)
+	assert.Equal(t, 137, r.Episode)
+}
+
+func TestParseReleaseShouldKeepYearBetweenParentheses(t *testing.T) {
+	r := ParseRelease("The Matrix (1999).mkv")
+	assert.Equal(t, "The Matrix", r.Title)
+	assert.Equal(t, 1999, r.Year)
+}
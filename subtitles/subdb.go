@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
-	"path"
 	"time"
 
 	"github.com/google/go-querystring/query"
@@ -30,7 +29,9 @@ var subdbLangs = map[string]string{
 	"swe": "sv",
 	"tur": "tr",
 	"rum": "ro",
-	"pob": "pt",
+
+	// Regional and script variants
+	"pt-BR": "pt",
 }
 
 // SubDBAPI entry point
@@ -65,7 +66,7 @@ func (s SubDBAPI) Download(videoPath string, language Language) (subtitlePath st
 	}
 
 	// Save the content to file
-	subtitlePath = videoPath[0:len(videoPath)-len(path.Ext(videoPath))] + "." + lang + ".srt"
+	subtitlePath = subtitlePathFor(videoPath, language.Tag, ".srt")
 
 	err = ioutil.WriteFile(subtitlePath, subtitle, 0644)
 	if err != nil {
@@ -90,6 +91,14 @@ func (s SubDBAPI) GetAliases() []string {
 	return s.Aliases
 }
 
+// GetCapabilities returns what this API is able to do
+func (s SubDBAPI) GetCapabilities() Capabilities {
+	return Capabilities{
+		Languages:   subdbLangs,
+		SearchModes: []SearchMode{SearchByHash},
+	}
+}
+
 // options describes parameters to the SubDB API
 type options struct {
 	Action   string `url:"action,omitempty"`
@@ -0,0 +1,314 @@
+package subtitles
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-querystring/query"
+	"github.com/lafikl/fluent"
+	"github.com/matcornic/subify/common/config"
+	logger "github.com/spf13/jwalterweatherman"
+)
+
+const (
+	subdlUserAgent   = "Subify"
+	subdlAPIURL      = "https://api.subdl.com/api/v1/subtitles"
+	subdlDownloadURL = "https://dl.subdl.com"
+	// subdlMaxTries is the number of archives downloaded before giving up
+	subdlMaxTries = 3
+)
+
+var subdlLangs = map[string]string{
+	"alb": "SQ",
+	"ara": "AR",
+	"aze": "AZ",
+	"bel": "BE",
+	"ben": "BN",
+	"bos": "BS",
+	"bul": "BG",
+	"bur": "MY",
+	"cat": "CA",
+	"chi": "ZH",
+	"cze": "CS",
+	"dan": "DA",
+	"dut": "NL",
+	"eng": "EN",
+	"epo": "EO",
+	"est": "ET",
+	"fin": "FI",
+	"fre": "FR",
+	"geo": "KA",
+	"ger": "DE",
+	"gre": "EL",
+	"heb": "HE",
+	"hin": "HI",
+	"hrv": "HR",
+	"hun": "HU",
+	"ice": "IS",
+	"ind": "ID",
+	"ita": "IT",
+	"jpn": "JA",
+	"kor": "KO",
+	"kur": "KU",
+	"lav": "LV",
+	"lit": "LT",
+	"mac": "MK",
+	"mal": "ML",
+	"may": "MS",
+	"mni": "MNI",
+	"nor": "NO",
+	"per": "FA",
+	"pol": "PL",
+	"por": "PT",
+	"rum": "RO",
+	"rus": "RU",
+	"srp": "SR",
+	"sin": "SI",
+	"slo": "SK",
+	"slv": "SL",
+	"spa": "ES",
+	"swe": "SV",
+	"tam": "TA",
+	"tel": "TE",
+	"tgl": "TL",
+	"tha": "TH",
+	"tur": "TR",
+	"ukr": "UK",
+	"urd": "UR",
+	"vie": "VI",
+
+	// Regional and script variants
+	"pt-BR":   "BR_PT",
+	"zh-Hans": "ZH",
+	"zh-Hant": "ZH_BG",
+}
+
+// SubDLAPI is the endpoint for downloading SubDL subtitles.
+// SubDL is searched by title, hence it finds subtitles for videos unknown by hash based APIs
+type SubDLAPI struct {
+	Name    string
+	Aliases []string
+}
+
+// SubDL creates a new API for SubDL
+func SubDL() SubDLAPI {
+	return SubDLAPI{
+		Name:    "SubDL",
+		Aliases: []string{"subdl", "sdl"},
+	}
+}
+
+// subdlOptions describes parameters to the SubDL search API
+type subdlOptions struct {
+	APIKey        string `url:"api_key"`
+	FilmName      string `url:"film_name"`
+	Type          string `url:"type"`
+	Year          int    `url:"year,omitempty"`
+	SeasonNumber  int    `url:"season_number,omitempty"`
+	EpisodeNumber int    `url:"episode_number,omitempty"`
+	Languages     string `url:"languages"`
+	SubsPerPage   int    `url:"subs_per_page"`
+}
+
+// subdlResponse is the answer of the SubDL search API
+type subdlResponse struct {
+	Status    bool            `json:"status"`
+	Error     string          `json:"error"`
+	Subtitles []subdlSubtitle `json:"subtitles"`
+}
+
+// subdlSubtitle is one subtitle archive referenced by SubDL
+type subdlSubtitle struct {
+	ReleaseName string `json:"release_name"`
+	Name        string `json:"name"`
+	Language    string `json:"language"`
+	URL         string `json:"url"`
+	Season      int    `json:"season"`
+	Episode     int    `json:"episode"`
+	FullSeason  bool   `json:"full_season"`
+	HI          bool   `json:"hi"`
+}
+
+// Download downloads the SubDL subtitle from a video
+func (s SubDLAPI) Download(videoPath string, language Language) (subtitlePath string, err error) {
+	if config.SubDLAPIKey == "" {
+		return "", errors.New("SubDL needs an API key. Set 'subdl.apikey' in your configuration file")
+	}
+	lang, ok := subdlLangs[language.ID]
+	if !ok {
+		return "", errors.New("Language exists but is not available for SubDL")
+	}
+
+	video := ParseRelease(videoPath)
+	if video.Title == "" {
+		return "", errors.New("Can't guess the title of the video from its name")
+	}
+
+	subs, err := subdlSearch(video, lang)
+	if err != nil {
+		return "", err
+	}
+	subs = rankSubDLSubtitles(subs, video, configuredHearingImpaired())
+	if len(subs) == 0 {
+		return "", fmt.Errorf("No subtitle found by SubDL for %v", video.Title)
+	}
+
+	for i, sub := range subs {
+		if i >= subdlMaxTries {
+			break
+		}
+		var name string
+		var content []byte
+		name, content, err = subdlDownload(sub, video)
+		if err != nil {
+			logger.INFO.Println("Can't use SubDL subtitle", sub.ReleaseName, ":", err)
+			continue
+		}
+
+		subtitlePath = subtitlePathFor(videoPath, language.Tag, strings.ToLower(path.Ext(name)))
+		if err = ioutil.WriteFile(subtitlePath, content, 0644); err != nil {
+			return "", fmt.Errorf("Can't save the file %v because of : %v", subtitlePath, err)
+		}
+		logger.INFO.Println("Original name of subtitle :", sub.ReleaseName, "("+name+")")
+		return subtitlePath, nil
+	}
+
+	return "", err
+}
+
+// Upload uploads the subtitle to SubDL, for the given video
+func (s SubDLAPI) Upload(subtitlePath string, language Language, videoPath string) error {
+	return errors.New("Not yet implemented")
+}
+
+// GetName returns the name of the api
+func (s SubDLAPI) GetName() string {
+	return s.Name
+}
+
+// GetAliases returns aliases to identify this API
+func (s SubDLAPI) GetAliases() []string {
+	return s.Aliases
+}
+
+// GetCapabilities returns what this API is able to do
+func (s SubDLAPI) GetCapabilities() Capabilities {
+	return Capabilities{
+		Languages:       subdlLangs,
+		SearchModes:     []SearchMode{SearchByName},
+		HearingImpaired: true,
+		NeedsAuth:       true,
+	}
+}
+
+// subdlSearch searches subtitles by title (and year or episode)
+func subdlSearch(video Release, lang string) ([]subdlSubtitle, error) {
+	opt := subdlOptions{
+		APIKey:      config.SubDLAPIKey,
+		FilmName:    video.Title,
+		Type:        "movie",
+		Year:        video.Year,
+		Languages:   lang,
+		SubsPerPage: 30,
+	}
+	if video.IsEpisode() {
+		opt.Type = "tv"
+		opt.Year = 0
+		opt.SeasonNumber = video.Season
+		opt.EpisodeNumber = video.Episode
+	}
+	v, _ := query.Values(opt)
+
+	req := fluent.New()
+	req.Get(subdlAPIURL+"?"+v.Encode()).
+		SetHeader("User-Agent", subdlUserAgent).
+		InitialInterval(time.Duration(time.Millisecond)).
+		Retry(3)
+	res, err := req.Send()
+	if err != nil {
+		return nil, fmt.Errorf("Can't reach the SubDL Web API. Are you connected to the Internet ? %v", err.Error())
+	}
+	defer res.Body.Close()
+
+	var body subdlResponse
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("The answer of SubDL can't be read: %v", err)
+	}
+	if !body.Status {
+		if body.Error != "" {
+			return nil, fmt.Errorf("SubDL refused the search: %v", body.Error)
+		}
+		return nil, errors.New("Subtitle not stored by SubDL")
+	}
+	return body.Subtitles, nil
+}
+
+// rankSubDLSubtitles removes subtitles of other episodes, or not matching the hearing impaired preference,
+// and sorts the others by similarity to the video name
+func rankSubDLSubtitles(subs []subdlSubtitle, video Release, hi HearingImpaired) []subdlSubtitle {
+	type scored struct {
+		sub   subdlSubtitle
+		score float64
+	}
+	var candidates []scored
+	for _, sub := range subs {
+		if video.IsEpisode() && !sub.FullSeason && sub.Episode != 0 && sub.Episode != video.Episode {
+			continue
+		}
+		if !hi.Accepts(sub.HI) {
+			continue
+		}
+		release := ParseRelease(sub.ReleaseName)
+		if !video.MatchesEpisode(release) {
+			continue
+		}
+		candidates = append(candidates, scored{sub, video.Similarity(release) + hi.Score(sub.HI)})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		// Prefer single episodes over full season packs at equal similarity
+		if candidates[i].score == candidates[j].score {
+			return !candidates[i].sub.FullSeason && candidates[j].sub.FullSeason
+		}
+		return candidates[i].score > candidates[j].score
+	})
+
+	ranked := make([]subdlSubtitle, 0, len(candidates))
+	for _, c := range candidates {
+		logger.INFO.Println("SubDL candidate", c.sub.ReleaseName, "with score", strconv.FormatFloat(c.score, 'f', 2, 64))
+		ranked = append(ranked, c.sub)
+	}
+	return ranked
+}
+
+// subdlDownload downloads the ZIP archive of a subtitle and extracts the file matching the video
+func subdlDownload(sub subdlSubtitle, video Release) (name string, content []byte, err error) {
+	req := fluent.New()
+	req.Get(subdlDownloadURL+sub.URL).
+		SetHeader("User-Agent", subdlUserAgent).
+		InitialInterval(time.Duration(time.Millisecond)).
+		Retry(3)
+	res, err := req.Send()
+	if err != nil {
+		return "", nil, fmt.Errorf("Can't reach SubDL to download the subtitle: %v", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != 200 {
+		return "", nil, fmt.Errorf("SubDL answered with status %v", res.StatusCode)
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(res.Body, maxArchiveSize+1))
+	if err != nil {
+		return "", nil, fmt.Errorf("The archive downloaded from SubDL is corrupted")
+	}
+	if len(data) > maxArchiveSize {
+		return "", nil, errors.New("The archive downloaded from SubDL is too big")
+	}
+	return extractFromZip(data, video)
+}
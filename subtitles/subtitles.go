@@ -3,11 +3,16 @@ package subtitles
 import (
 	"fmt"
 	"os"
+	"path"
+	"path/filepath"
 	"strconv"
 	"strings"
 
+	"github.com/matcornic/subify/common/config"
 	"github.com/matcornic/subify/notif"
-	"github.com/olekukonko/tablewriter"
+	"github.com/matcornic/subify/subtitles/container"
+	"github.com/matcornic/subify/subtitles/format"
+	"github.com/matcornic/subify/subtitles/translit"
 	logger "github.com/spf13/jwalterweatherman"
 )
 
@@ -17,49 +22,31 @@ type Client interface {
 	Upload(subtitlePath string, language Language, videoPath string) error
 	GetName() string
 	GetAliases() []string
+	GetCapabilities() Capabilities
+}
+
+// ForcedDownloader is implemented by the APIs able to search forced subtitles, which only translate the foreign parts of the video
+type ForcedDownloader interface {
+	DownloadForced(videoPath string, language Language) (subtitlePath string, err error)
 }
 
 // Clients is a slice of Client
 type Clients []Client
 
-// DefaultAPIs represents the available APIs
-// Is also used as the default
-var DefaultAPIs = Clients{
-	SubDB(),
-	OpenSubtitles(),
-	Addic7ed(),
-}
-
 // InitAPIs sets the order of APIs search from apiAliases
 // If alias does not exists, it is not included
 func InitAPIs(apiAliases []string) (apis Clients) {
 	for _, alias := range apiAliases {
-		for _, availableAPI := range DefaultAPIs {
-			for _, availableAliases := range availableAPI.GetAliases() {
-				if strings.EqualFold(strings.TrimSpace(alias), strings.TrimSpace(availableAliases)) {
-					apis = append(apis, availableAPI)
-				}
-			}
+		if api := APIs.Find(alias); api != nil {
+			apis = append(apis, api)
 		}
 	}
 	return
 }
 
-// Print prints the clients as nice table
-func (c Clients) Print() {
-	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"Name", "Aliases"})
-	for _, l := range c {
-		values := []string{
-			l.GetName(),                        // Name
-			strings.Join(l.GetAliases(), ", "), // Aliases
-		}
-		table.Append(values)
-	}
-	table.SetAutoWrapText(false)
-	table.SetColWidth(50)
-	table.SetRowLine(true)
-	table.Render() // Send output
+// subtitlePathFor builds the path of a subtitle saved next to the video, with the language in its name
+func subtitlePathFor(videoPath, lang, ext string) string {
+	return videoPath[0:len(videoPath)-len(path.Ext(videoPath))] + "." + lang + ext
 }
 
 //String prints a nice representation of clients
@@ -73,8 +60,28 @@ func (c Clients) String() (s string) {
 	return
 }
 
-// Download the subtitle from the video identified by its path
-func Download(videoPath string, apiAliases []string, languages []string, notify bool) error {
+// Options changes how subtitles are downloaded and saved
+type Options struct {
+	Notify        bool   // Display desktop notifications
+	Format        string // Format to convert the subtitle to once downloaded (srt, vtt...). Empty to keep the original one
+	BOM           bool   // Save the subtitle in UTF-8 with a byte order mark, for players needing it
+	Clean         bool   // Remove the ads and credits at the start and the end of the subtitle
+	Script        string // Script to transliterate the subtitles of languages written in both Cyrillic and Latin. Empty to keep the original one
+	Force         bool   // Download even when the language is already embedded in the video
+	Forced        bool   // Download forced subtitles only, translating the foreign parts of the video
+	CheckLanguage bool   // Reject the downloaded subtitles whose text is in another language
+}
+
+// Download the subtitle from the video identified by its path.
+// The audio rules of the configuration may change the languages to download, or ask for forced subtitles
+func Download(videoPath string, apiAliases []string, languages []string, opts Options) error {
+	languages, opts = applyAudioRules(videoPath, languages, opts)
+	_, err := download(videoPath, apiAliases, languages, opts)
+	return err
+}
+
+// download downloads the subtitle of the first language found, and gives its path
+func download(videoPath string, apiAliases []string, languages []string, opts Options) (string, error) {
 	// APIs to download subtitles.
 	var subtitlePath string
 	var err error
@@ -82,41 +89,125 @@ func Download(videoPath string, apiAliases []string, languages []string, notify
 	// Gets APIs
 	a := InitAPIs(apiAliases)
 	if len(a) == 0 {
-		a = DefaultAPIs
-		logger.WARN.Println("No API has been recognized by Subify. Using default:", DefaultAPIs)
+		a = APIs.BuiltIn()
+		logger.WARN.Println("No API has been recognized by Subify. Using default:", a)
 	} else if len(apiAliases) != len(a) {
 		logger.WARN.Println("Some languages are not recognized. Given:", apiAliases, "Found:", a)
 	}
 
+	// Subtitles from local directories are always preferred to network APIs
+	if len(config.LocalDirs) > 0 {
+		network := Clients{}
+		for _, api := range a {
+			if api.GetName() != Local().Name {
+				network = append(network, api)
+			}
+		}
+		a = append(Clients{Local()}, network...)
+	}
+
+	if opts.Format != "" {
+		if _, err := format.ParseFormat(opts.Format); err != nil {
+			return "", err
+		}
+	}
+	if opts.Script != "" {
+		if _, err := translit.ParseScript(opts.Script); err != nil {
+			return "", err
+		}
+	}
+	hi := configuredHearingImpaired()
+
 	// Check languages
 	l := Languages.GetLanguages(languages)
 	if len(l) == 0 {
 		logger.ERROR.Println("Languages", languages, "are not available. Pick one ore more from the table below :")
-		Languages.Print(false)
-		return fmt.Errorf("No languages is available for given languages : %v", languages)
+		Languages.Print(false, a)
+		return "", fmt.Errorf("No languages is available for given languages : %v", languages)
 	} else if len(languages) != len(l) {
 		logger.WARN.Println("Some languages are not recognized. Given:", languages, "Found:", l.GetDescriptions())
 	}
 
+	// Subtitles embedded in the video don't need to be downloaded
+	var embedded []container.Track
+	if !opts.Force {
+		if tracks, err := container.ReadTracks(videoPath); err == nil {
+			embedded = container.SubtitleTracks(tracks)
+		} else if err != container.ErrUnknownContainer {
+			logger.INFO.Println("Embedded subtitles not read:", err)
+		}
+	}
+
+	kind := ""
+	if opts.Forced {
+		kind = "forced "
+	}
+
 	// Run through languages
 browselang:
 	for i, lang := range l {
-		// Run through different APIs to get the subtitle. Stops when found
-		logger.INFO.Println("===> ("+strconv.Itoa(i+1)+") Searching subtitles for", lang.Description, "language")
-		for j, api := range a {
-			logger.INFO.Println("=> (" + strconv.Itoa(i+1) + "." + strconv.Itoa(j+1) + ") Downloading subtitle with " + api.GetName() + "...")
-			subtitlePath, err = api.Download(videoPath, lang)
-			if err == nil {
-				if notify {
-					notif.SendSubtitleDownloadSuccess(api.GetName())
-				}
-				logger.INFO.Println(lang.Description, "subtitle found and saved to ", subtitlePath)
-				break browselang
+		if t := embeddedTrack(embedded, lang, opts.Forced); t != nil {
+			logger.INFO.Println(lang.Description, kind+"subtitle already embedded in the video (track", strconv.Itoa(t.Number)+"). Use --force to download it anyway")
+			subtitlePath, err = "", nil
+			break browselang
+		}
+		// Chinese subtitles missing in a script are searched in the other one, and converted
+		searches := Langs{lang}
+		if other := chineseCounterpart(lang); other != nil {
+			searches = append(searches, *other)
+		}
+		for k, search := range searches {
+			// Run through different APIs to get the subtitle. Stops when found
+			if k == 0 {
+				logger.INFO.Println("===> ("+strconv.Itoa(i+1)+") Searching "+kind+"subtitles for", lang.Description, "language")
 			} else {
-				logger.INFO.Println("Subtitle not found because :", err.Error())
+				logger.INFO.Println("===> ("+strconv.Itoa(i+1)+") Searching "+kind+"subtitles for", search.Description, "language, to convert them to", lang.Description)
 			}
-			if (j + 1) < len(a) {
-				logger.INFO.Println("Trying with another API...")
+			for j, api := range a {
+				logger.INFO.Println("=> (" + strconv.Itoa(i+1) + "." + strconv.Itoa(j+1) + ") Downloading subtitle with " + api.GetName() + "...")
+				if hi == HearingImpairedRequire && !api.GetCapabilities().HearingImpaired {
+					err = fmt.Errorf("%v does not flag hearing impaired subtitles", api.GetName())
+					logger.INFO.Println("Subtitle not searched because :", err.Error())
+					continue
+				}
+				if opts.Forced {
+					if forced, ok := api.(ForcedDownloader); ok {
+						subtitlePath, err = forced.DownloadForced(videoPath, search)
+					} else {
+						err = fmt.Errorf("%v does not search forced subtitles", api.GetName())
+					}
+				} else {
+					subtitlePath, err = api.Download(videoPath, search)
+				}
+				// Providers sometimes mislabel subtitles, or flag full subtitles as forced ones. Only forced downloads
+				// are checked, as full subtitles with few dialogues look like forced ones
+				if err == nil {
+					if opts.Forced {
+						err = checkForced(subtitlePath)
+					}
+					if err == nil && opts.CheckLanguage {
+						err = checkLanguage(subtitlePath, search)
+					}
+					if err != nil {
+						_ = os.Remove(subtitlePath)
+					}
+				}
+				if err == nil {
+					if search.ID != lang.ID {
+						subtitlePath = relabelConverted(videoPath, subtitlePath, lang, opts.Forced)
+					}
+					subtitlePath = postProcess(subtitlePath, lang, opts)
+					if opts.Notify {
+						notif.SendSubtitleDownloadSuccess(api.GetName())
+					}
+					logger.INFO.Println(lang.Description, "subtitle found and saved to ", subtitlePath)
+					break browselang
+				} else {
+					logger.INFO.Println("Subtitle not found because :", err.Error())
+				}
+				if (j + 1) < len(a) {
+					logger.INFO.Println("Trying with another API...")
+				}
 			}
 		}
 		if err != nil {
@@ -128,11 +219,68 @@ browselang:
 	}
 
 	if err != nil {
-		if notify {
+		if opts.Notify {
 			notif.SendSubtitleCouldNotBeDownloaded(a.String())
 		}
-		return fmt.Errorf("No %v subtitle found, even after searching in all APIs (%v)", strings.Join(l.GetDescriptions(), ", nor "), a.String())
+		return "", fmt.Errorf("No %v%v subtitle found, even after searching in all APIs (%v)", kind, strings.Join(l.GetDescriptions(), ", nor "), a.String())
+	}
+
+	return subtitlePath, nil
+}
+
+// DownloadDual downloads the subtitles of the video in two languages and merges them, to display both at once.
+// The merged subtitle is an ASS file with the first language at the bottom and the second one at the top,
+// or has stacked lines when another format is asked. The subtitles of each language are kept too
+func DownloadDual(videoPath string, apiAliases []string, languages []string, opts Options) error {
+	if len(languages) != 2 {
+		return fmt.Errorf("Two languages are needed to merge subtitles, like en,fr. Given: %v", languages)
+	}
+	to := format.ASS
+	if opts.Format != "" {
+		f, err := format.ParseFormat(opts.Format)
+		if err != nil {
+			return err
+		}
+		to = f
 	}
 
+	// Embedded subtitles can't be merged, hence both languages are downloaded
+	single := opts
+	single.Format, single.BOM, single.Force = "", false, true
+	var paths []string
+	for _, l := range languages {
+		subtitlePath, err := download(videoPath, apiAliases, []string{l}, single)
+		if err != nil {
+			return err
+		}
+		paths = append(paths, subtitlePath)
+	}
+
+	mergedPath := MergedPath(paths[0], paths[1], to)
+	if err := format.MergeFiles(paths[0], paths[1], mergedPath, to, format.Options{}); err != nil {
+		return fmt.Errorf("Can't merge the subtitles because of : %v", err)
+	}
+	if opts.BOM {
+		if _, err := NormalizeEncoding(mergedPath, nil, true); err != nil {
+			logger.WARN.Println("Byte order mark not added:", err)
+		}
+	}
+	logger.INFO.Println("Subtitles merged and saved to", mergedPath)
 	return nil
 }
+
+// MergedPath gives the path of two merged subtitles, next to the primary one, with both languages in its name
+// (Movie.en-fr.ass). Subtitles without languages in their name give Movie.merged.ass
+func MergedPath(primary, secondary string, to format.Format) string {
+	base := strings.TrimSuffix(primary, filepath.Ext(primary))
+	p, s := LanguageFromPath(primary), LanguageFromPath(secondary)
+	if p == nil || s == nil {
+		return base + ".merged" + to.Extension()
+	}
+	// The language of the primary subtitle is replaced by both languages
+	ext := filepath.Ext(base)
+	if l := Languages.GetLanguage(strings.TrimPrefix(ext, ".")); l != nil && l.ID == p.ID {
+		base = strings.TrimSuffix(base, ext)
+	}
+	return base + "." + p.Tag + "-" + s.Tag + to.Extension()
+}
@@ -3,6 +3,7 @@ package subtitles
 import (
 	"testing"
 
+	"github.com/matcornic/subify/subtitles/format"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -19,3 +20,14 @@ func TestInitAPIsShouldExistsWithOneThatDoesNotExist(t *testing.T) {
 	assert.Equal(t, apis[0].GetName(), "SubDB", "Should be SubDB")
 	assert.Equal(t, apis[1].GetName(), "OpenSubtitles", "Should be OpenSubtitles")
 }
+
+func TestRegistryShouldFindBuiltInAPIs(t *testing.T) {
+	assert.Equal(t, "Addic7ed", APIs.Find(" AD7 ").GetName())
+	assert.Nil(t, APIs.Find("dontexist"))
+	assert.Equal(t, "Local", APIs.BuiltIn()[0].GetName(), "Local should be searched first")
+}
+
+func TestMergedPathShouldNameBothLanguages(t *testing.T) {
+	assert.Equal(t, "/videos/Movie.en-fr.ass", MergedPath("/videos/Movie.eng.srt", "/videos/Movie.fr.srt", format.ASS))
+	assert.Equal(t, "/videos/Movie.merged.srt", MergedPath("/videos/Movie.srt", "/videos/Movie.fr.srt", format.SRT))
+}
@@ -0,0 +1,284 @@
+// Package synchro finds the timing corrections of subtitles, from a reference subtitle or from the audio of the video.
+// Corrections are linear transforms: t' = t * Scale + Offset, where Scale fixes the drift and Offset the delay.
+package synchro
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/matcornic/subify/subtitles/format"
+)
+
+// Interval is a time interval, a cue or a speech segment
+type Interval struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// Result is a linear correction of the times of a subtitle: t' = t * Scale + Offset
+type Result struct {
+	Scale   float64
+	Offset  time.Duration
+	Matched int     // Number of target intervals matched to a reference interval
+	Score   float64 // Share of the target duration overlapping the reference once corrected, from 0 to 1
+}
+
+// Cues gives the intervals of the cues of a subtitle
+func Cues(s *format.Subtitle) []Interval {
+	intervals := make([]Interval, 0, len(s.Cues))
+	for _, c := range s.Cues {
+		intervals = append(intervals, Interval{c.Start, c.End})
+	}
+	return intervals
+}
+
+// Apply gives the corrected time
+func (r Result) Apply(t time.Duration) time.Duration {
+	return time.Duration(float64(t)*r.Scale) + r.Offset
+}
+
+// Usual drifts, between the frame rates of the releases: 23.976, 24, 25 and 29.97 fps
+var frameRateScales = []float64{
+	1,
+	25 / 23.976, 23.976 / 25,
+	24 / 23.976, 23.976 / 24,
+	25 / 24.0, 24 / 25.0,
+	29.97 / 23.976, 23.976 / 29.97,
+	30 / 29.97, 29.97 / 30,
+}
+
+const (
+	// voteBin is the precision of the offsets found by vote, before refining
+	voteBin = 100 * time.Millisecond
+	// matchWindow is the maximum distance between the starts of two matched intervals
+	matchWindow = 3 * time.Second
+	// candidates is the number of best voted offsets which are checked
+	candidates = 5
+)
+
+// Align finds the correction of the target intervals matching the best the reference intervals.
+// The offset and the drift are first found by vote, with pairs of cues having similar durations and gaps,
+// then refined by matching the cues in order with dynamic programming and a linear regression.
+func Align(target, reference []Interval) (Result, error) {
+	if len(target) < 2 || len(reference) < 2 {
+		return Result{}, errors.New("At least two cues are needed in both subtitles to align them")
+	}
+	target, reference = sorted(target), sorted(reference)
+
+	best := Result{Score: -1}
+	for _, scale := range frameRateScales {
+		for _, offset := range voteOffsets(target, reference, scale) {
+			r := Result{Scale: scale, Offset: offset}
+			r.Score = overlap(target, reference, r)
+			if r.Score > best.Score {
+				best = r
+			}
+		}
+	}
+	if best.Score <= 0 {
+		return Result{}, errors.New("No similarity found between the subtitles")
+	}
+
+	// Refine with the matched cues: the drift may not be a usual one and the vote is not precise
+	for i := 0; i < 3; i++ {
+		refined, ok := regression(match(target, reference, best))
+		if !ok {
+			break
+		}
+		refined.Score = overlap(target, reference, refined)
+		if refined.Score < best.Score {
+			break
+		}
+		best = refined
+	}
+	best.Matched = len(match(target, reference, best))
+	return best, nil
+}
+
+// voteOffsets gives the most voted offsets for a scale. Each pair of intervals with similar durations,
+// and similar gaps to the next interval, votes for the offset moving one onto the other
+func voteOffsets(target, reference []Interval, scale float64) []time.Duration {
+	votes := map[int64]float64{}
+	for i, t := range target {
+		start := time.Duration(float64(t.Start) * scale)
+		duration := float64(t.End-t.Start) * scale
+		gap := nextGap(target, i) * scale
+		for j, r := range reference {
+			d := similarity(duration, float64(r.End-r.Start))
+			if d == 0 {
+				continue
+			}
+			g := similarity(gap, nextGap(reference, j))
+			votes[int64((r.Start-start)/voteBin)] += d * (0.5 + g)
+		}
+	}
+
+	// Neighbor bins are summed, the right offset is often split between two bins
+	type vote struct {
+		bin   int64
+		score float64
+	}
+	var sortedVotes []vote
+	for bin, score := range votes {
+		sortedVotes = append(sortedVotes, vote{bin, score + (votes[bin-1]+votes[bin+1])/2})
+	}
+	sort.Slice(sortedVotes, func(i, j int) bool {
+		if sortedVotes[i].score == sortedVotes[j].score {
+			return sortedVotes[i].bin < sortedVotes[j].bin
+		}
+		return sortedVotes[i].score > sortedVotes[j].score
+	})
+	var offsets []time.Duration
+	for i := 0; i < len(sortedVotes) && i < candidates; i++ {
+		offsets = append(offsets, time.Duration(sortedVotes[i].bin)*voteBin+voteBin/2)
+	}
+	return offsets
+}
+
+// similarity of two positive values, from 1 when equal to 0 when one is more than 40% off the other
+func similarity(a, b float64) float64 {
+	if a <= 0 || b <= 0 {
+		return 0
+	}
+	ratio := math.Min(a, b) / math.Max(a, b)
+	return math.Max(0, (ratio-0.6)/0.4)
+}
+
+// nextGap gives the gap between an interval and the next one, in nanoseconds
+func nextGap(intervals []Interval, i int) float64 {
+	if i+1 >= len(intervals) {
+		return 0
+	}
+	return float64(intervals[i+1].Start - intervals[i].End)
+}
+
+// overlap gives the share of the corrected target duration overlapping the reference
+func overlap(target, reference []Interval, r Result) float64 {
+	var total, common time.Duration
+	j := 0
+	for _, t := range target {
+		start, end := r.Apply(t.Start), r.Apply(t.End)
+		total += end - start
+		for j < len(reference) && reference[j].End <= start {
+			j++
+		}
+		for k := j; k < len(reference) && reference[k].Start < end; k++ {
+			s, e := reference[k].Start, reference[k].End
+			if s < start {
+				s = start
+			}
+			if e > end {
+				e = end
+			}
+			if e > s {
+				common += e - s
+			}
+		}
+	}
+	if total <= 0 {
+		return 0
+	}
+	return float64(common) / float64(total)
+}
+
+// pair is a target interval matched to a reference interval
+type pair struct {
+	target, reference Interval
+	weight            float64
+}
+
+// match matches the corrected target intervals to the reference intervals, in order, maximizing the
+// sum of the similarities of the pairs (dynamic programming, like a longest common subsequence)
+func match(target, reference []Interval, r Result) []pair {
+	n, m := len(target), len(reference)
+	score := make([][]float64, n+1)
+	for i := range score {
+		score[i] = make([]float64, m+1)
+	}
+	sim := func(i, j int) float64 {
+		start, end := r.Apply(target[i].Start), r.Apply(target[i].End)
+		distance := math.Abs(float64(start - reference[j].Start))
+		if distance >= float64(matchWindow) {
+			return 0
+		}
+		d := similarity(float64(end-start), float64(reference[j].End-reference[j].Start))
+		return (1 - distance/float64(matchWindow)) * (0.5 + d/2)
+	}
+
+	for i := 1; i <= n; i++ {
+		// Only the references near the target can match, the others are skipped
+		low := sort.Search(m, func(j int) bool { return reference[j].Start > r.Apply(target[i-1].Start)-matchWindow })
+		for j := 1; j <= m; j++ {
+			best := math.Max(score[i-1][j], score[i][j-1])
+			if j-1 >= low {
+				if s := sim(i-1, j-1); s > 0 && score[i-1][j-1]+s > best {
+					best = score[i-1][j-1] + s
+				}
+			}
+			score[i][j] = best
+		}
+	}
+
+	var pairs []pair
+	for i, j := n, m; i > 0 && j > 0; {
+		switch {
+		case score[i][j] == score[i-1][j]:
+			i--
+		case score[i][j] == score[i][j-1]:
+			j--
+		default:
+			pairs = append(pairs, pair{target[i-1], reference[j-1], sim(i-1, j-1)})
+			i, j = i-1, j-1
+		}
+	}
+	for i, j := 0, len(pairs)-1; i < j; i, j = i+1, j-1 {
+		pairs[i], pairs[j] = pairs[j], pairs[i]
+	}
+	return pairs
+}
+
+// regression fits the correction moving the target starts onto the reference starts (weighted least squares).
+// Pairs far from the first fit are removed before fitting again
+func regression(pairs []pair) (Result, bool) {
+	fit := func(pairs []pair) (Result, bool) {
+		var sw, sx, sy, sxx, sxy float64
+		for _, p := range pairs {
+			x, y, w := p.target.Start.Seconds(), p.reference.Start.Seconds(), p.weight
+			sw, sx, sy, sxx, sxy = sw+w, sx+w*x, sy+w*y, sxx+w*x*x, sxy+w*x*y
+		}
+		det := sw*sxx - sx*sx
+		if len(pairs) < 2 || det == 0 {
+			return Result{}, false
+		}
+		scale := (sw*sxy - sx*sy) / det
+		offset := (sy - scale*sx) / sw
+		if scale <= 0.5 || scale >= 2 {
+			return Result{}, false
+		}
+		return Result{Scale: scale, Offset: time.Duration(offset * float64(time.Second))}, true
+	}
+
+	r, ok := fit(pairs)
+	if !ok {
+		return r, false
+	}
+	var kept []pair
+	for _, p := range pairs {
+		if d := r.Apply(p.target.Start) - p.reference.Start; d < time.Second && d > -time.Second {
+			kept = append(kept, p)
+		}
+	}
+	if refined, ok := fit(kept); ok {
+		return refined, true
+	}
+	return r, true
+}
+
+// sorted gives the intervals sorted by start
+func sorted(intervals []Interval) []Interval {
+	s := append([]Interval{}, intervals...)
+	sort.SliceStable(s, func(i, j int) bool { return s[i].Start < s[j].Start })
+	return s
+}
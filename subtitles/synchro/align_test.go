@@ -0,0 +1,68 @@
+package synchro
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// dialogue gives intervals looking like the cues of a movie, always the same for a seed
+func dialogue(seed int64, count int) []Interval {
+	rnd := rand.New(rand.NewSource(seed))
+	var intervals []Interval
+	t := 30 * time.Second
+	for i := 0; i < count; i++ {
+		duration := time.Duration(800+rnd.Intn(4000)) * time.Millisecond
+		intervals = append(intervals, Interval{t, t + duration})
+		t += duration + time.Duration(100+rnd.Intn(8000))*time.Millisecond
+	}
+	return intervals
+}
+
+// distort applies the inverse of a correction, drops some intervals and jitters the others
+func distort(reference []Interval, r Result, seed int64) []Interval {
+	rnd := rand.New(rand.NewSource(seed))
+	var target []Interval
+	inverse := func(t time.Duration) time.Duration {
+		return time.Duration(float64(t-r.Offset) / r.Scale)
+	}
+	for _, i := range reference {
+		if rnd.Intn(10) == 0 {
+			continue
+		}
+		jitter := time.Duration(rnd.Intn(200)-100) * time.Millisecond
+		target = append(target, Interval{inverse(i.Start) + jitter, inverse(i.End) + jitter})
+	}
+	return target
+}
+
+func TestAlignShouldFindOffset(t *testing.T) {
+	reference := dialogue(1, 400)
+	target := distort(reference, Result{Scale: 1, Offset: 2300 * time.Millisecond}, 2)
+
+	r, err := Align(target, reference)
+	assert.Nil(t, err)
+	assert.InDelta(t, 1, r.Scale, 0.0005)
+	assert.InDelta(t, float64(2300*time.Millisecond), float64(r.Offset), float64(150*time.Millisecond))
+	assert.True(t, r.Matched > 300, "matched %v", r.Matched)
+	assert.True(t, r.Score > 0.8, "score %v", r.Score)
+}
+
+func TestAlignShouldFindFrameRateDriftAndOffset(t *testing.T) {
+	reference := dialogue(3, 600)
+	target := distort(reference, Result{Scale: 25 / 23.976, Offset: -4 * time.Second}, 4)
+
+	r, err := Align(target, reference)
+	assert.Nil(t, err)
+	assert.InDelta(t, 25/23.976, r.Scale, 0.0005)
+	// The error at the end of the movie stays small
+	end := reference[len(reference)-1].End
+	assert.InDelta(t, float64(end), float64(r.Apply(time.Duration(float64(end+4*time.Second)*23.976/25))), float64(300*time.Millisecond))
+}
+
+func TestAlignShouldFailWithoutCues(t *testing.T) {
+	_, err := Align(nil, dialogue(1, 10))
+	assert.NotNil(t, err)
+}
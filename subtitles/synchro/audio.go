@@ -0,0 +1,204 @@
+package synchro
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/cmplx"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// maxAudioOffset is the largest offset searched between the subtitle and the audio
+const maxAudioOffset = 10 * time.Minute
+
+// Speech finds the speech segments of a WAV file, or of the audio of a video extracted with ffmpeg
+func Speech(path string) ([]Interval, error) {
+	if strings.EqualFold(filepath.Ext(path), ".wav") {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("Can't open the file %v because of : %v", path, err)
+		}
+		defer f.Close()
+		a, err := OpenWAV(f)
+		if err != nil {
+			return nil, err
+		}
+		return DetectSpeech(a)
+	}
+
+	ffmpeg, err := exec.LookPath("ffmpeg")
+	if err != nil {
+		return nil, errors.New("ffmpeg is needed to read the audio of videos, install it or give a WAV file")
+	}
+	// Mono 16 kHz is enough for speech, and keeps the stream small
+	cmd := exec.Command(ffmpeg, "-nostdin", "-loglevel", "error", "-i", path, "-vn", "-ac", "1", "-ar", "16000", "-f", "wav", "-")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	stderr := new(strings.Builder)
+	cmd.Stderr = stderr
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("Can't run ffmpeg because of : %v", err)
+	}
+	a, err := OpenWAV(stdout)
+	var speech []Interval
+	if err == nil {
+		speech, err = DetectSpeech(a)
+	}
+	if err != nil {
+		// ffmpeg would wait forever to write the rest of the audio
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("Can't extract the audio of %v because of : %v %v", path, err, msg)
+		}
+		return nil, err
+	}
+	if waitErr := cmd.Wait(); waitErr != nil {
+		return nil, fmt.Errorf("Can't extract the audio of %v because of : %v %v", path, waitErr, strings.TrimSpace(stderr.String()))
+	}
+	return speech, nil
+}
+
+// AlignAudio finds the correction of the cues lining them up the best with the speech segments.
+// Cues and speech are turned into signals (cue or not, speech or silence) and cross-correlated with FFT,
+// for each usual drift between frame rates. Cues over speech win, cues over silence lose.
+// The best correction is then refined like in Align, the speech segments being the reference
+func AlignAudio(cues, speech []Interval) (Result, error) {
+	if len(cues) == 0 {
+		return Result{}, errors.New("The subtitle has no cue")
+	}
+	if len(speech) == 0 {
+		return Result{}, errors.New("No speech found in the audio")
+	}
+	end := speech[len(speech)-1].End
+	for _, c := range cues {
+		if c.End > end {
+			end = c.End
+		}
+	}
+	end = time.Duration(float64(end)*maxScale()) + maxAudioOffset
+
+	// The size covers both signals, so that the circular correlation has no wrap around
+	size := 1
+	for time.Duration(size)*frameDuration < 2*end {
+		size *= 2
+	}
+
+	// Speech is +1, silence is -1
+	audio := make([]complex128, size)
+	for i := 0; i < int(end/frameDuration); i++ {
+		audio[i] = -1
+	}
+	for _, s := range speech {
+		for i := int(s.Start / frameDuration); i < int(s.End/frameDuration) && i < size; i++ {
+			audio[i] = 1
+		}
+	}
+	fft(audio, false)
+
+	best := Result{Score: math.Inf(-1)}
+	subtitle := make([]complex128, size)
+	maxLag := int(maxAudioOffset / frameDuration)
+	for _, scale := range frameRateScales {
+		for i := range subtitle {
+			subtitle[i] = 0
+		}
+		for _, c := range cues {
+			from, to := int(float64(c.Start)*scale/float64(frameDuration)), int(float64(c.End)*scale/float64(frameDuration))
+			for i := from; i < to && i < size; i++ {
+				subtitle[i] = 1
+			}
+		}
+		fft(subtitle, false)
+		for i := range subtitle {
+			subtitle[i] = cmplx.Conj(subtitle[i]) * audio[i]
+		}
+		fft(subtitle, true)
+
+		// The correlation at lag k is the score of the offset k frames, negative lags are at the end
+		for lag := -maxLag; lag <= maxLag; lag++ {
+			score := real(subtitle[(lag+size)%size])
+			if score > best.Score {
+				best = Result{Scale: scale, Offset: time.Duration(lag) * frameDuration, Score: score}
+			}
+		}
+	}
+
+	// Refine with the cues matched to speech segments: the drift may not be a usual one and frames are not precise
+	cues, speech = sorted(cues), sorted(speech)
+	best.Score = overlap(cues, speech, best)
+	for i := 0; i < 3; i++ {
+		refined, ok := regression(match(cues, speech, best))
+		if !ok {
+			break
+		}
+		refined.Score = overlap(cues, speech, refined)
+		if refined.Score < best.Score {
+			break
+		}
+		best = refined
+	}
+	for _, c := range cues {
+		start, end := best.Apply(c.Start), best.Apply(c.End)
+		for _, s := range speech {
+			if s.Start < end && s.End > start {
+				best.Matched++
+				break
+			}
+		}
+	}
+	return best, nil
+}
+
+// maxScale gives the largest usual drift
+func maxScale() float64 {
+	max := 1.0
+	for _, s := range frameRateScales {
+		max = math.Max(max, s)
+	}
+	return max
+}
+
+// fft computes in place the fast Fourier transform of a signal whose size is a power of 2,
+// or its inverse (normalized)
+func fft(x []complex128, inverse bool) {
+	n := len(x)
+	// Bit reversal permutation
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			x[i], x[j] = x[j], x[i]
+		}
+	}
+	sign := -1.0
+	if inverse {
+		sign = 1
+	}
+	for length := 2; length <= n; length <<= 1 {
+		angle := sign * 2 * math.Pi / float64(length)
+		w := complex(math.Cos(angle), math.Sin(angle))
+		for i := 0; i < n; i += length {
+			wk := complex(1, 0)
+			for k := 0; k < length/2; k++ {
+				u, v := x[i+k], x[i+k+length/2]*wk
+				x[i+k], x[i+k+length/2] = u+v, u-v
+				wk *= w
+			}
+		}
+	}
+	if inverse {
+		for i := range x {
+			x[i] /= complex(float64(n), 0)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package synchro
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// synthesizeWAV builds a 16 bits stereo WAV file with noise, and voice like sounds during the speech intervals
+func synthesizeWAV(speech []Interval, duration time.Duration) []byte {
+	const rate = 8000
+	rnd := rand.New(rand.NewSource(1))
+	count := int(duration.Seconds() * rate)
+	data := new(bytes.Buffer)
+	for i := 0; i < count; i++ {
+		t := time.Duration(i) * time.Second / rate
+		v := rnd.NormFloat64() * 0.003
+		for _, s := range speech {
+			if t >= s.Start && t < s.End {
+				// Harmonics of a 150 Hz voice, with a syllable rhythm
+				x := float64(i) / rate
+				v += (0.3*math.Sin(2*math.Pi*150*x) + 0.2*math.Sin(2*math.Pi*450*x) + 0.1*math.Sin(2*math.Pi*1200*x)) * (0.6 + 0.4*math.Sin(2*math.Pi*4*x))
+				break
+			}
+		}
+		sample := int16(math.Max(-1, math.Min(1, v)) * 32767)
+		_ = binary.Write(data, binary.LittleEndian, [2]int16{sample, sample})
+	}
+
+	wav := new(bytes.Buffer)
+	wav.WriteString("RIFF")
+	_ = binary.Write(wav, binary.LittleEndian, uint32(36+data.Len()))
+	wav.WriteString("WAVEfmt ")
+	_ = binary.Write(wav, binary.LittleEndian, []uint32{16})
+	_ = binary.Write(wav, binary.LittleEndian, []uint16{wavPCM, 2})
+	_ = binary.Write(wav, binary.LittleEndian, []uint32{rate, rate * 4})
+	_ = binary.Write(wav, binary.LittleEndian, []uint16{4, 16})
+	wav.WriteString("data")
+	_ = binary.Write(wav, binary.LittleEndian, uint32(data.Len()))
+	wav.Write(data.Bytes())
+	return wav.Bytes()
+}
+
+func TestDetectSpeechShouldFindVoices(t *testing.T) {
+	speech := []Interval{{2 * time.Second, 4 * time.Second}, {6 * time.Second, 6500 * time.Millisecond}}
+	a, err := OpenWAV(bytes.NewReader(synthesizeWAV(speech, 8*time.Second)))
+	assert.Nil(t, err)
+	assert.Equal(t, 8000, a.SampleRate)
+	assert.Equal(t, 2, a.Channels)
+
+	found, err := DetectSpeech(a)
+	assert.Nil(t, err)
+	if assert.Equal(t, 2, len(found)) {
+		for i := range speech {
+			assert.InDelta(t, float64(speech[i].Start), float64(found[i].Start), float64(60*time.Millisecond))
+			assert.InDelta(t, float64(speech[i].End), float64(found[i].End), float64(60*time.Millisecond))
+		}
+	}
+}
+
+func TestOpenWAVShouldRejectOtherFiles(t *testing.T) {
+	_, err := OpenWAV(bytes.NewReader([]byte("ID3 not a wav file")))
+	assert.NotNil(t, err)
+}
+
+func TestAlignAudioShouldFindOffsetAndDrift(t *testing.T) {
+	speech := dialogue(5, 60)
+	duration := speech[len(speech)-1].End + 5*time.Second
+	a, err := OpenWAV(bytes.NewReader(synthesizeWAV(speech, duration)))
+	assert.Nil(t, err)
+	found, err := DetectSpeech(a)
+	assert.Nil(t, err)
+
+	// Subtitle made for a 25 fps release, 1.5 second early
+	expected := Result{Scale: 25 / 23.976, Offset: 1500 * time.Millisecond}
+	cues := distort(speech, expected, 6)
+	r, err := AlignAudio(cues, found)
+	assert.Nil(t, err)
+	assert.Equal(t, expected.Scale, r.Scale)
+	assert.InDelta(t, float64(expected.Offset), float64(r.Offset), float64(100*time.Millisecond))
+	assert.True(t, r.Score > 0.8, "score %v", r.Score)
+	assert.True(t, r.Matched >= len(cues)*9/10, "matched %v", r.Matched)
+}
+
+func TestAlignAudioShouldRefineUnusualDrifts(t *testing.T) {
+	speech := dialogue(7, 60)
+	duration := speech[len(speech)-1].End + 5*time.Second
+	a, err := OpenWAV(bytes.NewReader(synthesizeWAV(speech, duration)))
+	assert.Nil(t, err)
+	found, err := DetectSpeech(a)
+	assert.Nil(t, err)
+
+	// Drift between no usual frame rates
+	expected := Result{Scale: 1.004, Offset: -2 * time.Second}
+	cues := distort(speech, expected, 8)
+	r, err := AlignAudio(cues, found)
+	assert.Nil(t, err)
+	assert.InDelta(t, expected.Scale, r.Scale, 0.0005)
+	assert.InDelta(t, float64(expected.Offset), float64(r.Offset), float64(150*time.Millisecond))
+	assert.True(t, r.Score > 0.8, "score %v", r.Score)
+}
@@ -0,0 +1,101 @@
+package synchro
+
+import (
+	"io"
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	// frameDuration is the resolution of the voice activity detection
+	frameDuration = 20 * time.Millisecond
+	// minSpeech is the shortest speech segment kept
+	minSpeech = 100 * time.Millisecond
+	// maxPause is the longest pause inside a speech segment
+	maxPause = 200 * time.Millisecond
+)
+
+// DetectSpeech finds the speech segments of an audio stream (voice activity detection).
+// Frames much louder than the noise floor of the audio are taken as speech, after a pre-emphasis
+// filter attenuating the low frequencies of music and noises
+func DetectSpeech(a *Audio) ([]Interval, error) {
+	levels, err := frameLevels(a)
+	if err != nil {
+		return nil, err
+	}
+	if len(levels) == 0 {
+		return nil, nil
+	}
+
+	// The threshold adapts to the recording: between the noise floor and the loud parts
+	sortedLevels := append([]float64{}, levels...)
+	sort.Float64s(sortedLevels)
+	floor := sortedLevels[len(sortedLevels)/10]
+	peak := sortedLevels[len(sortedLevels)*95/100]
+	threshold := floor + math.Max(6, (peak-floor)*0.35)
+
+	var speech []Interval
+	start := -1
+	for i := 0; i <= len(levels); i++ {
+		active := i < len(levels) && levels[i] > threshold
+		switch {
+		case active && start < 0:
+			start = i
+		case !active && start >= 0:
+			speech = append(speech, Interval{time.Duration(start) * frameDuration, time.Duration(i) * frameDuration})
+			start = -1
+		}
+	}
+	return smooth(speech), nil
+}
+
+// frameLevels gives the energy of each frame, in dB
+func frameLevels(a *Audio) ([]float64, error) {
+	frameSize := int(time.Duration(a.SampleRate) * frameDuration / time.Second)
+	if frameSize < 1 {
+		frameSize = 1
+	}
+	var levels []float64
+	buf := make([]float64, frameSize*256)
+	var previous, energy float64
+	count := 0
+	for {
+		n, err := a.Read(buf)
+		for _, s := range buf[:n] {
+			filtered := s - 0.97*previous
+			previous = s
+			energy += filtered * filtered
+			count++
+			if count == frameSize {
+				levels = append(levels, 10*math.Log10(energy/float64(count)+1e-10))
+				energy, count = 0, 0
+			}
+		}
+		if err == io.EOF {
+			return levels, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// smooth merges the segments separated by short pauses and removes the too short ones
+func smooth(segments []Interval) []Interval {
+	var merged []Interval
+	for _, s := range segments {
+		if len(merged) > 0 && s.Start-merged[len(merged)-1].End <= maxPause {
+			merged[len(merged)-1].End = s.End
+			continue
+		}
+		merged = append(merged, s)
+	}
+	var kept []Interval
+	for _, s := range merged {
+		if s.End-s.Start >= minSpeech {
+			kept = append(kept, s)
+		}
+	}
+	return kept
+}
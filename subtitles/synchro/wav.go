@@ -0,0 +1,138 @@
+package synchro
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+)
+
+// WAV formats
+const (
+	wavPCM        = 1
+	wavFloat      = 3
+	wavExtensible = 0xFFFE
+)
+
+// Audio reads the samples of a WAV stream, mixed down to mono
+type Audio struct {
+	SampleRate int
+	Channels   int
+	bits       int
+	float      bool
+	r          *bufio.Reader
+	remaining  int64 // Bytes left in the data chunk, -1 when unknown (streamed by ffmpeg)
+}
+
+// OpenWAV reads the header of a WAV stream, up to its samples
+func OpenWAV(r io.Reader) (*Audio, error) {
+	br := bufio.NewReaderSize(r, 64*1024)
+	header := make([]byte, 12)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return nil, fmt.Errorf("Can't read the WAV header: %v", err)
+	}
+	if string(header[0:4]) != "RIFF" || string(header[8:12]) != "WAVE" {
+		return nil, errors.New("Not a WAV file")
+	}
+
+	a := &Audio{r: br}
+	for {
+		chunk := make([]byte, 8)
+		if _, err := io.ReadFull(br, chunk); err != nil {
+			return nil, errors.New("No audio data in the WAV file")
+		}
+		id, size := string(chunk[0:4]), binary.LittleEndian.Uint32(chunk[4:8])
+		switch id {
+		case "fmt ":
+			body := make([]byte, size)
+			if _, err := io.ReadFull(br, body); err != nil || size < 16 {
+				return nil, errors.New("Invalid WAV format chunk")
+			}
+			format := binary.LittleEndian.Uint16(body[0:2])
+			if format == wavExtensible && size >= 26 {
+				// The real format is the first bytes of the sub format GUID
+				format = binary.LittleEndian.Uint16(body[24:26])
+			}
+			a.Channels = int(binary.LittleEndian.Uint16(body[2:4]))
+			a.SampleRate = int(binary.LittleEndian.Uint32(body[4:8]))
+			a.bits = int(binary.LittleEndian.Uint16(body[14:16]))
+			a.float = format == wavFloat
+			if format != wavPCM && format != wavFloat {
+				return nil, fmt.Errorf("Unsupported WAV format %v, only PCM and float are supported", format)
+			}
+			if a.float && a.bits != 32 && a.bits != 64 || !a.float && (a.bits < 8 || a.bits > 32 || a.bits%8 != 0) {
+				return nil, fmt.Errorf("Unsupported WAV sample size of %v bits", a.bits)
+			}
+			if a.Channels < 1 || a.SampleRate < 1 {
+				return nil, errors.New("Invalid WAV format chunk")
+			}
+		case "data":
+			if a.SampleRate == 0 {
+				return nil, errors.New("WAV data found before its format")
+			}
+			a.remaining = int64(size)
+			if size == 0 || size == 0xFFFFFFFF {
+				a.remaining = -1
+			}
+			return a, nil
+		default:
+			if _, err := io.CopyN(ioutil.Discard, br, int64(size+size%2)); err != nil {
+				return nil, errors.New("No audio data in the WAV file")
+			}
+		}
+	}
+}
+
+// Read reads mono samples, between -1 and 1. It gives io.EOF at the end of the audio
+func (a *Audio) Read(samples []float64) (int, error) {
+	frameSize := a.bits / 8 * a.Channels
+	buf := make([]byte, len(samples)*frameSize)
+	if a.remaining >= 0 && int64(len(buf)) > a.remaining {
+		buf = buf[:a.remaining-a.remaining%int64(frameSize)]
+	}
+	if len(buf) == 0 {
+		return 0, io.EOF
+	}
+	n, err := io.ReadFull(a.r, buf)
+	if err == io.ErrUnexpectedEOF {
+		err = nil
+	}
+	if a.remaining >= 0 {
+		a.remaining -= int64(n)
+	}
+	frames := n / frameSize
+	if frames == 0 {
+		return 0, io.EOF
+	}
+	for i := 0; i < frames; i++ {
+		var sum float64
+		for c := 0; c < a.Channels; c++ {
+			sum += a.sample(buf[i*frameSize+c*a.bits/8:])
+		}
+		samples[i] = sum / float64(a.Channels)
+	}
+	return frames, err
+}
+
+// sample decodes one sample
+func (a *Audio) sample(b []byte) float64 {
+	switch {
+	case a.float && a.bits == 32:
+		return float64(math.Float32frombits(binary.LittleEndian.Uint32(b)))
+	case a.float:
+		return math.Float64frombits(binary.LittleEndian.Uint64(b))
+	case a.bits == 8:
+		// 8 bits samples are unsigned
+		return (float64(b[0]) - 128) / 128
+	case a.bits == 16:
+		return float64(int16(binary.LittleEndian.Uint16(b))) / (1 << 15)
+	case a.bits == 24:
+		v := int32(uint32(b[0])<<8|uint32(b[1])<<16|uint32(b[2])<<24) >> 8
+		return float64(v) / (1 << 23)
+	default:
+		return float64(int32(binary.LittleEndian.Uint32(b))) / (1 << 31)
+	}
+}
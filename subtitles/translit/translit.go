@@ -0,0 +1,257 @@
+// Package translit converts subtitles between the Cyrillic and Latin scripts of the languages written in both,
+// like Serbian and Macedonian. Letters are transliterated one to one, following the official alphabets,
+// and the tags, ASS override blocks and links of the cues are kept as is
+package translit
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/matcornic/subify/subtitles/format"
+)
+
+// Script is a writing system of a language
+type Script string
+
+// Scripts of the languages written in both Cyrillic and Latin
+const (
+	Latin    Script = "latin"
+	Cyrillic Script = "cyrillic"
+)
+
+// ParseScript gives the script from its name or its ISO 15924 code (latn, cyrl)
+func ParseScript(name string) (Script, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "latin", "latn":
+		return Latin, nil
+	case "cyrillic", "cyrl":
+		return Cyrillic, nil
+	}
+	return "", fmt.Errorf("Script %v is unknown. Use latin or cyrillic", name)
+}
+
+// alphabet gives the letters of a language in both scripts
+type alphabet struct {
+	letters map[rune]string // Lower case Cyrillic letters and their Latin transliteration
+	extra   map[string]rune // Other Latin spellings of Cyrillic letters
+	// Starts of words where a Latin digraph is two letters, split by |, like nad|živeti (надживети, not наџивети)
+	exceptions []string
+	latin      map[string]rune // Latin letters and digraphs to Cyrillic, built from letters and extra
+}
+
+var serbian = &alphabet{
+	letters: map[rune]string{
+		'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'ђ': "đ", 'е': "e", 'ж': "ž", 'з': "z", 'и': "i",
+		'ј': "j", 'к': "k", 'л': "l", 'љ': "lj", 'м': "m", 'н': "n", 'њ': "nj", 'о': "o", 'п': "p", 'р': "r",
+		'с': "s", 'т': "t", 'ћ': "ć", 'у': "u", 'ф': "f", 'х': "h", 'ц': "c", 'ч': "č", 'џ': "dž", 'ш': "š",
+	},
+	exceptions: []string{
+		"nad|ž", "pod|ž",
+		"in|jek", "kon|jug", "kon|junk", "van|jezi", "tan|jug",
+	},
+}
+
+var macedonian = &alphabet{
+	letters: map[rune]string{
+		'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'ѓ': "gj", 'е': "e", 'ж': "ž", 'з': "z", 'ѕ': "dz",
+		'и': "i", 'ј': "j", 'к': "k", 'л': "l", 'љ': "lj", 'м': "m", 'н': "n", 'њ': "nj", 'о': "o", 'п': "p",
+		'р': "r", 'с': "s", 'т': "t", 'ќ': "kj", 'у': "u", 'ф': "f", 'х': "h", 'ц': "c", 'ч': "č", 'џ': "dž",
+		'ш': "š",
+	},
+	extra: map[string]rune{"ǵ": 'ѓ', "ḱ": 'ќ'},
+	exceptions: []string{
+		"nad|z", "pod|z", "od|z", "pred|z",
+		"nad|ž", "pod|ž",
+	},
+}
+
+// alphabets are the alphabets of the languages, by the primary subtag of their BCP 47 tag.
+// Bosnian and Montenegrin use the Serbian letters
+var alphabets = map[string]*alphabet{
+	"sr":  serbian,
+	"sh":  serbian,
+	"bs":  serbian,
+	"cnr": serbian,
+	"mk":  macedonian,
+}
+
+func init() {
+	for _, a := range alphabets {
+		if a.latin != nil {
+			continue
+		}
+		a.latin = make(map[string]rune)
+		for c, l := range a.letters {
+			a.latin[l] = c
+		}
+		for l, c := range a.extra {
+			a.latin[l] = c
+		}
+	}
+}
+
+// keptRegexp matches the parts of a text which are not transliterated: tags, ASS override blocks,
+// entities, escaped characters like \N, and links
+var keptRegexp = regexp.MustCompile(`(?i)<[^>]*>|&#?\w+;|\{[^}]*\}|\\[a-z]|\b(https?://|www\.)\S+`)
+
+// alphabetOf gives the alphabet of a language, nil when it is not written in both scripts
+func alphabetOf(lang string) *alphabet {
+	return alphabets[strings.ToLower(strings.SplitN(strings.Replace(lang, "_", "-", -1), "-", 2)[0])]
+}
+
+// Supports tells if a language, given by its BCP 47 tag, can be transliterated
+func Supports(lang string) bool {
+	return alphabetOf(lang) != nil
+}
+
+// Detect gives the script of most of the letters of a text. It is false when the text has no Cyrillic nor Latin letter
+func Detect(text string) (Script, bool) {
+	var latin, cyrillic int
+	for _, r := range format.StripTags(text) {
+		switch {
+		case unicode.Is(unicode.Cyrillic, r):
+			cyrillic++
+		case unicode.Is(unicode.Latin, r):
+			latin++
+		}
+	}
+	if latin == 0 && cyrillic == 0 {
+		return "", false
+	}
+	if cyrillic > latin {
+		return Cyrillic, true
+	}
+	return Latin, true
+}
+
+// Text transliterates a text of a language, given by its BCP 47 tag, to a script
+func Text(text, lang string, to Script) (string, error) {
+	a := alphabetOf(lang)
+	if a == nil {
+		return "", fmt.Errorf("Language %v is not written in both Cyrillic and Latin scripts", lang)
+	}
+	convert := a.toLatin
+	if to == Cyrillic {
+		convert = a.toCyrillic
+	}
+
+	var b strings.Builder
+	start := 0
+	for _, m := range keptRegexp.FindAllStringIndex(text, -1) {
+		b.WriteString(convert(text[start:m[0]]))
+		b.WriteString(text[m[0]:m[1]])
+		start = m[1]
+	}
+	b.WriteString(convert(text[start:]))
+	return b.String(), nil
+}
+
+// Transliterate transliterates the cues of a subtitle to a script, and gives the number of changed cues.
+// Nothing is changed when most of the subtitle is already written in this script
+func Transliterate(s *format.Subtitle, lang string, to Script) (int, error) {
+	if !Supports(lang) {
+		return 0, fmt.Errorf("Language %v is not written in both Cyrillic and Latin scripts", lang)
+	}
+	var all []string
+	for _, c := range s.Cues {
+		all = append(all, c.Text())
+	}
+	if script, ok := Detect(strings.Join(all, "\n")); !ok || script == to {
+		return 0, nil
+	}
+
+	changed := 0
+	for _, c := range s.Cues {
+		if c.MapText(func(text string) string {
+			t, _ := Text(text, lang, to)
+			return t
+		}) {
+			changed++
+		}
+	}
+	return changed, nil
+}
+
+// toLatin transliterates the Cyrillic letters of a text. Capital digraphs (Љ to Lj) are fully in capitals
+// in words written in capitals (ЉУБАВ to LJUBAV)
+func (a *alphabet) toLatin(text string) string {
+	runes := []rune(text)
+	var b strings.Builder
+	for i, r := range runes {
+		l, ok := a.letters[unicode.ToLower(r)]
+		if !ok {
+			b.WriteRune(r)
+			continue
+		}
+		if !unicode.IsUpper(r) {
+			b.WriteString(l)
+			continue
+		}
+		if len([]rune(l)) > 1 && !capitals(runes, i) {
+			first := []rune(l)[0]
+			b.WriteString(string(unicode.ToUpper(first)) + l[len(string(first)):])
+			continue
+		}
+		b.WriteString(strings.ToUpper(l))
+	}
+	return b.String()
+}
+
+// capitals tells if the letter at a position is in a word written in capitals, from the letters around it
+func capitals(runes []rune, i int) bool {
+	if i+1 < len(runes) && unicode.IsLetter(runes[i+1]) {
+		return unicode.IsUpper(runes[i+1])
+	}
+	return i > 0 && unicode.IsUpper(runes[i-1])
+}
+
+// toCyrillic transliterates the Latin letters of a text, digraphs first. The letters without Cyrillic equivalent
+// (q, w, x, y) are kept
+func (a *alphabet) toCyrillic(text string) string {
+	runes := []rune(text)
+	var b strings.Builder
+	wordStart := 0
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if !unicode.IsLetter(r) {
+			wordStart = i + 1
+			b.WriteRune(r)
+			continue
+		}
+		if i+1 < len(runes) {
+			digraph := strings.ToLower(string(runes[i : i+2]))
+			if c, ok := a.latin[digraph]; ok && !a.isException(runes[wordStart:], i+1-wordStart) {
+				b.WriteRune(withCase(c, r))
+				i++
+				continue
+			}
+		}
+		if c, ok := a.latin[string(unicode.ToLower(r))]; ok {
+			b.WriteRune(withCase(c, r))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// isException tells if the digraph after the first letters of a word is written with two letters
+func (a *alphabet) isException(word []rune, split int) bool {
+	w := strings.ToLower(string(word))
+	for _, e := range a.exceptions {
+		if strings.HasPrefix(w, strings.Replace(e, "|", "", 1)) && len([]rune(e[:strings.Index(e, "|")])) == split {
+			return true
+		}
+	}
+	return false
+}
+
+// withCase gives the letter in capital when the original one is
+func withCase(c, original rune) rune {
+	if unicode.IsUpper(original) {
+		return unicode.ToUpper(c)
+	}
+	return c
+}
@@ -0,0 +1,72 @@
+package translit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/matcornic/subify/subtitles/format"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTextShouldTransliterateSerbian(t *testing.T) {
+	for cyrillic, latin := range map[string]string{
+		"Љубав и њена џамија, Ђорђе!": "Ljubav i njena džamija, Đorđe!",
+		"ЉУБАВ ЊЕГОВА":                "LJUBAV NJEGOVA",
+		"Шта ћеш да радиш?":           "Šta ćeš da radiš?",
+	} {
+		text, err := Text(cyrillic, "sr", Latin)
+		assert.Nil(t, err)
+		assert.Equal(t, latin, text)
+		text, err = Text(latin, "sr", Cyrillic)
+		assert.Nil(t, err)
+		assert.Equal(t, cyrillic, text)
+	}
+}
+
+func TestTextShouldKeepDigraphExceptions(t *testing.T) {
+	text, _ := Text("Nadživeće nas. Injekcija je spremna.", "sr-Latn", Cyrillic)
+	assert.Equal(t, "Надживеће нас. Инјекција је спремна.", text)
+	text, _ = Text("Podzemen, kjerka i gjavol. Dzvono.", "mk", Cyrillic)
+	assert.Equal(t, "Подземен, ќерка и ѓавол. Ѕвоно.", text)
+	text, _ = Text("Ѓорѓи и Ќе", "mk", Latin)
+	assert.Equal(t, "Gjorgji i Kje", text)
+}
+
+func TestTextShouldKeepTagsAndLinks(t *testing.T) {
+	text, _ := Text(`<i>Dobro</i> {\an8}jutro\Nsvima, www.titlovi.com`, "sr", Cyrillic)
+	assert.Equal(t, `<i>Добро</i> {\an8}јутро\Nсвима, www.titlovi.com`, text)
+	_, err := Text("Hello", "en", Cyrillic)
+	assert.NotNil(t, err)
+}
+
+func TestTransliterateShouldSkipSubtitlesInScript(t *testing.T) {
+	s := &format.Subtitle{Cues: []*format.Cue{
+		{Index: 1, Start: time.Second, End: 2 * time.Second, Lines: []string{"Где си био?"}},
+		{Index: 2, Start: 3 * time.Second, End: 4 * time.Second, Lines: []string{"На послу.", "OK"}},
+	}}
+	changed, err := Transliterate(s, "sr", Cyrillic)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, changed)
+	assert.Equal(t, "OK", s.Cues[1].Lines[1])
+
+	changed, err = Transliterate(s, "sr", Latin)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, changed)
+	assert.Equal(t, "Gde si bio?", s.Cues[0].Text())
+
+	script, ok := Detect("123 !")
+	assert.False(t, ok)
+	script, ok = Detect("<i>Где</i> si")
+	assert.True(t, ok)
+	assert.Equal(t, Cyrillic, script)
+}
+
+func TestParseScriptShouldAcceptCodes(t *testing.T) {
+	for name, expected := range map[string]Script{"latin": Latin, "Latn": Latin, "cyrillic": Cyrillic, "CYRL": Cyrillic} {
+		script, err := ParseScript(name)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, script)
+	}
+	_, err := ParseScript("greek")
+	assert.NotNil(t, err)
+}
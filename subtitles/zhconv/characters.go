@@ -0,0 +1,130 @@
+package zhconv
+
+// stCharacters are the Simplified characters followed by their usual Traditional character, as used in Taiwan.
+// The characters written with several Traditional ones (发 as 發 or 髮) are given with the most frequent one,
+// the others come from stPhrases
+const stCharacters = `
+讠訁 计計 订訂 讣訃 认認 讥譏 讦訐 讧訌 讨討 让讓 讪訕 讫訖 训訓 议議 讯訊 记記 讲講 讳諱 讴謳 讵詎
+讶訝 讷訥 许許 讹訛 论論 讼訟 讽諷 设設 访訪 诀訣 证證 诂詁 诃訶 评評 诅詛 识識 诈詐 诉訴 诊診 诋詆
+诌謅 词詞 诎詘 诏詔 译譯 诒詒 诓誆 诔誄 试試 诖詿 诗詩 诘詰 诙詼 诚誠 诛誅 诜詵 话話 诞誕 诟詬 诠詮
+诡詭 询詢 诣詣 诤諍 该該 详詳 诧詫 诨諢 诩詡 诫誡 诬誣 语語 诮誚 误誤 诰誥 诱誘 诲誨 诳誑 说說 诵誦
+诶誒 请請 诸諸 诹諏 诺諾 读讀 诼諑 诽誹 课課 诿諉 谀諛 谁誰 谂諗 调調 谄諂 谅諒 谆諄 谇誶 谈談 谊誼
+谋謀 谌諶 谍諜 谎謊 谏諫 谐諧 谑謔 谒謁 谓謂 谔諤 谕諭 谖諼 谗讒 谘諮 谙諳 谚諺 谛諦 谜謎 谝諞 谞諝
+谟謨 谠讜 谡謖 谢謝 谣謠 谤謗 谥謚 谦謙 谧謐 谨謹 谩謾 谪謫 谫譾 谬謬 谭譚 谮譖 谯譙 谰讕 谱譜 谲譎
+谳讞 谴譴 谵譫 谶讖
+钆釓 钇釔 针針 钉釘 钊釗 钋釙 钌釕 钍釷 钎釬 钏釧 钐釤 钒釩 钓釣 钔鍆 钕釹 钗釵 钙鈣 钛鈦 钜鉅 钝鈍
+钞鈔 钟鐘 钠鈉 钡鋇 钢鋼 钣鈑 钤鈐 钥鑰 钦欽 钧鈞 钨鎢 钩鉤 钪鈧 钫鈁 钬鈥 钭鈄 钮鈕 钯鈀 钰鈺 钱錢
+钲鉦 钳鉗 钴鈷 钵缽 钶鈳 钸鈽 钹鈸 钺鉞 钻鑽 钼鉬 钽鉭 钾鉀 钿鈿 铀鈾 铁鐵 铂鉑 铃鈴 铄鑠 铅鉛 铆鉚
+铈鈰 铉鉉 铊鉈 铋鉍 铌鈮 铍鈹 铎鐸 铐銬 铑銠 铒鉺 铕銪 铗鋏 铙鐃 铛鐺 铜銅 铝鋁 铟銦 铠鎧 铡鍘 铢銖
+铣銑 铤鋌 铥銩 铧鏵 铨銓 铩鎩 铪鉿 铫銚 铬鉻 铭銘 铮錚 铯銫 铰鉸 铱銥 铲鏟 铳銃 铵銨 银銀 铷銣 铸鑄
+铹鐒 铺鋪 铼錸 铽鋱 链鏈 铿鏗 销銷 锁鎖 锂鋰 锃鋥 锄鋤 锅鍋 锆鋯 锇鋨 锈鏽 锉銼 锊鋝 锋鋒 锌鋅 锍鋶
+锎鐦 锏鐧 锐銳 锑銻 锒鋃 锓鋟 锔鋦 锕錒 锖錆 锗鍺 错錯 锚錨 锛錛 锞錁 锟錕 锡錫 锢錮 锣鑼 锤錘 锥錐
+锦錦 锩錈 锪鍃 锫錇 锬錟 锭錠 键鍵 锯鋸 锰錳 锱錙 锲鍥 锴鍇 锵鏘 锶鍶 锷鍔 锸鍤 锹鍬 锻鍛 锼鎪 锾鍰
+锿鎄 镀鍍 镁鎂 镂鏤 镄鐨 镅鎇 镇鎮 镉鎘 镊鑷 镌鐫 镍鎳 镎鎿 镏鎦 镐鎬 镑鎊 镒鎰 镓鎵 镔鑌 镖鏢 镗鏜
+镘鏝 镙鏍 镛鏞 镜鏡 镝鏑 镞鏃 镟鏇 镡鐔 镢钁 镣鐐 镤鏷 镦鐓 镧鑭 镨鐠 镩鑹 镪鏹 镫鐙 镬鑊 镭鐳 镯鐲
+镰鐮 镱鐿 镲鑔 镳鑣 镶鑲
+纟糹 纠糾 纡紆 红紅 纣紂 纤纖 纥紇 约約 级級 纨紈 纩纊 纪紀 纫紉 纬緯 纭紜 纯純 纰紕 纱紗 纲綱 纳納
+纵縱 纶綸 纷紛 纸紙 纹紋 纺紡 纽紐 纾紓 线線 绀紺 绁紲 绂紱 练練 组組 绅紳 细細 织織 终終 绉縐 绊絆
+绋紼 绌絀 绍紹 绎繹 经經 绐紿 绑綁 绒絨 结結 绔絝 绕繞 绗絎 绘繪 给給 绚絢 绛絳 络絡 绝絕 绞絞 统統
+绠綆 绡綃 绢絹 绣繡 绥綏 绦絛 继繼 绨綈 绩績 绪緒 绫綾 续續 绮綺 绯緋 绰綽 绲緄 绳繩 维維 绵綿 绶綬
+绷繃 绸綢 绺綹 绻綣 综綜 绽綻 绾綰 绿綠 缀綴 缁緇 缂緙 缃緗 缄緘 缅緬 缆纜 缇緹 缈緲 缉緝 缋繢 缌緦
+缍綞 缎緞 缏緶 缑緱 缒縋 缓緩 缔締 缕縷 编編 缗緡 缘緣 缙縉 缚縛 缛縟 缜縝 缝縫 缟縞 缠纏 缡縭 缢縊
+缣縑 缤繽 缥縹 缦縵 缧縲 缨纓 缩縮 缪繆 缫繅 缬纈 缭繚 缮繕 缯繒 缰韁 缱繾 缲繰 缳繯 缴繳
+饥飢 饦飥 饧餳 饨飩 饩餼 饪飪 饫飫 饬飭 饭飯 饮飲 饯餞 饰飾 饱飽 饲飼 饴飴 饵餌 饶饒 饷餉 饺餃 饼餅
+饽餑 饿餓 馁餒 馄餛 馅餡 馆館 馈饋 馊餿 馋饞 馍饃 馏餾 馐饈 馑饉 馒饅 馓饊 馔饌 馕饢
+贝貝 贞貞 负負 贡貢 财財 责責 贤賢 败敗 账賬 货貨 质質 贩販 贪貪 贫貧 贬貶 购購 贮貯 贯貫 贰貳 贱賤
+贲賁 贳貰 贴貼 贵貴 贶貺 贷貸 贸貿 费費 贺賀 贻貽 贼賊 贽贄 贾賈 贿賄 赀貲 赁賃 赂賂 赃贓 资資 赅賅
+赆贐 赇賕 赈賑 赉賚 赊賒 赋賦 赌賭 赍齎 赎贖 赏賞 赐賜 赓賡 赔賠 赕賧 赖賴 赗賵 赘贅 赙賻 赚賺 赛賽
+赜賾 赝贗 赞讚 赠贈 赡贍 赢贏 赣贛
+车車 轧軋 轨軌 轩軒 轫軔 转轉 轭軛 轮輪 软軟 轰轟 轱軲 轲軻 轳轤 轴軸 轵軹 轶軼 轷軤 轸軫 轹轢 轺軺
+轻輕 轼軾 载載 轾輊 轿轎 辀輈 辁輇 辂輅 较較 辄輒 辅輔 辆輛 辇輦 辈輩 辉輝 辊輥 辋輞 辍輟 辎輜 辏輳
+辐輻 辑輯 辒轀 输輸 辔轡 辕轅 辖轄 辗輾 辘轆 辙轍 辚轔 阵陣 连連 莲蓮 琏璉 裤褲 库庫 浑渾 晕暈 挥揮
+荤葷 珲琿 恽惲 郓鄆 运運 军軍
+门門 闩閂 闪閃 闫閆 闭閉 问問 闯闖 闰閏 闱闈 闲閒 闳閎 间間 闵閔 闶閌 闷悶 闸閘 闹鬧 闺閨 闻聞 闼闥
+闽閩 闾閭 闿闓 阀閥 阁閣 阂閡 阃閫 阄鬮 阅閱 阆閬 阈閾 阉閹 阊閶 阋鬩 阌閿 阍閽 阎閻 阏閼 阐闡 阑闌
+阒闃 阔闊 阕闋 阖闔 阗闐 阙闕 阚闞 们們 扪捫 焖燜 润潤 涧澗 搁擱 痫癇 娴嫻 悯憫
+马馬 驭馭 驮馱 驯馴 驰馳 驱驅 驳駁 驴驢 驵駔 驶駛 驷駟 驸駙 驹駒 驺騶 驻駐 驼駝 驽駑 驾駕 驿驛 骀駘
+骁驍 骂罵 骄驕 骅驊 骆駱 骇駭 骈駢 骊驪 骋騁 验驗 骏駿 骐騏 骑騎 骒騍 骓騅 骖驂 骗騙 骘騭 骚騷 骛騖
+骜驁 骝騮 骞騫 骟騸 骠驃 骡騾 骢驄 骣驏 骤驟 骥驥 骧驤 吗嗎 妈媽 码碼 玛瑪 蚂螞 犸獁 杩榪 冯馮 笃篤
+鸟鳥 鸠鳩 鸡雞 鸢鳶 鸣鳴 鸥鷗 鸦鴉 鸨鴇 鸩鴆 鸪鴣 鸫鶇 鸬鸕 鸭鴨 鸯鴦 鸱鴟 鸲鴝 鸳鴛 鸵鴕 鸶鷥 鸷鷙
+鸸鴯 鸹鴰 鸺鵂 鸽鴿 鸾鸞 鸿鴻 鹁鵓 鹂鸝 鹃鵑 鹄鵠 鹅鵝 鹆鵒 鹈鵜 鹉鵡 鹊鵲 鹋鶓 鹌鵪 鹎鵯 鹏鵬 鹑鶉
+鹕鶘 鹗鶚 鹘鶻 鹚鶿 鹜鶩 鹞鷂 鹣鶼 鹤鶴 鹦鸚 鹧鷓 鹩鷯 鹫鷲 鹬鷸 鹭鷺 鹰鷹 鹳鸛 岛島 捣搗 袅裊 枭梟
+坞塢 呜嗚 邬鄔 乌烏
+鱼魚 鱿魷 鲁魯 鲂魴 鲍鮑 鲇鯰 鲈鱸 鲋鮒 鲐鮐 鲑鮭 鲒鮚 鲔鮪 鲕鮞 鲚鱭 鲛鮫 鲜鮮 鲞鯗 鲟鱘 鲠鯁 鲡鱺
+鲢鰱 鲣鰹 鲤鯉 鲥鰣 鲦鰷 鲧鯀 鲨鯊 鲩鯇 鲫鯽 鲭鯖 鲮鯪 鲰鯫 鲱鯡 鲲鯤 鲳鯧 鲵鯢 鲷鯛 鲸鯨 鲻鯔 鲼鱝
+鲽鰈 鳃鰓 鳄鱷 鳅鰍 鳆鰒 鳇鰉 鳊鯿 鳌鰲 鳍鰭 鳎鰨 鳏鰥 鳐鰩 鳔鰾 鳕鱈 鳖鱉 鳗鰻 鳙鱅 鳜鱖 鳝鱔 鳞鱗
+鳟鱒 鳢鱧 苏蘇 噜嚕 撸擼 橹櫓
+页頁 顶頂 顷頃 项項 顺順 须須 顼頊 顽頑 顾顧 顿頓 颀頎 颁頒 颂頌 颃頏 预預 颅顱 领領 颇頗 颈頸 颉頡
+颊頰 颌頜 颍潁 颏頦 颐頤 频頻 颓頹 颔頷 颖穎 颗顆 题題 颚顎 颛顓 颜顏 额額 颞顳 颟顢 颠顛 颡顙 颢顥
+颤顫 颥顬 颦顰 颧顴 烦煩 硕碩 倾傾
+见見 观觀 规規 觅覓 视視 觇覘 览覽 觉覺 觊覬 觋覡 觌覿 觎覦 觏覯 觐覲 觑覷 现現 苋莧 舰艦 砚硯 宽寬
+蚬蜆 岘峴 枧梘 笕筧 搅攪
+风風 飏颺 飐颭 飑颮 飒颯 飓颶 飔颸 飕颼 飘飄 飙飆 疯瘋 枫楓 岚嵐 砜碸
+韦韋 违違 围圍 伟偉 苇葦 玮瑋 炜煒 韧韌 韩韓 韪韙 韬韜 卫衛 帏幃 涠潿
+齿齒 龀齔 龃齟 龄齡 龅齙 龆齠 龇齜 龈齦 龉齬 龊齪 龋齲 龌齷 啮齧
+龙龍 垄壟 拢攏 陇隴 聋聾 笼籠 宠寵 庞龐 珑瓏 胧朧 砻礱 泷瀧 咙嚨 袭襲 龚龔 龛龕 茏蘢
+仑侖 伦倫 抡掄 沦淪 囵圇 仓倉 创創 苍蒼 抢搶 枪槍 沧滄 舱艙 呛嗆 疮瘡 炝熗 跄蹌 戗戧 区區 岖嶇 躯軀
+欧歐 殴毆 呕嘔 怄慪 妪嫗 枢樞 抠摳 瓯甌 单單 弹彈 惮憚 掸撣 禅禪 蝉蟬 婵嬋 郸鄲 殚殫 瘅癉 箪簞 战戰
+东東 冻凍 栋棟 陈陳 胨腖 乐樂 栎櫟 砾礫 烁爍 泺濼 尧堯 挠撓 浇澆 烧燒 晓曉 娆嬈 翘翹 跷蹺 侥僥 硗磽
+峣嶢 桡橈 蛲蟯 专專 传傳 砖磚 啭囀 抟摶 长長 张張 帐帳 胀脹 涨漲 怅悵 枨棖 苌萇 为為 伪偽 沩溈 书書
+击擊 双雙 对對 邓鄧 劝勸 欢歡 戏戲 难難 汉漢 叹嘆 艰艱 权權 仅僅 圣聖 头頭 买買 卖賣 渎瀆 犊犢 椟櫝
+黩黷 实實 宁寧 拧擰 狞獰 柠檸 泞濘 咛嚀 苎苧 边邊 会會 烩燴 荟薈 桧檜 刽劊 哙噲 狯獪 脍膾 亚亞 哑啞
+垩堊 压壓 过過 达達 哒噠 挞撻 鞑韃 迈邁 万萬 与與 屿嶼 欤歟 举舉 誉譽 丰豐 艳豔 开開 无無 抚撫 芜蕪
+妩嫵 庑廡 怃憮 厂廠 广廣 旷曠 矿礦 扩擴 犷獷 邝鄺 圹壙 飞飛 习習 乡鄉 丽麗 俪儷 郦酈 逦邐 严嚴 俨儼
+酽釅 两兩 俩倆 魉魎 满滿 瞒瞞 螨蟎 懑懣 个個 义義 仪儀 蚁蟻 亏虧 酝醞 昙曇 艺藝 呓囈 节節 术術 厉厲
+励勵 砺礪 蛎蠣 灭滅 业業 邺鄴 旧舊 帅帥 归歸 叶葉 号號 电電 叽嘰 玑璣 矶磯 机機 丛叢 尔爾 弥彌 猕獼
+迩邇 称稱 玺璽 处處 务務 雾霧 动動 恸慟 执執 挚摯 垫墊 蛰蟄 絷縶 扫掃 场場 扬揚 杨楊 汤湯 肠腸 疡瘍
+炀煬 旸暘 玚瑒 畅暢 烫燙 殇殤 觞觴 协協 胁脅 夺奪 毕畢 哔嗶 荜蓽 跸蹕 筚篳 岁歲 秽穢 师師 狮獅 蛳螄
+筛篩 尘塵 吓嚇 虫蟲 岂豈 恺愷 凯凱 皑皚 剀剴 垲塏 刚剛 岗崗 则則 侧側 测測 厕廁 恻惻 网網 乔喬 侨僑
+桥橋 娇嬌 荞蕎 峤嶠 伞傘 优優 忧憂 扰擾 犹猶 伤傷 华華 哗嘩 桦樺 晔曄 烨燁 杀殺 众眾 争爭 挣掙 净淨
+静靜 睁睜 筝箏 峥崢 狰猙 庄莊 桩樁 庆慶 刘劉 齐齊 剂劑 济濟 挤擠 脐臍 荠薺 霁霽 跻躋 产產 萨薩 关關
+灯燈 兴興 农農 浓濃 脓膿 侬儂 哝噥 寻尋 浔潯 荨蕁 烬燼 导導 异異 孙孫 荪蓀 逊遜 阴陰 阳陽 阶階 际際
+陆陸 妇婦 寿壽 涛濤 祷禱 焘燾 畴疇 筹籌 踌躊 俦儔 麦麥 进進 远遠 坏壞 怀懷 坟墳 坝壩 块塊 声聲 报報
+拟擬 劳勞 捞撈 唠嘮 崂嶗 痨癆 涝澇 芦蘆 庐廬 炉爐 胪臚 泸瀘 栌櫨 舻艫 极極 医醫 还還 环環 来來 莱萊
+涞淶 徕徠 崃崍 睐睞 坚堅 肾腎 竖豎 紧緊 鉴鑑 揽攬 榄欖 监監 滥濫 蓝藍 篮籃 褴襤 槛檻 时時 埘塒 莳蒔
+县縣 悬懸 园園 员員 圆圓 陨隕 勋勳 郧鄖 损損 殒殞 听聽 吨噸 乱亂 体體 佣傭 彻徹 余餘 邻鄰 龟龜 条條
+涤滌 状狀 亩畝 况況 疗療 应應 这這 灿燦 沟溝 构構 沪滬 穷窮 启啟 补補 灵靈 层層 迟遲 担擔 胆膽 势勢
+热熱 拥擁 拦攔 栏欄 烂爛 兰蘭 拨撥 泼潑 废廢 择擇 泽澤 释釋 苹蘋 茎莖 径徑 劲勁 泾涇 痉痙 胫脛
+烃烴 氢氫 刭剄 柜櫃 杰傑 丧喪 画畫 卧臥 奋奮 态態 斩斬 崭嶄 渐漸 惭慚 暂暫 錾鏨 虏虜 掳擄 国國 罗羅
+萝蘿 逻邏 箩籮 猡玀 椤欏 岭嶺 图圖 侠俠 峡峽 狭狹 挟挾 侦偵 帧幀 桢楨 祯禎 凭憑 肤膚 肿腫 种種 备備
+惫憊 变變 恋戀 蛮蠻 峦巒 弯彎 湾灣 孪孿 栾欒 挛攣 娈孌 脔臠 銮鑾 庙廟 郑鄭 掷擲 踯躑 浅淺 践踐 残殘
+栈棧 盏盞 笺箋 溅濺 泪淚 泻瀉 怜憐 学學 宝寶 审審 婶嬸 帘簾 衬襯 肃肅 萧蕭 箫簫 潇瀟 啸嘯 隶隸 录錄
+陕陝 参參 惨慘 掺摻 渗滲 糁糝 帮幫 挂掛 赵趙 挡擋 档檔 荐薦 带帶 滞滯 茧繭 荣榮 营營 萤螢 莹瑩 萦縈
+荧熒 蓥鎣 药藥 标標 树樹 牵牽 点點 临臨 显顯 湿濕 虾蝦 虽雖 响響 罚罰 选選 俭儉 险險 检檢 剑劍 脸臉
+敛斂 殓殮 捡撿 睑瞼 猃獫 胜勝 脉脈 独獨 烛燭 浊濁 触觸 蚀蝕 将將 奖獎 浆漿 桨槳 酱醬 蒋蔣 亲親 养養
+类類 娄婁 楼樓 搂摟 篓簍 喽嘍 蝼螻 偻僂 屡屢 数數 薮藪 总總 洁潔 洒灑 恼惱 脑腦 宪憲 窃竊 袄襖 垦墾
+恳懇 昼晝 垒壘 蚕蠶 赶趕 盐鹽 壶壺 样樣 础礎 毙斃 虑慮 滤濾 晒曬 罢罷 摆擺 牺犧 敌敵 积積 笔筆 债債
+爱愛 胶膠 离離 篱籬 凉涼 竞競 递遞 涌湧 涩澀 宾賓 滨濱 摈擯 殡殯 膑臏 鬓鬢 槟檳 窍竅 剧劇 琐瑣 唢嗩
+职職 帜幟 炽熾 梦夢 随隨 盘盤 猎獵 痒癢 盖蓋 断斷 兽獸 渊淵 惊驚 惯慣 窑窯 隐隱 瘾癮 琼瓊 趋趨 搀攙
+联聯 椭橢 确確 喷噴 愤憤 遗遺 筑築 惩懲 粪糞 溃潰 窜竄 窝窩 涡渦 蜗蝸 祸禍 剐剮 娲媧 属屬 嘱囑 瞩矚
+摄攝 慑懾 摊攤 滩灘 瘫癱 蓦驀 献獻 碍礙 嗳噯 韵韻 蝇蠅 辞辭 简簡 腻膩 腾騰 叠疊 蔷薔 墙牆 嫱嬙 樯檣
+酿釀 碱鹼 踊踴 稳穩 舆輿 聪聰 蕴蘊 樱櫻 婴嬰 撄攖 嘤嚶 瘿癭 踪蹤 潜潛 澜瀾 懒懶 濑瀨 癞癩 籁籟 辩辯
+辫辮 巅巔 癣癬 嚣囂 躏躪 髅髏 气氣 忾愾 亿億 忆憶 从從 冈岡 队隊 坠墜 办辦 厅廳 币幣 凤鳳 写寫 礼禮
+辽遼 丝絲 巩鞏 厌厭 夹夾 荚莢 蛱蛺 郏郟 浃浹 迁遷 爷爺 杂雜 壮壯 妆妝 忏懺 歼殲 护護 壳殼 吴吳 邮郵
+弃棄 沥瀝 雳靂 呖嚦 枥櫪 疬癧 苈藶 坜壢 汹洶 灾災 枣棗 矾礬 狱獄 届屆 换換 唤喚 焕煥 痪瘓 涣渙
+皱皺 邹鄒 刍芻 雏雛 袜襪 娱娛 虚虛 啰囉 矫矯 偿償 脚腳 猪豬 猫貓 凑湊 减減 盗盜 渔漁 惧懼 决決 内內
+兑兌 税稅 悦悅 脱脫 蜕蛻 温溫 愠慍 别別 强強 黄黃 奥奧 没沒 殁歿 丢丟 儿兒 尸屍 屉屜 厨廚 厦廈 厢廂
+厩廄 厮廝 毡氈 肮骯 芗薌 茔塋 茑蔦 蒇蕆 蛊蠱 衅釁 衔銜 装裝 亵褻 誊謄 跃躍 迹跡 荫蔭 隽雋 奂奐 昵暱
+亘亙 伛傴 伥倀 伧傖 伫佇 佥僉 侩儈 俣俁 偬傯 偾僨 傥儻 傧儐 储儲 傩儺 兖兗 凫鳧 凿鑿 刹剎 匦匭
+匮匱 卺巹 厍厙 叙敘 吣唚 呐吶 呒嘸 呗唄 呙咼 咝噝 哓嘵 哕噦 哜嚌 唛嘜 啧嘖 啬嗇 喾嚳 嗫囁 嘘噓 囱囪
+埙塤 埚堝 堑塹 堕墮 壸壼 够夠 奁奩 妫媯 姗姍 娅婭 婳嫿 嫒嬡 嫔嬪 嬷嬤 寝寢 尴尷 屃屓 屦屨 岽崬 岿巋
+峄嶧 嵘嶸 嵚嶔 嵝嶁 帱幬 帻幘 帼幗 幂冪 廪廩 弪弳 彦彥 怂慫 怆愴 怼懟 怿懌 恒恆 恹懨 悫愨 悭慳 惬愜
+愦憒 懔懍 戆戇 戋戔 戬戩 户戶 抛拋 挜掗 挝撾 挢撟 挦撏 掴摑 掼摜 揿撳 携攜 摅攄 摇搖 遥遙 瑶瑤
+撑撐 撵攆 撷擷 撺攛 擞擻 攒攢 斋齋 斓斕 晋晉 晖暉 暧曖 枞樅 柽檉 栀梔 栅柵 栉櫛 栊櫳 栖棲 桠椏 桤榿
+梼檮 梾棶 棂櫺 椁槨 椠槧 榇櫬 榈櫚 榉櫸 槚檟 槠櫧 橥櫫 橱櫥 橼櫞 檐簷 檩檁 毂轂 毵毿 氇氌 氩氬 氲氳
+污汙 沣灃 沤漚 泶澩 洼窪 浈湞 浍澮 浏瀏 浐滻 浒滸 涂塗 涟漣 渌淥 渍漬 渑澠 溆漵 滗潷 滚滾 滟灩 滠灄
+滢瀅 滦灤 潆瀠 潋瀲 潍濰 潴瀦 濒瀕 灏灝 炖燉 牍牘 牦氂 狈狽 狲猻 猬蝟 獭獺 玱瑲 珐琺 珰璫 瑷璦 璎瓔
+瓒瓚 疖癤 疟瘧 疠癘 痈癰 痖瘂 瘆瘮 瘗瘞 瘘瘻 瘪癟 癫癲 皲皸 眍瞘 眦眥 眬矓 砀碭 砗硨 硁硜 硖硤 硙磑
+碛磧 碜磣 祃禡 祎禕 祢禰 禀稟 禄祿 秃禿 秆稈 秾穠 稆穭 稣穌 穑穡 窎窵 窥窺 窦竇 窭窶 笋筍 笾籩 筜簹
+箓籙 箦簀 箧篋 箨籜 篑簣 簖籪 籴糴 籼秈 粜糶 粝糲 粤粵 粮糧 糇餱 罂罌 罴羆 羁羈 羟羥 耧耬 耸聳 耻恥
+聂聶 聍聹 聩聵 肷膁 脶腡 腌醃 腘膕 腭齶 腼靦 腽膃 臜臢 舣艤 芈羋 苁蓯 茕煢 荙薘 荛蕘 荥滎 荦犖 荩藎
+荬蕒 荭葒 莅蒞 莴萵 莸蕕 莺鶯 莼蓴 葱蔥 蒉蕢 蒌蔞 蓟薊 蓠蘺 蓣蕷 蔹蘞 蔺藺 蔼藹 蕲蘄 藓蘚 虬虯 虮蟣
+虿蠆 蚝蠔 蛏蟶 蛴蠐 蝈蟈 蝎蠍 蝾蠑 螀螿 蟏蠨 衮袞 袆褘 袯襏 裆襠 裈褌 裢褳 裣襝 裥襇 褛褸 觯觶 訚誾
+豮豶 赪赬 趱趲 趸躉 跞躒 跶躂 跹躚 踬躓 蹑躡 蹒蹣 蹰躕 蹿躥 躜躦 迳逕 郐鄶 酦醱 酾釃 陉陘 陧隉 雠讎
+霭靄 靓靚 靥靨 鞒鞽 鞯韉 飨饗 餍饜 髋髖 髌髕 魇魘 鹾鹺 麸麩 黉黌 黡黶 黪黲 黾黽 鼋黿 鼍鼉 鼹鼴 齑齏
+剥剝 册冊 宫宮 横橫 毁毀 拣揀 荆荊 凛凜 卢盧 吕呂 侣侶 却卻 删刪 锨鍁 哟喲 咏詠 匀勻 兹茲 侪儕 垆壚 垭埡 莶薟 缵纘 绱鞝
+钚鈈 钷鉕 铖鋮 铘鋣 铞銱 铴鐋 锘鍩 锝鍀 镆鏌 镥鑥 鹇鷴 鹛鶥 鹨鷚 鹪鷦 鹱鸌 鲅鮁 鲆鮃 鲎鱟 鲴鯝 鲺鯴 鳋鰠 鳓鰳 鳘鰵
+么麼 几幾 云雲 于於 后後 里裡 发發 干幹 历歷 复復 松鬆 划劃 冲衝 准準 尽盡 汇匯 团團 坛壇 当當 采採
+扑撲 仆僕 咸鹹 斗鬥 凶兇 伙夥 丑醜 签簽 脏髒 游遊 恶惡 价價 获獲 占佔 厘釐 舍捨 尝嘗 愿願 适適 腊臘
+蜡蠟 荡蕩 卤滷 据據 杠槓 烟煙 痴癡 着著 炼煉 并並 党黨 郁鬱 夸誇
+`
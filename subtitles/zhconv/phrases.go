@@ -0,0 +1,103 @@
+package zhconv
+
+// stPhrases are the Simplified words whose Traditional form is not given by their characters alone,
+// as Simplified:Traditional. Some words only keep a character from a longer phrase (这只是, not 這隻是)
+const stPhrases = `
+头发:頭髮 理发:理髮 发型:髮型 白发:白髮 金发:金髮 长发:長髮 短发:短髮 黑发:黑髮 卷发:捲髮 假发:假髮
+发夹:髮夾 发胶:髮膠 发廊:髮廊 发丝:髮絲 染发:染髮 剪发:剪髮 秀发:秀髮 毛发:毛髮 发际:髮際 一发千钧:一髮千鈞
+干净:乾淨 干杯:乾杯 干燥:乾燥 饼干:餅乾 干脆:乾脆 干旱:乾旱 干枯:乾枯 干涸:乾涸 晒干:曬乾 烘干:烘乾
+擦干:擦乾 吹干:吹乾 干爹:乾爹 干妈:乾媽 干粮:乾糧 干瘪:乾癟 干咳:乾咳 口干:口乾 外强中干:外強中乾 干巴巴:乾巴巴
+干涉:干涉 干扰:干擾 干预:干預 若干:若干 不相干:不相干 相干:相干 干戈:干戈 天干:天干 干支:干支
+皇后:皇后 王后:王后 太后:太后 天后:天后 影后:影后 歌后:歌后 后羿:后羿 皇太后:皇太后
+面条:麵條 面包:麵包 面粉:麵粉 拉面:拉麵 方便面:方便麵 泡面:泡麵 面团:麵糰 面食:麵食 汤面:湯麵 炒面:炒麵
+面馆:麵館 凉面:涼麵 挂面:掛麵 吃面:吃麵 一碗面:一碗麵 牛肉面:牛肉麵 意大利面:義大利麵 面筋:麵筋
+方面:方面 里面:裡面 表面:表面 外面:外面 前面:前面 后面:後面 上面:上面 下面:下面 对面:對面 见面:見面
+全面:全面 正面:正面 当面:當面 地面:地面 一面:一面
+公里:公里 英里:英里 海里:海里 千里:千里 万里:萬里 里程:里程 邻里:鄰里 故里:故里 乡里:鄉里 里长:里長
+哈里:哈里 杰里:傑里 里克:里克 里奇:里奇 里昂:里昂 里约:里約 加里:加里 巴里:巴里
+只有:只有 只是:只是 只要:只要 只能:只能 只好:只好 只得:只得 只管:只管 不只:不只 只不过:只不過
+一只:一隻 两只:兩隻 三只:三隻 四只:四隻 五只:五隻 几只:幾隻 这只:這隻 那只:那隻 哪只:哪隻 每只:每隻
+只身:隻身 只字:隻字 船只:船隻 形单影只:形單影隻 这只是:這只是 那只是:那只是 唯一只:唯一只
+台风:颱風 台球:檯球 柜台:櫃檯 吧台:吧檯 台灯:檯燈 写字台:寫字檯
+关系:關係 没关系:沒關係 联系:聯繫 维系:維繫 系上:繫上 系好:繫好 系着:繫著 系住:繫住 系鞋带:繫鞋帶 系领带:繫領帶
+日历:日曆 农历:農曆 阳历:陽曆 阴历:陰曆 历法:曆法 挂历:掛曆 台历:檯曆 年历:年曆 公历:公曆 月历:月曆
+钟情:鍾情 一见钟情:一見鍾情 钟爱:鍾愛 钟馗:鍾馗
+复杂:複雜 复制:複製 重复:重複 复印:複印 复数:複數 复合:複合 复习:複習 复述:複述 复查:複查 繁复:繁複
+反复:反覆 答复:答覆 回复:回覆 复盖:覆蓋 复苏:復甦 复辟:復辟
+松树:松樹 松鼠:松鼠 松林:松林 松果:松果 松子:松子 松本:松本 松下:松下 松江:松江 青松:青松 松柏:松柏
+划船:划船 划算:划算 划桨:划槳 划不来:划不來 划得来:划得來
+冲洗:沖洗 冲澡:沖澡 冲绳:沖繩 冲泡:沖泡 冲咖啡:沖咖啡 冲茶:沖茶 冲水:沖水 冲马桶:沖馬桶 冲淡:沖淡 冲凉:沖涼
+一出戏:一齣戲 这出戏:這齣戲
+制造:製造 制作:製作 录制:錄製 复制品:複製品 制片:製片 制品:製品 研制:研製 绘制:繪製 编制:編製 监制:監製
+缝制:縫製 特制:特製 自制:自製 定制:訂製 印制:印製 配制:配製 炮制:炮製 试制:試製 仿制:仿製 精制:精製
+自制力:自制力 制造商:製造商 制片人:製片人
+批准:批准 不准:不准 准许:准許 准予:准予
+特征:特徵 象征:象徵 征兆:徵兆 征求:徵求 征收:徵收 征税:徵稅 征婚:徵婚 征集:徵集 征召:徵召 应征:應徵
+人云亦云:人云亦云 茶几:茶几
+尽管:儘管 尽量:儘量 尽快:儘快 尽早:儘早 尽可能:儘可能
+卷入:捲入 席卷:席捲 龙卷风:龍捲風 卷起:捲起 卷烟:捲菸 卷尺:捲尺 卷心菜:捲心菜 春卷:春捲 花卷:花捲
+词汇:詞彙 汇报:彙報 汇编:彙編 汇集:彙集 汇总:彙總
+饭团:飯糰 酒坛:酒罈 叮当:叮噹
+风采:風采 神采:神采 兴高采烈:興高采烈 文采:文采 无精打采:無精打采
+朴素:樸素 简朴:簡樸 纯朴:純樸 淳朴:淳樸 朴实:樸實
+周末:週末 一周:一週 上周:上週 下周:下週 本周:本週 每周:每週 周年:週年 周一:週一 周二:週二 周三:週三
+周四:週四 周五:週五 周六:週六 周日:週日 周刊:週刊 周报:週報 周期:週期 两周:兩週 几周:幾週 这周:這週
+秋千:鞦韆
+北斗:北斗 烟斗:菸斗 漏斗:漏斗 熨斗:熨斗 斗篷:斗篷 车载斗量:車載斗量 斗胆:斗膽 星斗:星斗 翻筋斗:翻筋斗
+吉凶:吉凶 凶兆:凶兆 凶宅:凶宅 凶多吉少:凶多吉少
+借口:藉口 凭借:憑藉 借此:藉此 借着:藉著 借以:藉以 借故:藉故
+伙食:伙食 家伙:傢伙 伙房:伙房
+小丑:小丑 丑角:丑角 丑时:丑時
+折叠:摺疊 折纸:摺紙 折扇:摺扇 存折:存摺
+标签:標籤 书签:書籤 抽签:抽籤 签子:籤子 牙签:牙籤 求签:求籤
+心脏:心臟 肝脏:肝臟 肾脏:腎臟 内脏:內臟 脏器:臟器 五脏:五臟 脾脏:脾臟 肺脏:肺臟 胰脏:胰臟
+苏醒:甦醒 呼吁:呼籲 吁请:籲請
+规范:規範 范围:範圍 模范:模範 示范:示範 防范:防範 典范:典範 范畴:範疇 范例:範例 范本:範本 师范:師範
+手表:手錶 表带:錶帶 钟表:鐘錶 怀表:懷錶 秒表:秒錶 腕表:腕錶 电表:電錶 水表:水錶 表盘:錶盤
+游泳:游泳 上游:上游 下游:下游 游水:游水 中游:中游 游泳池:游泳池 力争上游:力爭上游 游鱼:游魚
+蒙蒙:濛濛 迷蒙:迷濛 蒙骗:矇騙 蒙混:矇混 瞎蒙:瞎矇 蒙眼:矇眼 蒙古:蒙古 内蒙古:內蒙古 蒙受:蒙受
+沈阳:瀋陽 谷物:穀物 稻谷:稻穀 五谷:五穀 谷子:穀子 谷仓:穀倉 谷类:穀類
+精致:精緻 细致:細緻 别致:別緻 雅致:雅緻 标致:標緻
+恶心:噁心 胡子:鬍子 胡须:鬍鬚 胡同:衚衕 络腮胡:絡腮鬍 刮胡子:刮鬍子 八字胡:八字鬍
+生姜:生薑 姜汁:薑汁 姜片:薑片 姜茶:薑茶 老姜:老薑
+巡回:巡迴 轮回:輪迴 回旋:迴旋 回响:迴響 迂回:迂迴 回廊:迴廊 回避:迴避 回荡:迴盪 来回:來回 回纹针:迴紋針
+犯困:犯睏 收获:收穫 防御:防禦 御寒:禦寒 抵御:抵禦 开辟:開闢 辟谣:闢謠
+了解:瞭解 为了:為了 一目了然:一目瞭然 了望:瞭望
+占卜:占卜 占星:占星 向导:嚮導 向往:嚮往
+拜托:拜託 委托:委託 托付:託付 寄托:寄託 推托:推託 托人:託人 托福:託福 信托:信託 嘱托:囑託 托梦:託夢
+杂志:雜誌 标志:標誌 日志:日誌 志哀:誌哀 墓志铭:墓誌銘
+老板:老闆 症结:癥結
+宿舍:宿舍 舍弟:舍弟 寒舍:寒舍 校舍:校舍 房舍:房舍 左邻右舍:左鄰右舍 猪舍:豬舍 舍下:舍下
+尝尝:嚐嚐 品尝:品嚐 尝鲜:嚐鮮 尝一尝:嚐一嚐
+动荡:動盪 震荡:震盪 摇荡:搖盪 飘荡:飄盪 激荡:激盪 荡漾:盪漾 扫荡:掃蕩
+家具:傢俱 咽下:嚥下 吞咽:吞嚥 咽气:嚥氣 狼吞虎咽:狼吞虎嚥
+克星:剋星 相克:相剋 克扣:剋扣 刮风:颳風
+强奸:強姦 通奸:通姦 奸淫:姦淫 诬蔑:誣衊 污蔑:汙衊
+香烟:香菸 抽烟:抽菸 戒烟:戒菸 烟草:菸草 烟民:菸民 吸烟:吸菸 烟瘾:菸癮 烟头:菸頭 烟灰缸:菸灰缸 烟盒:菸盒
+公布:公佈 宣布:宣佈 分布:分佈 布置:佈置 布局:佈局 遍布:遍佈 密布:密佈 散布:散佈 布满:佈滿 发布:發佈
+锻炼:鍛鍊 修炼:修鍊 冶炼:冶煉 提炼:提煉
+注册:註冊 注定:註定 注解:註解 注释:註釋 批注:批註 备注:備註 注明:註明 附注:附註 标注:標註 脚注:腳註
+念书:唸書 念经:唸經 念叨:唸叨
+合并:合併 兼并:兼併 吞并:吞併 并购:併購 并入:併入 并发症:併發症
+别扭:彆扭 饥荒:饑荒 饥馑:饑饉
+赞成:贊成 赞助:贊助 赞同:贊同 赞许:贊許
+浓郁:濃郁 馥郁:馥郁
+夸克:夸克 夸父:夸父
+于谦:于謙
+`
+
+// tsCharacters are the Traditional characters not given by stCharacters, followed by their Simplified character
+const tsCharacters = `
+髮发 麵面 麪面 隻只 颱台 臺台 檯台 係系 繫系 曆历 鍾钟 複复 齣出 製制 徵征 儘尽 捲卷 彙汇 糰团 罈坛
+噹当 樸朴 週周 鞦秋 韆千 藉借 傢家 摺折 籤签 臟脏 甦苏 籲吁 範范 錶表 矇蒙 濛蒙 瀋沈 穀谷 緻致 噁恶
+縴纤 鬚须 鬍胡 衚胡 衕同 薑姜 迴回 睏困 穫获 禦御 闢辟 瞭了 嚮向 託托 誌志 癥症 闆板 嚐尝 盪荡 嚥咽
+剋克 颳刮 鹵卤 姦奸 衊蔑 菸烟 佈布 註注 唸念 併并 彆别 饑饥 贊赞 乾干 裏里 爲为 衆众 僞伪 啓启 鷄鸡
+綫线 羣群 峯峰 牀床 溼湿 説说 鍊炼 沖冲
+`
+
+// tsPhrases are the Traditional words whose Simplified form is not given by their characters alone
+const tsPhrases = `
+著名:著名 著作:著作 顯著:显著 著稱:著称 土著:土著 卓著:卓著 名著:名著 原著:原著 編著:编著 著者:著者
+論著:论著 專著:专著 巨著:巨著 遺著:遗著 著述:著述 乾隆:乾隆 乾坤:乾坤 慰藉:慰藉 狼藉:狼藉 枕藉:枕藉
+傢俱:家具 反覆:反复 答覆:答复 回覆:回复
+`
@@ -0,0 +1,192 @@
+// Package zhconv converts Chinese subtitles between Simplified and Traditional characters, as written in Taiwan.
+// Like OpenCC, the longest known phrase is converted first (头发 to 頭髮, not 頭發), then the characters one by one
+package zhconv
+
+import (
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/matcornic/subify/subtitles/format"
+)
+
+// Script is a way of writing Chinese characters
+type Script string
+
+// Scripts of Chinese, named as their ISO 15924 code
+const (
+	Simplified  Script = "Hans"
+	Traditional Script = "Hant"
+)
+
+// ScriptOf gives the script of a language, given by its BCP 47 tag (zh-Hant, zh-TW). It is false when the tag
+// is not Chinese or does not tell the script
+func ScriptOf(tag string) (Script, bool) {
+	subtags := strings.Split(strings.ToLower(strings.Replace(tag, "_", "-", -1)), "-")
+	if subtags[0] != "zh" {
+		return "", false
+	}
+	for _, s := range subtags[1:] {
+		switch s {
+		case "hans", "cn", "sg", "my":
+			return Simplified, true
+		case "hant", "tw", "hk", "mo":
+			return Traditional, true
+		}
+	}
+	return "", false
+}
+
+// dictionary converts a text to a script
+type dictionary struct {
+	characters map[rune]rune
+	phrases    map[string]string
+	longest    int           // Number of characters of the longest phrase
+	only       map[rune]bool // Characters only used in the script converted from
+}
+
+var (
+	dictionaries     map[Script]*dictionary
+	dictionariesOnce sync.Once
+)
+
+// loadDictionaries builds the dictionaries to both scripts, from the built-in tables
+func loadDictionaries() map[Script]*dictionary {
+	dictionariesOnce.Do(func() {
+		toTraditional := newDictionary()
+		toSimplified := newDictionary()
+		for _, t := range strings.Fields(stCharacters) {
+			s, tr := pair(t)
+			toTraditional.characters[s] = tr
+			toSimplified.characters[tr] = s
+		}
+		for _, t := range strings.Fields(tsCharacters) {
+			tr, s := pair(t)
+			toSimplified.characters[tr] = s
+		}
+		toTraditional.addPhrases(stPhrases)
+		toSimplified.addPhrases(tsPhrases)
+		toTraditional.setOnly()
+		toSimplified.setOnly()
+		dictionaries = map[Script]*dictionary{Traditional: toTraditional, Simplified: toSimplified}
+	})
+	return dictionaries
+}
+
+// newDictionary gives an empty dictionary
+func newDictionary() *dictionary {
+	return &dictionary{characters: map[rune]rune{}, phrases: map[string]string{}, only: map[rune]bool{}}
+}
+
+// pair reads the two characters of an entry of the tables
+func pair(entry string) (rune, rune) {
+	from, size := utf8.DecodeRuneInString(entry)
+	to, _ := utf8.DecodeRuneInString(entry[size:])
+	return from, to
+}
+
+// addPhrases adds the phrases of a table, written as from:to
+func (d *dictionary) addPhrases(table string) {
+	for _, t := range strings.Fields(table) {
+		p := strings.SplitN(t, ":", 2)
+		d.phrases[p[0]] = p[1]
+		if n := utf8.RuneCountInString(p[0]); n > d.longest {
+			d.longest = n
+		}
+	}
+}
+
+// setOnly keeps the characters converted by the dictionary which are never written in the other script
+func (d *dictionary) setOnly() {
+	written := map[rune]bool{}
+	for _, c := range d.characters {
+		written[c] = true
+	}
+	for _, p := range d.phrases {
+		for _, c := range p {
+			written[c] = true
+		}
+	}
+	for c := range d.characters {
+		if !written[c] {
+			d.only[c] = true
+		}
+	}
+}
+
+// convert converts a text, phrases first
+func (d *dictionary) convert(text string) string {
+	runes := []rune(text)
+	var b strings.Builder
+	for i := 0; i < len(runes); {
+		matched := false
+		for n := d.longest; n > 1; n-- {
+			if i+n > len(runes) {
+				continue
+			}
+			if p, ok := d.phrases[string(runes[i:i+n])]; ok {
+				b.WriteString(p)
+				i += n
+				matched = true
+				break
+			}
+		}
+		if matched {
+			continue
+		}
+		if c, ok := d.characters[runes[i]]; ok {
+			b.WriteRune(c)
+		} else {
+			b.WriteRune(runes[i])
+		}
+		i++
+	}
+	return b.String()
+}
+
+// Detect gives the script of a text, from the characters only written in one of them.
+// It is false when the text has none, like texts in other languages or written with characters common to both scripts
+func Detect(text string) (Script, bool) {
+	d := loadDictionaries()
+	var simplified, traditional int
+	for _, r := range text {
+		switch {
+		case d[Traditional].only[r]:
+			simplified++
+		case d[Simplified].only[r]:
+			traditional++
+		}
+	}
+	switch {
+	case simplified == 0 && traditional == 0:
+		return "", false
+	case simplified > traditional:
+		return Simplified, true
+	}
+	return Traditional, true
+}
+
+// Text converts a text to a script
+func Text(text string, to Script) string {
+	return loadDictionaries()[to].convert(text)
+}
+
+// Convert converts the cues of a subtitle to a script, and gives the number of changed cues.
+// Nothing is changed when the subtitle is already written in this script
+func Convert(s *format.Subtitle, to Script) int {
+	var all []string
+	for _, c := range s.Cues {
+		all = append(all, c.Text())
+	}
+	if script, ok := Detect(strings.Join(all, "\n")); !ok || script == to {
+		return 0
+	}
+
+	changed := 0
+	for _, c := range s.Cues {
+		if c.MapText(func(text string) string { return Text(text, to) }) {
+			changed++
+		}
+	}
+	return changed
+}
@@ -0,0 +1,61 @@
+package zhconv
+
+import (
+	"testing"
+	"time"
+
+	"github.com/matcornic/subify/subtitles/format"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTextShouldConvertPhrasesFirst(t *testing.T) {
+	for simplified, traditional := range map[string]string{
+		"你的头发干净吗？":    "你的頭髮乾淨嗎？",
+		"你在这里干什么？":    "你在這裡幹什麼？",
+		"这只是一只猫。":     "這只是一隻貓。",
+		"我们下周一见面。":    "我們下週一見面。",
+		"他发现了一个复杂的问题": "他發現了一個複雜的問題",
+	} {
+		assert.Equal(t, traditional, Text(simplified, Traditional))
+	}
+}
+
+func TestTextShouldConvertBackToSimplified(t *testing.T) {
+	for traditional, simplified := range map[string]string{
+		"你的頭髮乾淨嗎？":    "你的头发干净吗？",
+		"這是一本著名的小說。":  "这是一本著名的小说。",
+		"我們後來回覆了他。":   "我们后来回复了他。",
+		"<i>乾隆皇帝</i>": "<i>乾隆皇帝</i>",
+	} {
+		assert.Equal(t, simplified, Text(traditional, Simplified))
+	}
+}
+
+func TestConvertShouldSkipSubtitlesInScript(t *testing.T) {
+	s := &format.Subtitle{Cues: []*format.Cue{
+		{Index: 1, Start: time.Second, End: 2 * time.Second, Lines: []string{"我们走吧。"}},
+		{Index: 2, Start: 3 * time.Second, End: 4 * time.Second, Lines: []string{"好的", "OK"}},
+	}}
+	assert.Equal(t, 0, Convert(s, Simplified))
+	assert.Equal(t, 1, Convert(s, Traditional))
+	assert.Equal(t, "我們走吧。", s.Cues[0].Text())
+	assert.Equal(t, "OK", s.Cues[1].Lines[1])
+
+	_, ok := Detect("Hello 好的")
+	assert.False(t, ok)
+	script, ok := Detect("這裡")
+	assert.True(t, ok)
+	assert.Equal(t, Traditional, script)
+}
+
+func TestScriptOfShouldReadTags(t *testing.T) {
+	for tag, expected := range map[string]Script{"zh-Hans": Simplified, "zh-CN": Simplified, "zh-Hant": Traditional, "zh_TW": Traditional, "zh-Hant-HK": Traditional} {
+		script, ok := ScriptOf(tag)
+		assert.True(t, ok)
+		assert.Equal(t, expected, script)
+	}
+	for _, tag := range []string{"zh", "zh-x-dual", "ja", "sr-Latn"} {
+		_, ok := ScriptOf(tag)
+		assert.False(t, ok)
+	}
+}